@@ -0,0 +1,924 @@
+// Package parse provides a command-line application to parse the Library of Congress MADS/RDF (`*.both.ndjson`)
+// files for any of the vocabularies defined in the `dataset` package and output CSV-encoded ID and label data.
+package parse
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sfomuseum/go-csvdict"
+	"github.com/sfomuseum/go-libraryofcongress"
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/concordance"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+	"github.com/sfomuseum/go-libraryofcongress/lcc"
+	"github.com/sfomuseum/go-libraryofcongress/walk"
+)
+
+// type RunOptions defines configuration options for the `RunWithOptions` method.
+type RunOptions struct {
+	// Dataset is the `dataset.Dataset` instance describing the vocabulary being parsed.
+	Dataset *dataset.Dataset
+	// URIs is the list of URIs of the files to parse.
+	URIs []string
+	// Format is the format of the files to parse, for example "ndjson" or "marcxml". This is the scheme of the
+	// `walk.Walker` used to read the files. Default is "ndjson".
+	Format string
+	// Writer is the `io.Writer` instance where CSV data is written.
+	Writer io.Writer
+	// IncludeType is a boolean flag indicating that the MADS/RDF type of each record should be included.
+	IncludeType bool
+	// IncludeBroader is a boolean flag indicating that broader pointers should be included.
+	IncludeBroader bool
+	// IncludeClassification is a boolean flag indicating that Library of Congress Classification numbers should be included.
+	IncludeClassification bool
+	// ClassificationRange is an optional class number range used to limit output to records with a class number that falls within it.
+	ClassificationRange string
+	// Concordances is a comma-separated list of external vocabularies to include pointers for.
+	Concordances string
+	// IncludeWikidata is a boolean flag indicating that a single Wikidata pointer should be included, as the -include-wikidata
+	// flag always has. If "wikidata" is also listed in 'Concordances' it is ignored.
+	IncludeWikidata bool
+	// IncludeWorldcat is a boolean flag indicating that a single (FAST) Worldcat pointer should be included, as the -include-worldcat
+	// flag always has. If "worldcat" is also listed in 'Concordances' it is ignored.
+	IncludeWorldcat bool
+	// ConcordanceMatch is the type of external authority matches to include. Valid options are: any, close, exact. Default
+	// is "any" for the vocabularies listed in 'Concordances' and "close" for 'IncludeWikidata' and 'IncludeWorldcat'.
+	ConcordanceMatch string
+	// IncludeLastChange is a boolean flag indicating that the date and reason of the most recent change should be included.
+	IncludeLastChange bool
+	// IncludeStatus is a boolean flag indicating that the status of each record should be included.
+	IncludeStatus bool
+	// Deprecated indicates how deprecated records should be handled. Valid options are: exclude, include, only.
+	Deprecated string
+	// PrimaryOnly is a boolean flag indicating that only the primary authority described by each record should be output.
+	// By default, if only IDs and labels are being output, every authority in a record's graph is output. Any other columns,
+	// or the Edges, Redirects, CheckHierarchy, VariantsOutput, LabelsOutput and ComponentsOutput options, imply PrimaryOnly.
+	PrimaryOnly bool
+	// Redirects is a boolean flag indicating that "deprecated_id,replacement_id" rows should be written rather than record data.
+	Redirects bool
+	// Since is an optional date (YYYY-MM-DD) used to limit output to records which have been changed after it.
+	Since string
+	// Ordered is a boolean flag indicating that records should be output in the order they appear in the files being parsed.
+	Ordered bool
+	// CallbackWorkers is the number of records to process simultaneously. Default (and values less than 1) is 1.
+	CallbackWorkers int
+	// Shards is the number of byte ranges uncompressed NDJSON files are split in to and parsed concurrently. Default (and values less than 1) is 1.
+	Shards int
+	// Checkpoint is an optional path where checkpoints are written so that an interrupted run can be resumed. The
+	// catalog used to de-duplicate records is stored alongside it (with a ".db" extension).
+	Checkpoint string
+	// CheckpointInterval is the number of records to process between checkpoints. Default is 100000.
+	CheckpointInterval int
+	// Resume is a boolean flag indicating that the run should resume from the checkpoint stored in 'Checkpoint'.
+	Resume bool
+	// Tolerant is a boolean flag indicating that records which can not be parsed should be rejected rather than stopping the run.
+	Tolerant bool
+	// Rejects is an optional path where rejected records are written as newline-delimited JSON. It implies 'Tolerant'.
+	Rejects string
+	// MaxErrors is the maximum number of records that can be rejected before the run is stopped. If zero there is no maximum.
+	MaxErrors int
+	// IncludeNameParts is a boolean flag indicating that the constituent parts of name headings should be included.
+	IncludeNameParts bool
+	// IncludeComponents is a boolean flag indicating that the components of pre-coordinated headings should be included.
+	IncludeComponents bool
+	// ComponentsDelimiter is the delimiter used to separate multiple components in the "component_*" columns.
+	ComponentsDelimiter string
+	// ComponentsOutput is an optional path where components are written as a separate CSV file.
+	ComponentsOutput string
+	// Languages is the list of preferred language tags used to select the label for each record.
+	Languages []string
+	// IncludeLanguage is a boolean flag indicating that the language tag and script of each label should be included.
+	IncludeLanguage bool
+	// LabelsOutput is an optional path where all the labels for each record are written as a separate CSV file.
+	LabelsOutput string
+	// IncludeScopeNote is a boolean flag indicating that the scope notes of each record should be included.
+	IncludeScopeNote bool
+	// IncludeVariants is a boolean flag indicating that variant labels should be included.
+	IncludeVariants bool
+	// VariantsDelimiter is the delimiter used to separate multiple variant labels in the "variants" column.
+	VariantsDelimiter string
+	// VariantsOutput is an optional path where variant labels are written as a separate CSV file.
+	VariantsOutput string
+	// Edges is a boolean flag indicating that broader, narrower and related edges should be written rather than record data.
+	Edges bool
+	// CheckHierarchy is a boolean flag indicating that asymmetric broader/narrower relationships should be reported.
+	CheckHierarchy bool
+}
+
+// Run() parses the records in 'ds' using the default flag set.
+func Run(ctx context.Context, ds *dataset.Dataset) error {
+	fs := DefaultFlagSet(ds)
+	return RunWithFlagSet(ctx, fs, ds)
+}
+
+// RunWithFlagSet() parses the records in 'ds' using the flags defined in 'fs'. If 'ds' is nil the vocabulary to parse
+// is derived from the "-dataset" flag.
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet, ds *dataset.Dataset) error {
+
+	fs.Parse(os.Args[1:])
+
+	if ds == nil {
+
+		if dataset_name == "" {
+			return fmt.Errorf("Missing -dataset flag")
+		}
+
+		v, err := dataset.GetDataset(ctx, dataset_name)
+
+		if err != nil {
+			return fmt.Errorf("Invalid -dataset flag, %w", err)
+		}
+
+		ds = v
+
+		lang_set := false
+
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "lang" {
+				lang_set = true
+			}
+		})
+
+		if !lang_set {
+			lang = strings.Join(ds.Languages, ",")
+		}
+	}
+
+	if include_concordances || include_all {
+		include_wikidata = true
+		include_worldcat = true
+	}
+
+	opts := &RunOptions{
+		Dataset:               ds,
+		URIs:                  fs.Args(),
+		Format:                format,
+		Writer:                os.Stdout,
+		IncludeType:           include_type,
+		IncludeBroader:        include_broader,
+		IncludeClassification: include_classification,
+		ClassificationRange:   classification_range,
+		Concordances:          concordances,
+		IncludeWikidata:       include_wikidata,
+		IncludeWorldcat:       include_worldcat,
+		ConcordanceMatch:      concordance_match,
+		IncludeLastChange:     include_last_change,
+		IncludeStatus:         include_status,
+		Deprecated:            deprecated,
+		PrimaryOnly:           primary_only,
+		Redirects:             redirects,
+		Since:                 since,
+		Ordered:               ordered,
+		CallbackWorkers:       callback_workers,
+		Shards:                shards,
+		Checkpoint:            checkpoint,
+		CheckpointInterval:    checkpoint_interval,
+		Resume:                resume,
+		Tolerant:              tolerant,
+		Rejects:               rejects,
+		MaxErrors:             max_errors,
+		IncludeNameParts:      include_name_parts,
+		IncludeComponents:     include_components,
+		ComponentsDelimiter:   components_delimiter,
+		ComponentsOutput:      components_output,
+		Languages:             authority.ParseLanguages(lang),
+		IncludeLanguage:       include_language,
+		LabelsOutput:          labels_output,
+		IncludeScopeNote:      include_scope_note,
+		IncludeVariants:       include_variants,
+		VariantsDelimiter:     variants_delimiter,
+		VariantsOutput:        variants_output,
+		Edges:                 edges,
+		CheckHierarchy:        check_hierarchy,
+	}
+
+	if include_all {
+		opts.IncludeLanguage = true
+		opts.IncludeType = true
+		opts.IncludeBroader = true
+		opts.IncludeClassification = true
+		opts.IncludeNameParts = true
+		opts.IncludeComponents = true
+		opts.IncludeScopeNote = true
+		opts.IncludeVariants = true
+		opts.IncludeLastChange = true
+		opts.IncludeStatus = true
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+// RunWithOptions() parses the records defined by 'opts'.
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	ds := opts.Dataset
+
+	if ds == nil {
+		return fmt.Errorf("Missing dataset")
+	}
+
+	switch opts.ConcordanceMatch {
+	case "", "any", authority.MatchClose, authority.MatchExact:
+		// pass
+	default:
+		return fmt.Errorf("Invalid concordance match option '%s'", opts.ConcordanceMatch)
+	}
+
+	deprecated := opts.Deprecated
+
+	switch deprecated {
+	case "":
+		deprecated = "exclude"
+	case "exclude", "include", "only":
+		// pass
+	default:
+		return fmt.Errorf("Invalid deprecated option '%s'", deprecated)
+	}
+
+	if opts.Edges && opts.Redirects {
+		return fmt.Errorf("The edges and redirects options are mutually exclusive")
+	}
+
+	format := opts.Format
+
+	if format == "" {
+		format = "ndjson"
+	}
+
+	walker_uri := fmt.Sprintf("%s://", format)
+
+	q := url.Values{}
+
+	if opts.Since != "" {
+		q.Set("since", opts.Since)
+	}
+
+	if opts.Ordered {
+		q.Set("ordered", "true")
+	}
+
+	if opts.CallbackWorkers > 1 {
+		q.Set("callback_workers", strconv.Itoa(opts.CallbackWorkers))
+	}
+
+	if opts.Shards > 1 {
+		q.Set("shards", strconv.Itoa(opts.Shards))
+	}
+
+	if opts.Tolerant || opts.Rejects != "" {
+
+		q.Set("tolerant", "true")
+
+		if opts.MaxErrors > 0 {
+			q.Set("max_errors", strconv.Itoa(opts.MaxErrors))
+		}
+	}
+
+	if opts.Resume && opts.Checkpoint == "" {
+		return fmt.Errorf("The resume option requires a checkpoint")
+	}
+
+	if opts.Checkpoint != "" {
+
+		if format != "ndjson" {
+			return fmt.Errorf("Checkpoints are only supported for NDJSON files")
+		}
+
+		q.Set("checkpoint", opts.Checkpoint)
+
+		if opts.CheckpointInterval > 0 {
+			q.Set("checkpoint_interval", strconv.Itoa(opts.CheckpointInterval))
+		}
+
+		if opts.Resume {
+			q.Set("resume", "true")
+		}
+	}
+
+	if len(q) > 0 {
+		walker_uri = fmt.Sprintf("%s?%s", walker_uri, q.Encode())
+	}
+
+	w, err := walk.NewWalker(ctx, walker_uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create walker, %w", err)
+	}
+
+	include_type := opts.IncludeType
+	include_broader := opts.IncludeBroader
+	include_classification := opts.IncludeClassification
+	include_name_parts := opts.IncludeNameParts
+	include_components := opts.IncludeComponents || opts.ComponentsOutput != ""
+	include_language := opts.IncludeLanguage
+	include_scope_note := opts.IncludeScopeNote
+	include_variants := opts.IncludeVariants || opts.VariantsOutput != ""
+	include_last_change := opts.IncludeLastChange
+	include_status := opts.IncludeStatus
+
+	components_output := opts.ComponentsOutput
+	labels_output := opts.LabelsOutput
+	variants_output := opts.VariantsOutput
+	concordance_names := opts.Concordances
+
+	legacy_names := make([]string, 0)
+
+	if opts.IncludeWikidata {
+		legacy_names = append(legacy_names, "wikidata")
+	}
+
+	if opts.IncludeWorldcat {
+		legacy_names = append(legacy_names, "worldcat")
+	}
+
+	fieldnames := []string{
+		"id",
+		"label",
+	}
+
+	if opts.Redirects || opts.Edges {
+
+		fieldnames = []string{
+			"from",
+			"to",
+			"relation",
+		}
+
+		if opts.Redirects {
+
+			fieldnames = []string{
+				"deprecated_id",
+				"replacement_id",
+			}
+
+			deprecated = "only"
+		}
+
+		include_type = false
+		include_broader = false
+		include_classification = false
+		include_name_parts = false
+		include_components = false
+		include_language = false
+		include_scope_note = false
+		include_variants = false
+		include_last_change = false
+		include_status = false
+
+		components_output = ""
+		labels_output = ""
+		variants_output = ""
+		concordance_names = ""
+		legacy_names = []string{}
+	}
+
+	if include_language {
+		fieldnames = append(fieldnames, "label_language", "label_script")
+	}
+
+	if include_type {
+		fieldnames = append(fieldnames, "type")
+	}
+
+	if include_broader {
+		fieldnames = append(fieldnames, "broader")
+	}
+
+	if include_classification {
+		fieldnames = append(fieldnames, "classification")
+	}
+
+	concordance_list, err := concordance.NewConcordances(ctx, concordance_names)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create concordances, %w", err)
+	}
+
+	// The -include-wikidata and -include-worldcat flags predate the -concordances flag so, unless the same vocabulary
+	// has been requested explicitly, their columns are written the way they always have been: see LegacyConcordances
+
+	legacy_concordances := make(map[string]bool)
+
+	for _, name := range legacy_names {
+
+		exists := false
+
+		for _, c := range concordance_list {
+
+			if c.Name() == name {
+				exists = true
+				break
+			}
+		}
+
+		if exists {
+			continue
+		}
+
+		c, err := concordance.NewConcordance(ctx, fmt.Sprintf("%s://", name))
+
+		if err != nil {
+			return fmt.Errorf("Failed to create %s concordance, %w", name, err)
+		}
+
+		concordance_list = append(concordance_list, c)
+		legacy_concordances[c.Name()] = true
+	}
+
+	for _, c := range concordance_list {
+		fieldnames = append(fieldnames, fmt.Sprintf("%s_id", c.Name()))
+	}
+
+	if include_name_parts {
+		fieldnames = append(fieldnames, nameFieldnames...)
+	}
+
+	if include_components && components_output == "" {
+		fieldnames = append(fieldnames, "component_ids", "component_labels", "component_types")
+	}
+
+	if include_scope_note {
+		fieldnames = append(fieldnames, "scope_note")
+	}
+
+	if include_variants && variants_output == "" {
+		fieldnames = append(fieldnames, "variants")
+	}
+
+	if include_last_change {
+		fieldnames = append(fieldnames, "last_change_date", "last_change_reason")
+	}
+
+	if include_status {
+		fieldnames = append(fieldnames, "status")
+	}
+
+	wr := opts.Writer
+
+	if wr == nil {
+		wr = os.Stdout
+	}
+
+	csv_wr, err := csvdict.NewWriter(wr, fieldnames)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create CSV writer, %w", err)
+	}
+
+	// cp is the checkpoint being resumed from, if any, and is used to restore the state of the outputs and catalog
+
+	var cp *walk.Checkpoint
+
+	if opts.Resume {
+
+		v, err := walk.ReadCheckpoint(opts.Checkpoint)
+
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed to read checkpoint, %w", err)
+		}
+
+		cp = v
+	}
+
+	if cp == nil {
+
+		// The header is flushed immediately so that it is included in the size of the output recorded by checkpoints
+
+		csv_wr.WriteHeader()
+
+		err := flushOutput(csv_wr)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write header, %w", err)
+		}
+
+	} else {
+
+		err := truncateOutput(wr, checkpointState(cp, "output"))
+
+		if err != nil {
+			return fmt.Errorf("Failed to restore output, %w", err)
+		}
+	}
+
+	variants_wr, variants_close, err := newOutputWriter(variants_output, []string{"id", "variant", "language"}, checkpointState(cp, "variants_output"))
+
+	if err != nil {
+		return fmt.Errorf("Failed to create variants CSV writer, %w", err)
+	}
+
+	defer variants_close()
+
+	labels_wr, labels_close, err := newOutputWriter(labels_output, []string{"id", "label", "language", "script", "type"}, checkpointState(cp, "labels_output"))
+
+	if err != nil {
+		return fmt.Errorf("Failed to create labels CSV writer, %w", err)
+	}
+
+	defer labels_close()
+
+	components_wr, components_close, err := newOutputWriter(components_output, []string{"id", "position", "component_id", "component_label", "component_type"}, checkpointState(cp, "components_output"))
+
+	if err != nil {
+		return fmt.Errorf("Failed to create components CSV writer, %w", err)
+	}
+
+	defer components_close()
+
+	var rejects *walk.Rejects
+
+	if opts.Tolerant || opts.Rejects != "" {
+
+		var rejects_wr io.Writer
+
+		if opts.Rejects != "" {
+
+			// The offset of the rejects output is recorded in checkpoints by the walker, along with the number of rejected records
+
+			rejects_offset := int64(-1)
+
+			if cp != nil {
+				rejects_offset = cp.RejectsOffset
+			}
+
+			fh, err := openOutput(opts.Rejects, rejects_offset)
+
+			if err != nil {
+				return fmt.Errorf("Failed to open rejects output, %w", err)
+			}
+
+			defer fh.Close()
+
+			rejects_wr = fh
+		}
+
+		rejects = walk.NewRejects(rejects_wr)
+		ctx = walk.ContextWithRejects(ctx, rejects)
+	}
+
+	var class_range *lcc.Range
+
+	if opts.ClassificationRange != "" {
+
+		r, err := lcc.ParseRange(opts.ClassificationRange)
+
+		if err != nil {
+			return fmt.Errorf("Invalid classification range, %w", err)
+		}
+
+		class_range = r
+	}
+
+	var hierarchy *authority.Hierarchy
+
+	if opts.CheckHierarchy {
+		hierarchy = authority.NewHierarchy()
+	}
+
+	concordance_match := opts.ConcordanceMatch
+	legacy_concordance_match := opts.ConcordanceMatch
+
+	if concordance_match == "" {
+		concordance_match = "any"
+		legacy_concordance_match = authority.MatchClose
+	}
+
+	variants_delimiter := opts.VariantsDelimiter
+
+	if variants_delimiter == "" {
+		variants_delimiter = "|"
+	}
+
+	components_delimiter := opts.ComponentsDelimiter
+
+	if components_delimiter == "" {
+		components_delimiter = "|"
+	}
+
+	cb_opts := &walkCallbackOptions{
+		Dataset:                ds,
+		Writer:                 csv_wr,
+		VariantsWriter:         variants_wr,
+		VariantsDelimiter:      variants_delimiter,
+		ComponentsWriter:       components_wr,
+		ComponentsDelimiter:    components_delimiter,
+		Languages:              opts.Languages,
+		LabelsWriter:           labels_wr,
+		Fieldnames:             fieldnames,
+		Concordances:           concordance_list,
+		ConcordanceMatch:       concordance_match,
+		LegacyConcordances:     legacy_concordances,
+		LegacyConcordanceMatch: legacy_concordance_match,
+		Deprecated:             deprecated,
+		ClassificationRange:    class_range,
+		PrimaryOnly:            opts.PrimaryOnly,
+		Redirects:              opts.Redirects,
+		Edges:                  opts.Edges,
+		Hierarchy:              hierarchy,
+	}
+
+	// Checkpointed runs always use a persistent catalog, stored alongside the checkpoint, since records seen
+	// before the checkpoint need to be remembered when the run is resumed
+
+	switch {
+	case opts.Checkpoint != "":
+
+		path, err := filepath.Abs(opts.Checkpoint + ".db")
+
+		if err != nil {
+			return fmt.Errorf("Failed to derive catalog path, %w", err)
+		}
+
+		catalog, err := libraryofcongress.NewCatalog(ctx, "file://"+path)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create catalog, %w", err)
+		}
+
+		defer catalog.Close(ctx)
+
+		// Discard any records stored after the checkpoint was written (or all the records if there is no checkpoint)
+
+		err = catalog.Truncate(ctx, max(checkpointState(cp, "catalog"), 0))
+
+		if err != nil {
+			return fmt.Errorf("Failed to restore catalog, %w", err)
+		}
+
+		cb_opts.Catalog = catalog
+
+	case ds.Catalog:
+
+		catalog, err := libraryofcongress.NewCatalog(ctx, "tmp://")
+
+		if err != nil {
+			return fmt.Errorf("Failed to create catalog, %w", err)
+		}
+
+		defer catalog.Close(ctx)
+
+		cb_opts.Catalog = catalog
+
+	default:
+		cb_opts.Seen = new(sync.Map)
+	}
+
+	if opts.Checkpoint != "" {
+
+		// Checkpoints are written when no callback functions are running so the size of each output, and the
+		// position of the catalog, reflect exactly the records processed before the checkpoint
+
+		outputs := map[string]string{
+			"variants_output":   variants_output,
+			"labels_output":     labels_output,
+			"components_output": components_output,
+		}
+
+		checkpoint_cb := func(ctx context.Context, cp *walk.Checkpoint) error {
+
+			position, err := cb_opts.Catalog.Position(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			cp.State["catalog"] = strconv.FormatInt(position, 10)
+
+			sz, ok := outputSize(wr)
+
+			if ok {
+				cp.State["output"] = strconv.FormatInt(sz, 10)
+			}
+
+			for k, path := range outputs {
+
+				if path == "" {
+					continue
+				}
+
+				info, err := os.Stat(path)
+
+				if err != nil {
+					return fmt.Errorf("Failed to stat %s, %w", path, err)
+				}
+
+				cp.State[k] = strconv.FormatInt(info.Size(), 10)
+			}
+
+			return nil
+		}
+
+		ctx = walk.ContextWithCheckpointCallback(ctx, checkpoint_cb)
+	}
+
+	cb_func := walkCallbackFunc(cb_opts)
+
+	err = w.WalkURIs(ctx, cb_func, opts.URIs...)
+
+	// Rows are flushed as they are written but make sure nothing is left buffered, for example if no rows were written
+
+	flush_err := flushOutput(csv_wr, variants_wr, labels_wr, components_wr)
+
+	if rejects != nil && rejects.Count() > 0 {
+		log.Printf("%d records rejected", rejects.Count())
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to walk %s data, %w", strings.ToUpper(ds.Name), err)
+	}
+
+	if flush_err != nil {
+		return fmt.Errorf("Failed to write output, %w", flush_err)
+	}
+
+	if hierarchy != nil {
+
+		asymmetric := hierarchy.Asymmetric()
+
+		for _, e := range asymmetric {
+			log.Printf("Asymmetric %s relationship: %s lists %s but the inverse is missing", e.Relation, filepath.Base(e.From), filepath.Base(e.To))
+		}
+
+		log.Printf("%d asymmetric broader/narrower relationships found", len(asymmetric))
+	}
+
+	return nil
+}
+
+// newOutputWriter() returns a new `csvdict.Writer` instance, with 'fieldnames' columns, for 'path' and a function to
+// close the underlying file. If 'path' is empty a nil writer (and a no-op function) is returned. If 'size' is zero or
+// more, for example when resuming from a checkpoint, the existing file is truncated to 'size' bytes and appended to;
+// otherwise a new file is created.
+func newOutputWriter(path string, fieldnames []string, size int64) (*csvdict.Writer, func() error, error) {
+
+	no_op := func() error { return nil }
+
+	if path == "" {
+		return nil, no_op, nil
+	}
+
+	fh, err := openOutput(path, size)
+
+	if err != nil {
+		return nil, no_op, err
+	}
+
+	wr, err := csvdict.NewWriter(fh, fieldnames)
+
+	if err != nil {
+		fh.Close()
+		return nil, no_op, fmt.Errorf("Failed to create CSV writer for %s, %w", path, err)
+	}
+
+	if size < 0 {
+
+		wr.WriteHeader()
+
+		err := flushOutput(wr)
+
+		if err != nil {
+			fh.Close()
+			return nil, no_op, fmt.Errorf("Failed to write header for %s, %w", path, err)
+		}
+	}
+
+	return wr, fh.Close, nil
+}
+
+// flushOutput() flushes any buffered data in each of 'writers', ignoring nil instances, and returns the first error
+// reported by any of them.
+func flushOutput(writers ...*csvdict.Writer) error {
+
+	var flush_err error
+
+	for _, wr := range writers {
+
+		if wr == nil {
+			continue
+		}
+
+		wr.Flush()
+
+		err := wr.Error()
+
+		if err != nil && flush_err == nil {
+			flush_err = err
+		}
+	}
+
+	return flush_err
+}
+
+// openOutput() opens 'path' for writing. If 'size' is zero or more, for example when resuming from a checkpoint, the existing
+// file is truncated to 'size' bytes and appended to; otherwise a new file is created.
+func openOutput(path string, size int64) (*os.File, error) {
+
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+
+	if size >= 0 {
+		flags = os.O_RDWR
+	}
+
+	fh, err := os.OpenFile(path, flags, 0644)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open %s for writing, %w", path, err)
+	}
+
+	if size >= 0 {
+
+		err := truncateOutput(fh, size)
+
+		if err != nil {
+			fh.Close()
+			return nil, fmt.Errorf("Failed to restore %s, %w", path, err)
+		}
+	}
+
+	return fh, nil
+}
+
+// outputSize() returns the size of 'wr' and a boolean value indicating whether it could be determined. Only the size
+// of regular files (and not, for example, pipes) can be determined.
+func outputSize(wr io.Writer) (int64, bool) {
+
+	fh, ok := wr.(*os.File)
+
+	if !ok {
+		return 0, false
+	}
+
+	info, err := fh.Stat()
+
+	if err != nil || !info.Mode().IsRegular() {
+		return 0, false
+	}
+
+	return info.Size(), true
+}
+
+// truncateOutput() truncates 'wr' to 'size' bytes, discarding anything written after a checkpoint was created, and
+// positions it so that subsequent writes are appended. If 'size' is less than zero, or the size of 'wr' can not be
+// determined, nothing happens.
+func truncateOutput(wr io.Writer, size int64) error {
+
+	current, ok := outputSize(wr)
+
+	if !ok || size < 0 {
+		return nil
+	}
+
+	if current < size {
+		return fmt.Errorf("Output is smaller (%d bytes) than when the checkpoint was written (%d bytes), output should be appended to the existing file", current, size)
+	}
+
+	fh := wr.(*os.File)
+
+	err := fh.Truncate(size)
+
+	if err != nil {
+		return fmt.Errorf("Failed to truncate output, %w", err)
+	}
+
+	_, err = fh.Seek(size, io.SeekStart)
+
+	if err != nil {
+		return fmt.Errorf("Failed to seek output, %w", err)
+	}
+
+	return nil
+}
+
+// checkpointState() returns the numeric value of 'key' in the state of 'cp' or -1 if 'cp' is nil or the key is not present.
+func checkpointState(cp *walk.Checkpoint, key string) int64 {
+
+	if cp == nil {
+		return -1
+	}
+
+	v, ok := cp.State[key]
+
+	if !ok {
+		return -1
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+
+	if err != nil {
+		return -1
+	}
+
+	return i
+}
@@ -0,0 +1,963 @@
+package parse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sfomuseum/go-csvdict"
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+	"github.com/sfomuseum/go-libraryofcongress/walk"
+)
+
+func TestRunWithOptions(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		URI      string
+		Opts     *RunOptions
+		Expected string
+	}{
+		"lcsh": {
+			URI: "../../fixtures/lcsh.sample.ndjson",
+			Opts: &RunOptions{
+				IncludeBroader:        true,
+				IncludeClassification: true,
+			},
+			Expected: "id,label,broader,classification\nsh85016999,Broadband amplifiers,sh85004652,TK7871.58.B74\nsh2004004999,Śreshṭha family,,\nsh96009999,Arangel Channel (Palau),sh96010001,\n",
+		},
+		"lcgft": {
+			URI: "../../fixtures/lcgft.sample.ndjson",
+			Opts: &RunOptions{
+				IncludeType:     true,
+				IncludeBroader:  true,
+				IncludeVariants: true,
+			},
+			Expected: "id,label,type,broader,variants\ngf2014026339,Fiction,GenreForm,,\ngf2014026092,Detective and mystery fiction,GenreForm,gf2014026339,Mystery fiction@en\n",
+		},
+		"tgm": {
+			URI: "../../fixtures/tgm.sample.ndjson",
+			Opts: &RunOptions{
+				IncludeBroader:   true,
+				IncludeScopeNote: true,
+				Concordances:     "lcsh",
+			},
+			Expected: "id,label,broader,lcsh_id,scope_note\ntgm007721,Photographs,,sh85101206,Use for photographic prints and collections of photographs in general.\ntgm008085,Portrait photographs,tgm007721,sh85104956,For photographs of people. Search also under PORTRAITS.\ntgm008083,Portraits,,,\n",
+		},
+		"lcdgt": {
+			URI:      "../../fixtures/lcgft.sample.ndjson",
+			Opts:     &RunOptions{},
+			Expected: "id,label\n",
+		},
+	}
+
+	for name, test := range tests {
+
+		ds, err := dataset.GetDataset(ctx, name)
+
+		if err != nil {
+			t.Fatalf("Failed to get dataset for %s, %v", name, err)
+		}
+
+		var buf bytes.Buffer
+
+		opts := test.Opts
+		opts.Dataset = ds
+		opts.URIs = []string{test.URI}
+		opts.Languages = ds.Languages
+		opts.Writer = &buf
+		opts.Ordered = true
+
+		err = RunWithOptions(ctx, opts)
+
+		if err != nil {
+			t.Fatalf("Failed to run %s, %v", name, err)
+		}
+
+		out := buf.String()
+
+		if out != test.Expected {
+			t.Fatalf("Unexpected output for %s: '%s' (expected '%s')", name, out, test.Expected)
+		}
+	}
+}
+
+func TestRunWithOptionsGraphNodes(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	// The three LCSH fixtures also describe the broader and narrower headings they reference
+
+	tests := map[bool][]string{
+		false: {"sh85004652", "sh85016999", "sh85038540", "sh2004004999", "sh96009999", "sh96010001"},
+		true:  {"sh85016999", "sh2004004999", "sh96009999"},
+	}
+
+	for primary_only, expected := range tests {
+
+		var buf bytes.Buffer
+
+		opts := &RunOptions{
+			Dataset:     ds,
+			URIs:        []string{"../../fixtures/lcsh.sample.ndjson"},
+			Languages:   ds.Languages,
+			Writer:      &buf,
+			Ordered:     true,
+			PrimaryOnly: primary_only,
+		}
+
+		err := RunWithOptions(ctx, opts)
+
+		if err != nil {
+			t.Fatalf("Failed to run (primary only %t), %v", primary_only, err)
+		}
+
+		rows := strings.Split(strings.TrimSpace(buf.String()), "\n")[1:]
+
+		if len(rows) != len(expected) {
+			t.Fatalf("Unexpected number of rows (primary only %t): %d (expected %d)", primary_only, len(rows), len(expected))
+		}
+
+		for i, row := range rows {
+
+			if !strings.HasPrefix(row, expected[i]+",") {
+				t.Fatalf("Unexpected row %d (primary only %t): %s (expected %s)", i, primary_only, row, expected[i])
+			}
+		}
+	}
+}
+
+func TestRunWithOptionsLegacyConcordances(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	// sh96009999 has two (close) Worldcat pointers. The -include-worldcat flag has always output only the last one.
+
+	tests := map[string]struct {
+		Opts     *RunOptions
+		Expected string
+	}{
+		"include": {
+			Opts:     &RunOptions{IncludeWikidata: true, IncludeWorldcat: true},
+			Expected: "sh96009999,Arangel Channel (Palau),Q31886764,1797194",
+		},
+		"concordances": {
+			Opts:     &RunOptions{Concordances: "wikidata,worldcat"},
+			Expected: "sh96009999,Arangel Channel (Palau),Q31886764,\"1278640,1797194\"",
+		},
+		"both": {
+			Opts:     &RunOptions{Concordances: "worldcat", IncludeWikidata: true, IncludeWorldcat: true},
+			Expected: "sh96009999,Arangel Channel (Palau),\"1278640,1797194\",Q31886764",
+		},
+		"exact": {
+			Opts:     &RunOptions{IncludeWikidata: true, IncludeWorldcat: true, ConcordanceMatch: "exact"},
+			Expected: "sh96009999,Arangel Channel (Palau),,",
+		},
+	}
+
+	for name, test := range tests {
+
+		var buf bytes.Buffer
+
+		opts := test.Opts
+		opts.Dataset = ds
+		opts.URIs = []string{"../../fixtures/lcsh.sample.ndjson"}
+		opts.Languages = ds.Languages
+		opts.Writer = &buf
+		opts.Ordered = true
+
+		err := RunWithOptions(ctx, opts)
+
+		if err != nil {
+			t.Fatalf("Failed to run %s, %v", name, err)
+		}
+
+		found := false
+
+		for _, row := range strings.Split(buf.String(), "\n") {
+
+			if strings.HasPrefix(row, "sh96009999,") {
+
+				if row != test.Expected {
+					t.Fatalf("Unexpected row for %s: %s (expected %s)", name, row, test.Expected)
+				}
+
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatalf("Missing row for %s", name)
+		}
+	}
+}
+
+func TestRunWithOptionsNoRows(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcnaf")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+
+	outputs := map[string]string{
+		filepath.Join(dir, "variants.csv"):   "id,variant,language\n",
+		filepath.Join(dir, "labels.csv"):     "id,label,language,script,type\n",
+		filepath.Join(dir, "components.csv"): "id,position,component_id,component_label,component_type\n",
+	}
+
+	// There are no LCNAF records in the LCSH fixtures so only the headers should be written
+
+	opts := &RunOptions{
+		Dataset:          ds,
+		URIs:             []string{"../../fixtures/lcsh.sample.ndjson"},
+		Languages:        ds.Languages,
+		Writer:           &buf,
+		VariantsOutput:   filepath.Join(dir, "variants.csv"),
+		LabelsOutput:     filepath.Join(dir, "labels.csv"),
+		ComponentsOutput: filepath.Join(dir, "components.csv"),
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	if buf.String() != "id,label\n" {
+		t.Fatalf("Unexpected output: '%s'", buf.String())
+	}
+
+	for path, expected := range outputs {
+
+		body, err := os.ReadFile(path)
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", path, err)
+		}
+
+		if string(body) != expected {
+			t.Fatalf("Unexpected output in %s: '%s' (expected '%s')", path, body, expected)
+		}
+	}
+}
+
+func TestRunWithOptionsEdges(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcgft")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	opts := &RunOptions{
+		Dataset: ds,
+		URIs:    []string{"../../fixtures/lcgft.sample.ndjson"},
+		Writer:  &buf,
+		Edges:   true,
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	expected := "from,to,relation\ngf2014026092,gf2014026339,broader\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output: '%s' (expected '%s')", buf.String(), expected)
+	}
+
+	opts.Redirects = true
+
+	err = RunWithOptions(ctx, opts)
+
+	if err == nil {
+		t.Fatalf("Expected error for mutually exclusive options")
+	}
+}
+
+// amplifiersRecord is the complete record for sh85004652, whose graph includes an abbreviated description of the first
+// LCSH fixture (sh85016999).
+const amplifiersRecord = `{"@context": {"madsrdf": "http://www.loc.gov/mads/rdf/v1#", "skos": "http://www.w3.org/2004/02/skos/core#", "about": "http://id.loc.gov/authorities/subjects/sh85004652"}, "@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85004652", "@type": ["madsrdf:Authority", "madsrdf:Topic", "skos:Concept"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Amplifiers (Electronics)"}, "skos:broader": {"@id": "http://id.loc.gov/authorities/subjects/sh85042383"}, "skos:narrower": {"@id": "http://id.loc.gov/authorities/subjects/sh85016999"}}, {"@id": "http://id.loc.gov/authorities/subjects/sh85042383", "@type": ["madsrdf:Authority", "madsrdf:Topic", "skos:Concept"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Electronics"}}, {"@id": "http://id.loc.gov/authorities/subjects/sh85016999", "@type": ["madsrdf:Authority", "madsrdf:Topic", "skos:Concept"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Broadband amplifiers"}}]}`
+
+// shortDescriptionFixture() writes the first LCSH fixture, whose graph includes an abbreviated description of its broader
+// heading (sh85004652), followed by the complete record for that heading, to a temporary file and returns its path.
+func shortDescriptionFixture(t *testing.T) string {
+
+	body, err := os.ReadFile("../../fixtures/lcsh.sample.ndjson")
+
+	if err != nil {
+		t.Fatalf("Failed to read fixtures, %v", err)
+	}
+
+	first := strings.SplitAfter(string(body), "\n")[0]
+
+	full := amplifiersRecord + "\n"
+
+	path := filepath.Join(t.TempDir(), "lcsh.ndjson")
+
+	err = os.WriteFile(path, []byte(first+full), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	return path
+}
+
+func TestRunWithOptionsEdgesShortDescription(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	path := shortDescriptionFixture(t)
+
+	var buf bytes.Buffer
+
+	opts := &RunOptions{
+		Dataset: ds,
+		URIs:    []string{path},
+		Writer:  &buf,
+		Ordered: true,
+		Edges:   true,
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	// The abbreviated description of sh85004652 in the first record must not stand in for its complete record
+
+	expected := "from,to,relation\nsh85016999,sh85004652,broader\nsh85016999,sh85038540,narrower\nsh85004652,sh85042383,broader\nsh85004652,sh85016999,narrower\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output: '%s' (expected '%s')", buf.String(), expected)
+	}
+
+	hierarchy := authority.NewHierarchy()
+
+	cb := walkCallbackFunc(&walkCallbackOptions{
+		Dataset:   ds,
+		Languages: ds.Languages,
+		Seen:      new(sync.Map),
+		Hierarchy: hierarchy,
+		Writer:    newDiscardWriter(t, []string{"id", "label"}),
+	})
+
+	w, err := walk.NewWalker(ctx, "ndjson://?ordered=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	err = w.WalkURIs(ctx, cb, path)
+
+	if err != nil {
+		t.Fatalf("Failed to walk %s, %v", path, err)
+	}
+
+	asymmetric := hierarchy.Asymmetric()
+
+	if len(asymmetric) != 0 {
+		t.Fatalf("Unexpected asymmetric relationships: %d", len(asymmetric))
+	}
+}
+
+func TestRunWithOptionsShortDescription(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	path := shortDescriptionFixture(t)
+
+	// Abbreviated descriptions are only output when nothing other than IDs and labels is being output. Otherwise the
+	// abbreviated description of sh85004652 in the first record must not stand in for its complete record.
+
+	tests := map[bool]string{
+		false: "id,label\nsh85004652,Amplifiers (Electronics)\nsh85016999,Broadband amplifiers\nsh85038540,Distributed amplifiers\nsh85042383,Electronics\n",
+		true:  "id,label,broader\nsh85016999,Broadband amplifiers,sh85004652\nsh85004652,Amplifiers (Electronics),sh85042383\n",
+	}
+
+	for include_broader, expected := range tests {
+
+		var buf bytes.Buffer
+
+		opts := &RunOptions{
+			Dataset:        ds,
+			URIs:           []string{path},
+			Languages:      ds.Languages,
+			Writer:         &buf,
+			Ordered:        true,
+			IncludeBroader: include_broader,
+		}
+
+		err = RunWithOptions(ctx, opts)
+
+		if err != nil {
+			t.Fatalf("Failed to run (include broader %t), %v", include_broader, err)
+		}
+
+		if buf.String() != expected {
+			t.Fatalf("Unexpected output (include broader %t): '%s' (expected '%s')", include_broader, buf.String(), expected)
+		}
+	}
+}
+
+func TestRunWithOptionsVariantsShortDescription(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	// The abbreviated description of sh85016999 in the record for sh85004652 precedes its complete record
+
+	body, err := os.ReadFile("../../fixtures/lcsh.sample.ndjson")
+
+	if err != nil {
+		t.Fatalf("Failed to read fixtures, %v", err)
+	}
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "lcsh.ndjson")
+	variants := filepath.Join(dir, "variants.csv")
+
+	err = os.WriteFile(path, append([]byte(amplifiersRecord+"\n"), body...), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	var buf bytes.Buffer
+
+	opts := &RunOptions{
+		Dataset:         ds,
+		URIs:            []string{path},
+		Languages:       ds.Languages,
+		Writer:          &buf,
+		Ordered:         true,
+		IncludeVariants: true,
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	expected := "id,label,variants\nsh85004652,Amplifiers (Electronics),\nsh85016999,Broadband amplifiers,Wide-band amplifiers@en\nsh2004004999,Śreshṭha family,Śreṣṭha family@en|Shreshtha family@en\nsh96009999,Arangel Channel (Palau),\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output: '%s' (expected '%s')", buf.String(), expected)
+	}
+
+	buf.Reset()
+
+	opts.VariantsOutput = variants
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run with variants output, %v", err)
+	}
+
+	variants_body, err := os.ReadFile(variants)
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", variants, err)
+	}
+
+	if !strings.Contains(string(variants_body), "\nsh85016999,Wide-band amplifiers,en\n") {
+		t.Fatalf("Unexpected variants output: '%s'", variants_body)
+	}
+}
+
+func TestRunWithOptionsRedirectsShortDescription(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	// The graph for sh2001000001 includes an abbreviated description of the deprecated heading it replaces, which
+	// does not include the madsrdf:useInstead pointer found in the complete record that follows it
+
+	records := []string{
+		`{"@context": {"madsrdf": "http://www.loc.gov/mads/rdf/v1#", "about": "http://id.loc.gov/authorities/subjects/sh2001000001"}, "@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh2001000001", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Electronic amplifiers"}}, {"@id": "http://id.loc.gov/authorities/subjects/sh85000001", "@type": ["madsrdf:DeprecatedAuthority", "madsrdf:Topic"], "madsrdf:variantLabel": {"@language": "en", "@value": "Amplifiers, Electronic"}}]}`,
+		`{"@context": {"madsrdf": "http://www.loc.gov/mads/rdf/v1#", "about": "http://id.loc.gov/authorities/subjects/sh85000001"}, "@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85000001", "@type": ["madsrdf:DeprecatedAuthority", "madsrdf:Topic"], "madsrdf:variantLabel": {"@language": "en", "@value": "Amplifiers, Electronic"}, "madsrdf:useInstead": {"@id": "http://id.loc.gov/authorities/subjects/sh2001000001"}}, {"@id": "http://id.loc.gov/authorities/subjects/sh2001000001", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Electronic amplifiers"}}]}`,
+	}
+
+	path := filepath.Join(t.TempDir(), "lcsh.ndjson")
+
+	err = os.WriteFile(path, []byte(strings.Join(records, "\n")+"\n"), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	var buf bytes.Buffer
+
+	opts := &RunOptions{
+		Dataset:   ds,
+		URIs:      []string{path},
+		Languages: ds.Languages,
+		Writer:    &buf,
+		Ordered:   true,
+		Redirects: true,
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	expected := "deprecated_id,replacement_id\nsh85000001,sh2001000001\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output: '%s' (expected '%s')", buf.String(), expected)
+	}
+}
+
+// newDiscardWriter() returns a `csvdict.Writer` instance with 'fieldnames' which discards everything written to it.
+func newDiscardWriter(t *testing.T, fieldnames []string) *csvdict.Writer {
+
+	wr, err := csvdict.NewWriter(io.Discard, fieldnames)
+
+	if err != nil {
+		t.Fatalf("Failed to create CSV writer, %v", err)
+	}
+
+	return wr
+}
+
+func TestRunWithOptionsMARCXML(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	opts := &RunOptions{
+		Dataset:         ds,
+		URIs:            []string{"../../fixtures/authorities.sample.marcxml"},
+		Format:          "marcxml",
+		Languages:       ds.Languages,
+		Writer:          &buf,
+		IncludeBroader:  true,
+		IncludeVariants: true,
+	}
+
+	err = RunWithOptions(ctx, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	expected := "id,label,broader,variants\nsh85016999,Broadband amplifiers,sh85004652,Wide-band amplifiers\nsh2008100128,Anarchism--Italy--History--20th century,,\nsh99005024,History,,\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output: '%s' (expected '%s')", buf.String(), expected)
+	}
+}
+
+func TestRunWithOptionsCallbackWorkers(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	run := func(callback_workers int, shards int) []string {
+
+		var buf bytes.Buffer
+
+		opts := &RunOptions{
+			Dataset:           ds,
+			URIs:              []string{"../../fixtures/lcsh.sample.ndjson"},
+			Languages:         ds.Languages,
+			Writer:            &buf,
+			IncludeBroader:    true,
+			IncludeComponents: true,
+			IncludeVariants:   true,
+			Deprecated:        "include",
+			CallbackWorkers:   callback_workers,
+			Shards:            shards,
+		}
+
+		err := RunWithOptions(ctx, opts)
+
+		if err != nil {
+			t.Fatalf("Failed to run with %d callback workers and %d shards, %v", callback_workers, shards, err)
+		}
+
+		rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		sort.Strings(rows)
+
+		return rows
+	}
+
+	expected := run(1, 1)
+
+	for _, shards := range []int{1, 3} {
+
+		rows := run(8, shards)
+
+		if !reflect.DeepEqual(rows, expected) {
+			t.Fatalf("Unexpected output with callback workers and %d shards: %v (expected %v)", shards, rows, expected)
+		}
+	}
+}
+
+func TestRunWithOptionsResume(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	dir := t.TempDir()
+
+	fixture := "../../fixtures/lcsh.sample.ndjson"
+	later := filepath.Join(dir, "later.ndjson")
+	output := filepath.Join(dir, "output.csv")
+	variants := filepath.Join(dir, "variants.csv")
+
+	run := func(resume bool) error {
+
+		fh, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to open %s, %v", output, err)
+		}
+
+		defer fh.Close()
+
+		opts := &RunOptions{
+			Dataset:            ds,
+			URIs:               []string{fixture, later},
+			Languages:          ds.Languages,
+			Writer:             fh,
+			IncludeBroader:     true,
+			VariantsOutput:     variants,
+			Checkpoint:         filepath.Join(dir, "checkpoint.json"),
+			CheckpointInterval: 1,
+			Resume:             resume,
+		}
+
+		return RunWithOptions(ctx, opts)
+	}
+
+	// The first run fails because the second URI does not exist, once the first URI has been processed
+
+	err = run(false)
+
+	if err == nil {
+		t.Fatalf("Expected first run to fail")
+	}
+
+	expected, err := os.ReadFile(output)
+
+	if err != nil {
+		t.Fatalf("Failed to read output, %v", err)
+	}
+
+	expected_variants, err := os.ReadFile(variants)
+
+	if err != nil {
+		t.Fatalf("Failed to read variants, %v", err)
+	}
+
+	if !strings.HasPrefix(string(expected), "id,label,broader\nsh85016999,Broadband amplifiers,sh85004652\n") {
+		t.Fatalf("Unexpected output from first run: '%s'", expected)
+	}
+
+	// Simulate rows written after the checkpoint and before the first run was interrupted, which should be discarded
+
+	for _, path := range []string{output, variants} {
+
+		fh, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to open %s, %v", path, err)
+		}
+
+		fh.WriteString("partial,row\n")
+		fh.Close()
+	}
+
+	// The second URI is a copy of the first so every record should be de-duplicated using the catalog restored from the checkpoint
+
+	body, err := os.ReadFile(fixture)
+
+	if err != nil {
+		t.Fatalf("Failed to read fixture, %v", err)
+	}
+
+	err = os.WriteFile(later, body, 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", later, err)
+	}
+
+	err = run(true)
+
+	if err != nil {
+		t.Fatalf("Failed to resume, %v", err)
+	}
+
+	for path, expected := range map[string][]byte{output: expected, variants: expected_variants} {
+
+		body, err := os.ReadFile(path)
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", path, err)
+		}
+
+		if !bytes.Equal(body, expected) {
+			t.Fatalf("Unexpected output in %s after resuming: '%s' (expected '%s')", path, body, expected)
+		}
+	}
+}
+
+func TestRunWithOptionsTolerant(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "lcsh")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	dir := t.TempDir()
+
+	fixture := "../../fixtures/lcsh.sample.ndjson"
+
+	body, err := os.ReadFile(fixture)
+
+	if err != nil {
+		t.Fatalf("Failed to read fixture, %v", err)
+	}
+
+	// A truncated record and a record without a @graph property
+
+	body = append([]byte("{\"@graph\":[\n{\"@context\":{}}\n"), body...)
+
+	path := filepath.Join(dir, "test.ndjson")
+
+	err = os.WriteFile(path, body, 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	run := func(uri string, tolerant bool, rejects string, max_errors int) (string, error) {
+
+		var buf bytes.Buffer
+
+		opts := &RunOptions{
+			Dataset:   ds,
+			URIs:      []string{uri},
+			Languages: ds.Languages,
+			Writer:    &buf,
+			Ordered:   true,
+			Tolerant:  tolerant,
+			Rejects:   rejects,
+			MaxErrors: max_errors,
+		}
+
+		err := RunWithOptions(ctx, opts)
+		return buf.String(), err
+	}
+
+	expected, err := run(fixture, false, "", 0)
+
+	if err != nil {
+		t.Fatalf("Failed to run fixture, %v", err)
+	}
+
+	_, err = run(path, false, "", 0)
+
+	if err == nil {
+		t.Fatalf("Expected run with invalid records to fail")
+	}
+
+	rejects := filepath.Join(dir, "rejects.ndjson")
+
+	out, err := run(path, false, rejects, 0)
+
+	if err != nil {
+		t.Fatalf("Failed to run with rejects, %v", err)
+	}
+
+	if out != expected {
+		t.Fatalf("Unexpected output with rejects: '%s' (expected '%s')", out, expected)
+	}
+
+	enc_rejects, err := os.ReadFile(rejects)
+
+	if err != nil {
+		t.Fatalf("Failed to read rejects, %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(enc_rejects)), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected rejects: %s", enc_rejects)
+	}
+
+	for i, expected_error := range []string{"Record is not valid JSON", "Failed to process record, Failed to parse record, Record is missing @graph property"} {
+
+		var rec *walk.Reject
+
+		err := json.Unmarshal([]byte(lines[i]), &rec)
+
+		if err != nil {
+			t.Fatalf("Failed to decode reject, %v", err)
+		}
+
+		if rec.URI != path || rec.LineNumber != i+1 || rec.Error != expected_error {
+			t.Fatalf("Unexpected reject: %s", lines[i])
+		}
+	}
+
+	_, err = run(path, true, "", 1)
+
+	if !errors.Is(err, walk.ErrTooManyRejects) {
+		t.Fatalf("Expected too many rejects error, got %v", err)
+	}
+
+	// The number of rejected records, and the offset of the rejects output, are restored when resuming so the
+	// maximum number of rejected records applies to the run as a whole
+
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+
+	resume := func(max_errors int) error {
+
+		var buf bytes.Buffer
+
+		opts := &RunOptions{
+			Dataset:    ds,
+			URIs:       []string{path},
+			Languages:  ds.Languages,
+			Writer:     &buf,
+			Checkpoint: checkpoint,
+			Resume:     true,
+			Rejects:    rejects,
+			MaxErrors:  max_errors,
+		}
+
+		return RunWithOptions(ctx, opts)
+	}
+
+	err = resume(2)
+
+	if err != nil {
+		t.Fatalf("Failed to run with checkpoint, %v", err)
+	}
+
+	cp, err := walk.ReadCheckpoint(checkpoint)
+
+	if err != nil {
+		t.Fatalf("Failed to read checkpoint, %v", err)
+	}
+
+	if cp.Rejects != 2 || cp.RejectsOffset != int64(len(enc_rejects)) {
+		t.Fatalf("Unexpected rejects (%d) or rejects offset (%d) in checkpoint", cp.Rejects, cp.RejectsOffset)
+	}
+
+	// Rewind the checkpoint to the record without a @graph property, as though the run had been interrupted
+
+	cp.LineNumber = 2
+	cp.Offset = int64(bytes.Index(body, []byte("{\"@context\"")))
+	cp.Rejects = 1
+	cp.RejectsOffset = int64(len(lines[0]) + 1)
+
+	err = walk.WriteCheckpoint(checkpoint, cp)
+
+	if err != nil {
+		t.Fatalf("Failed to write checkpoint, %v", err)
+	}
+
+	err = resume(1)
+
+	if !errors.Is(err, walk.ErrTooManyRejects) {
+		t.Fatalf("Expected too many rejects error when resuming, got %v", err)
+	}
+
+	enc_resumed, err := os.ReadFile(rejects)
+
+	if err != nil {
+		t.Fatalf("Failed to read rejects, %v", err)
+	}
+
+	if string(enc_resumed) != string(enc_rejects) {
+		t.Fatalf("Unexpected rejects after resuming: %s", enc_resumed)
+	}
+}
@@ -0,0 +1,547 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sfomuseum/go-csvdict"
+	"github.com/sfomuseum/go-libraryofcongress"
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/concordance"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+	"github.com/sfomuseum/go-libraryofcongress/lcc"
+	"github.com/sfomuseum/go-libraryofcongress/walk"
+)
+
+// type walkCallbackOptions defines configuration options for the `walkCallbackFunc` function.
+type walkCallbackOptions struct {
+	// Dataset is the `dataset.Dataset` instance describing the vocabulary being parsed. Records (and pointers to records)
+	// which are not part of the vocabulary are ignored.
+	Dataset *dataset.Dataset
+	// Writer is the `csvdict.Writer` instance where record data is written.
+	Writer *csvdict.Writer
+	// VariantsWriter is an optional `csvdict.Writer` instance where variant labels are written as "id,variant,language" rows.
+	VariantsWriter *csvdict.Writer
+	// VariantsDelimiter is the delimiter used to separate multiple variant labels in the "variants" column.
+	VariantsDelimiter string
+	// ComponentsWriter is an optional `csvdict.Writer` instance where the components of pre-coordinated headings
+	// are written as "id,position,component_id,component_label,component_type" rows.
+	ComponentsWriter *csvdict.Writer
+	// ComponentsDelimiter is the delimiter used to separate multiple components in the "component_*" columns.
+	ComponentsDelimiter string
+	// Languages is the list of preferred language tags used to select the label for each record.
+	Languages []string
+	// LabelsWriter is an optional `csvdict.Writer` instance where all the labels for each record are written as "id,label,language,script,type" rows.
+	LabelsWriter *csvdict.Writer
+	// Fieldnames is the list of columns being written to 'Writer'.
+	Fieldnames []string
+	// Concordances is the list of `concordance.Concordance` instances used to derive pointers to external vocabularies.
+	Concordances []concordance.Concordance
+	// ConcordanceMatch is the type of external authority matches to include. Valid options are: any, close, exact.
+	ConcordanceMatch string
+	// LegacyConcordances is the set of names of the instances in 'Concordances' which were enabled by the -include-wikidata
+	// and -include-worldcat flags. They are written the way those flags always have been: a single pointer (the last one
+	// listed in the record) derived from matches of type 'LegacyConcordanceMatch'.
+	LegacyConcordances map[string]bool
+	// LegacyConcordanceMatch is the type of external authority matches to include for 'LegacyConcordances'.
+	LegacyConcordanceMatch string
+	// Deprecated indicates how deprecated records should be handled. Valid options are: exclude, include, only.
+	Deprecated string
+	// ClassificationRange is an optional `lcc.Range` instance used to limit output to records with a class number that falls within it.
+	ClassificationRange *lcc.Range
+	// PrimaryOnly is a boolean flag indicating that only the primary authority described by each record should be processed.
+	// By default, if only IDs and labels are being written, every authority in a record's graph, including the abbreviated
+	// descriptions of the authorities it references, is processed.
+	PrimaryOnly bool
+	// Redirects is a boolean flag indicating that "deprecated_id,replacement_id" rows should be written rather than record data.
+	Redirects bool
+	// Seen is a `sync.Map` instance used to track records that have already been written. It is ignored if 'Catalog' is not nil.
+	Seen *sync.Map
+	// Catalog is an optional `libraryofcongress.Catalog` instance used to track records that have already been written for
+	// vocabularies which are too large to track in memory.
+	Catalog *libraryofcongress.Catalog
+	// Edges is a boolean flag indicating that broader, narrower and related edges should be written rather than record data.
+	Edges bool
+	// Hierarchy is an optional `authority.Hierarchy` instance used to track broader and narrower relationships.
+	Hierarchy *authority.Hierarchy
+}
+
+func walkCallbackFunc(opts *walkCallbackOptions) walk.WalkCallbackFunction {
+
+	csv_wr := opts.Writer
+	ds := opts.Dataset
+
+	capture := make(map[string]bool)
+
+	for _, k := range opts.Fieldnames {
+		capture[k] = true
+	}
+
+	// Callback functions may be invoked concurrently (see the -callback-workers flag) so writes to the
+	// (not thread-safe) CSV writers are serialised. Everything else is either local to each record or
+	// already safe for concurrent use.
+
+	mu := new(sync.Mutex)
+
+	write := func(wr *csvdict.Writer, rows ...map[string]string) error {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, row := range rows {
+
+			err := wr.WriteRow(row)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		wr.Flush()
+		return wr.Error()
+	}
+
+	// process() writes the data for a single authority described by a record
+
+	process := func(ctx context.Context, a *authority.Authority) error {
+
+		id := a.ID
+
+		if !ds.Contains(id) {
+			return nil
+		}
+
+		sh_id := a.Identifier()
+		lbl := a.LabelFor(opts.Languages...)
+
+		if lbl == nil {
+			return nil
+		}
+
+		label := lbl.Value
+
+		switch opts.Deprecated {
+		case "exclude":
+
+			if a.IsDeprecated() {
+				return nil
+			}
+
+		case "only":
+
+			if !a.IsDeprecated() {
+				return nil
+			}
+		}
+
+		if opts.ClassificationRange != nil && !inClassificationRange(a, opts.ClassificationRange) {
+			return nil
+		}
+
+		exists, err := isSeen(ctx, opts, sh_id)
+
+		if err != nil {
+			return fmt.Errorf("Failed to determine whether %s exists, %w", sh_id, err)
+		}
+
+		if exists {
+			return nil
+		}
+
+		if opts.Hierarchy != nil {
+			opts.Hierarchy.Add(a)
+		}
+
+		if opts.Redirects {
+
+			rows := make([]map[string]string, 0)
+
+			for _, r := range a.Replacements() {
+
+				r_id, ok := ds.Identifier(r)
+
+				if !ok {
+					continue
+				}
+
+				r_out := map[string]string{
+					"deprecated_id":  sh_id,
+					"replacement_id": r_id,
+				}
+
+				rows = append(rows, r_out)
+			}
+
+			err = write(csv_wr, rows...)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write replacements for %s, %v", id, err)
+			}
+
+			return nil
+		}
+
+		if opts.Edges {
+
+			rows := make([]map[string]string, 0)
+
+			for _, e := range a.Edges() {
+
+				to_id, ok := ds.Identifier(e.To)
+
+				if !ok {
+					continue
+				}
+
+				e_out := map[string]string{
+					"from":     sh_id,
+					"to":       to_id,
+					"relation": e.Relation,
+				}
+
+				rows = append(rows, e_out)
+			}
+
+			err = write(csv_wr, rows...)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write edges for %s, %v", id, err)
+			}
+
+			return nil
+		}
+
+		out := map[string]string{
+			"id":    sh_id,
+			"label": label,
+		}
+
+		_, capture_type := capture["type"]
+
+		if capture_type {
+			out["type"] = a.MADSType()
+		}
+
+		_, capture_broader := capture["broader"]
+
+		if len(opts.Concordances) > 0 {
+
+			concordance_ids := make(map[string][]string)
+			seen_ids := make(map[string]bool)
+
+			for _, c := range opts.Concordances {
+				concordance_ids[c.Name()] = make([]string, 0)
+			}
+
+			for _, m := range concordance.Matches(a, opts.Concordances...) {
+
+				match := opts.ConcordanceMatch
+
+				if opts.LegacyConcordances[m.Concordance] {
+					match = opts.LegacyConcordanceMatch
+				}
+
+				if match != "any" && m.Match != match {
+					continue
+				}
+
+				// The same authority may be both a close and an exact match
+
+				k := m.Concordance + "#" + m.ID
+
+				if seen_ids[k] {
+					continue
+				}
+
+				seen_ids[k] = true
+				concordance_ids[m.Concordance] = append(concordance_ids[m.Concordance], m.ID)
+			}
+
+			for name, ids := range concordance_ids {
+
+				if opts.LegacyConcordances[name] && len(ids) > 1 {
+					ids = ids[len(ids)-1:]
+				}
+
+				k := fmt.Sprintf("%s_id", name)
+				out[k] = strings.Join(ids, ",")
+			}
+		}
+
+		if capture_broader {
+
+			out["broader"] = strings.Join(ds.Identifiers(a.Broader), ",")
+		}
+
+		_, capture_classification := capture["classification"]
+
+		if capture_classification {
+			out["classification"] = strings.Join(a.ClassificationCodes(), ",")
+		}
+
+		_, capture_name_parts := capture["family_name"]
+
+		if capture_name_parts {
+
+			err := addNameParts(a, out)
+
+			if err != nil {
+				return fmt.Errorf("Failed to derive name parts for %s, %w", id, err)
+			}
+		}
+
+		_, capture_last_change := capture["last_change_date"]
+
+		if capture_last_change {
+
+			out["last_change_date"] = ""
+			out["last_change_reason"] = ""
+
+			cs := a.LastChange()
+
+			if cs != nil {
+				out["last_change_date"] = cs.CreatedDate.Format(time.RFC3339)
+				out["last_change_reason"] = cs.Reason
+			}
+		}
+
+		_, capture_status := capture["status"]
+
+		if capture_status {
+			out["status"] = a.Status()
+		}
+
+		_, capture_components := capture["component_ids"]
+
+		if capture_components {
+
+			count := len(a.Components)
+
+			ids := make([]string, count)
+			labels := make([]string, count)
+			types := make([]string, count)
+
+			for i, c := range a.Components {
+				ids[i] = c.Identifier()
+				labels[i] = c.Label()
+				types[i] = c.Type
+			}
+
+			out["component_ids"] = strings.Join(ids, opts.ComponentsDelimiter)
+			out["component_labels"] = strings.Join(labels, opts.ComponentsDelimiter)
+			out["component_types"] = strings.Join(types, opts.ComponentsDelimiter)
+		}
+
+		if opts.ComponentsWriter != nil && a.IsComplex() {
+
+			rows := make([]map[string]string, len(a.Components))
+
+			for i, c := range a.Components {
+
+				c_out := map[string]string{
+					"id":              sh_id,
+					"position":        strconv.Itoa(i + 1),
+					"component_id":    c.Identifier(),
+					"component_label": c.Label(),
+					"component_type":  c.Type,
+				}
+
+				rows[i] = c_out
+			}
+
+			err = write(opts.ComponentsWriter, rows...)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write components for %s, %v", id, err)
+			}
+		}
+
+		_, capture_scope_note := capture["scope_note"]
+
+		if capture_scope_note {
+			out["scope_note"] = a.ScopeNote()
+		}
+
+		_, capture_variants := capture["variants"]
+
+		if capture_variants {
+
+			tagged := make([]string, 0)
+
+			for _, v := range a.VariantLabels() {
+				tagged = append(tagged, v.Tagged())
+			}
+
+			out["variants"] = strings.Join(tagged, opts.VariantsDelimiter)
+		}
+
+		_, capture_language := capture["label_language"]
+
+		if capture_language {
+			out["label_language"] = lbl.Language
+			out["label_script"] = lbl.Script()
+		}
+
+		if opts.LabelsWriter != nil {
+
+			rows := make([]map[string]string, 0)
+
+			all_labels := map[string][]*authority.Label{
+				"authoritative": a.Labels,
+				"variant":       a.VariantLabels(),
+			}
+
+			for _, t := range []string{"authoritative", "variant"} {
+
+				for _, l := range all_labels[t] {
+
+					l_out := map[string]string{
+						"id":       sh_id,
+						"label":    l.Value,
+						"language": l.Language,
+						"script":   l.Script(),
+						"type":     t,
+					}
+
+					rows = append(rows, l_out)
+				}
+			}
+
+			err = write(opts.LabelsWriter, rows...)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write labels for %s, %v", id, err)
+			}
+		}
+
+		if opts.VariantsWriter != nil {
+
+			rows := make([]map[string]string, 0)
+
+			for _, v := range a.VariantLabels() {
+
+				v_out := map[string]string{
+					"id":       sh_id,
+					"variant":  v.Value,
+					"language": v.Language,
+				}
+
+				rows = append(rows, v_out)
+			}
+
+			err = write(opts.VariantsWriter, rows...)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write variants for %s, %v", id, err)
+			}
+		}
+
+		err = write(csv_wr, out)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write %s (%s), %v", id, label, err)
+		}
+
+		return nil
+	}
+
+	fn := func(ctx context.Context, body []byte) error {
+
+		var authorities []*authority.Authority
+
+		if primaryOnly(opts) {
+
+			a, err := authority.ParseRecord(body)
+
+			if err != nil {
+				return fmt.Errorf("Failed to parse record, %w", err)
+			}
+
+			authorities = []*authority.Authority{a}
+
+		} else {
+
+			v, err := authority.ParseRecords(body)
+
+			if err != nil {
+				return fmt.Errorf("Failed to parse record, %w", err)
+			}
+
+			authorities = v
+		}
+
+		for _, a := range authorities {
+
+			err := process(ctx, a)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// primaryOnly() returns a boolean value indicating whether only the primary authority described by each record should be
+// processed. Abbreviated descriptions of the authorities a record references are typically just a label so they are only
+// processed when nothing other than IDs and labels is being written. Otherwise, since each authority is only written once,
+// an abbreviated description would claim an authority ahead of the complete record which appears later in the file and its
+// relationships, replacements, variants and every other column would be lost.
+func primaryOnly(opts *walkCallbackOptions) bool {
+
+	if opts.PrimaryOnly || opts.Edges || opts.Redirects || opts.Hierarchy != nil {
+		return true
+	}
+
+	if opts.VariantsWriter != nil || opts.LabelsWriter != nil || opts.ComponentsWriter != nil {
+		return true
+	}
+
+	for _, k := range opts.Fieldnames {
+
+		if k != "id" && k != "label" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSeen() returns a boolean value indicating whether 'id' has already been processed, recording it as processed
+// if not.
+func isSeen(ctx context.Context, opts *walkCallbackOptions, id string) (bool, error) {
+
+	if opts.Catalog != nil {
+		return opts.Catalog.ExistsOrStore(ctx, id)
+	}
+
+	_, loaded := opts.Seen.LoadOrStore(id, true)
+	return loaded, nil
+}
+
+// inClassificationRange() returns a boolean value indicating whether any of the class numbers for 'a' fall within 'r'.
+// Class numbers which can not be parsed are ignored.
+func inClassificationRange(a *authority.Authority, r *lcc.Range) bool {
+
+	for _, code := range a.ClassificationCodes() {
+
+		code_r, err := lcc.ParseRange(code)
+
+		if err != nil {
+			continue
+		}
+
+		if code_r.Within(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+package parse
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+	"github.com/sfomuseum/go-libraryofcongress/walk"
+)
+
+var dataset_name string
+var format string
+
+var include_type bool
+var include_broader bool
+var include_classification bool
+var classification_range string
+
+var include_wikidata bool
+var include_worldcat bool
+var include_concordances bool
+var concordances string
+var concordance_match string
+
+var include_last_change bool
+var include_status bool
+var deprecated string
+var primary_only bool
+var redirects bool
+var since string
+var ordered bool
+var callback_workers int
+var shards int
+var checkpoint string
+var checkpoint_interval int
+var resume bool
+var tolerant bool
+var rejects string
+var max_errors int
+
+var include_name_parts bool
+
+var include_components bool
+var components_delimiter string
+var components_output string
+
+var lang string
+var include_language bool
+var labels_output string
+
+var include_scope_note bool
+
+var include_variants bool
+var variants_delimiter string
+var variants_output string
+
+var edges bool
+var check_hierarchy bool
+
+var include_all bool
+
+// DefaultFlagSet() returns a `flag.FlagSet` instance with the flags used to parse the records in 'ds'. If 'ds' is nil
+// a "-dataset" flag is added so that the vocabulary to parse can be specified at runtime.
+func DefaultFlagSet(ds *dataset.Dataset) *flag.FlagSet {
+
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+
+	noun := "record"
+	plural := "records"
+	default_lang := "en"
+
+	if ds == nil {
+		fs.StringVar(&dataset_name, "dataset", "", fmt.Sprintf("The name of the Library of Congress vocabulary being parsed. Valid options are: %s.", strings.Join(dataset.Names(), ", ")))
+	} else {
+		noun = ds.Noun
+		plural = ds.Plural()
+		default_lang = strings.Join(ds.Languages, ",")
+	}
+
+	fs.StringVar(&format, "format", "ndjson", fmt.Sprintf("The format of the files being parsed. Valid options are: %s. MARC 21 authority records are converted to MADS/RDF, and N-Triples and Turtle triples are grouped in to JSON-LD records, before being parsed.", strings.Join(formats(), ", ")))
+
+	fs.BoolVar(&include_type, "include-type", false, fmt.Sprintf("If present, include the MADS/RDF type (for example Topic, ComplexSubject, PersonalName, CorporateName or GenreForm) of each %s", noun))
+
+	fs.BoolVar(&include_broader, "include-broader", false, fmt.Sprintf("If present, include a comma-separated list of broader (skos:broader and madsrdf:hasBroaderAuthority) pointers associated with each %s", noun))
+
+	fs.BoolVar(&include_classification, "include-classification", false, fmt.Sprintf("If present, include a comma-separated list of Library of Congress Classification numbers (madsrdf:classification) associated with each %s", noun))
+
+	fs.StringVar(&classification_range, "classification-range", "", fmt.Sprintf("If present, only output %s with a Library of Congress Classification number (or range of numbers) that falls within this range, for example \"QA75-QA76.95\"", plural))
+
+	fs.BoolVar(&include_wikidata, "include-wikidata", false, fmt.Sprintf("If present, include a Wikidata pointer associated with each %s. If there is more than one pointer only the last is included. Use \"-concordances wikidata\" to include all of them.", noun))
+
+	fs.BoolVar(&include_worldcat, "include-worldcat", false, fmt.Sprintf("If present, include a Worldcat pointer associated with each %s. If there is more than one pointer only the last is included. Use \"-concordances worldcat\" to include all of them.", noun))
+
+	fs.BoolVar(&include_concordances, "include-concordances", false, "If true will enable the -include-wikidata and -include-worldcat flags")
+
+	fs.StringVar(&concordances, "concordances", "", "A comma-separated list of external (or other Library of Congress) vocabularies to include pointers for, for example \"wikidata,viaf,isni\" or \"lcsh\". Each vocabulary will be written to a \"{NAME}_id\" column. Use \"all\" to include every known vocabulary.")
+
+	fs.StringVar(&concordance_match, "concordance-match", "", "The type of external authority matches to include. Valid options are: any, close (madsrdf:hasCloseExternalAuthority or skos:closeMatch), exact (madsrdf:hasExactExternalAuthority or skos:exactMatch). If empty the default is \"any\" for the vocabularies listed in the -concordances flag and \"close\" for the -include-wikidata and -include-worldcat flags.")
+
+	fs.BoolVar(&include_last_change, "include-last-change", false, fmt.Sprintf("If present, include the date and reason of the most recent change made to each %s", noun))
+
+	fs.BoolVar(&include_status, "include-status", false, fmt.Sprintf("If present, include the status (for example \"new\", \"revised\" or \"deprecated\") of each %s", noun))
+
+	fs.StringVar(&deprecated, "deprecated", "exclude", fmt.Sprintf("How to handle deprecated (and cancelled) %s. Valid options are: exclude, include, only.", plural))
+
+	fs.BoolVar(&primary_only, "primary-only", false, fmt.Sprintf("If true, only output the primary %s described by each JSON-LD graph being parsed. By default, if only IDs and labels are being output, every %s in the graph is output, including the abbreviated descriptions (typically just a label) of the %s it references, for example broader headings. Any other columns, or the -edges, -redirects, -check-hierarchy, -variants-output, -labels-output and -components-output flags, imply -primary-only since an abbreviated description would otherwise be output instead of the complete record that appears later in the file.", noun, noun, plural))
+
+	fs.BoolVar(&redirects, "redirects", false, fmt.Sprintf("If true, output a \"deprecated_id,replacement_id\" table mapping deprecated %s to the %s that replace them (derived from madsrdf:useInstead and owl:sameAs) rather than %s data. The -include-* and -deprecated flags are ignored.", plural, plural, noun))
+
+	fs.StringVar(&since, "since", "", "If present, only output records which have been created, revised or deprecated after this date (YYYY-MM-DD)")
+
+	fs.BoolVar(&ordered, "ordered", false, "If true, output records in the order they appear in the files being parsed. Records are still processed in parallel but output is deterministic, for example so that the output of successive runs can be compared.")
+
+	fs.IntVar(&callback_workers, "callback-workers", 1, fmt.Sprintf("The number of %s to process simultaneously once they have been read. Values greater than 1 can speed up parsing large vocabularies but, if -ordered is also true, output is only deterministic when this is 1. Only applies to NDJSON files.", plural))
+
+	fs.IntVar(&shards, "shards", 1, fmt.Sprintf("The number of byte ranges, aligned to the start of a line, that uncompressed NDJSON files are split in to and parsed concurrently. Values greater than 1 can speed up parsing large local or remote (HTTP) files on multi-core machines but the order of %s in the output is not deterministic, even if -ordered is true. Can not be combined with -checkpoint.", plural))
+
+	fs.StringVar(&checkpoint, "checkpoint", "", fmt.Sprintf("If present, periodically write the position of the last %s processed to this path so that an interrupted run can be resumed with the -resume flag. The database used to de-duplicate %s is stored alongside it with a \".db\" extension. Implies -ordered. Only applies to NDJSON files.", noun, plural))
+
+	fs.IntVar(&checkpoint_interval, "checkpoint-interval", 100000, fmt.Sprintf("The number of %s to process between checkpoints.", plural))
+
+	fs.BoolVar(&resume, "resume", false, fmt.Sprintf("If true, resume an interrupted run from the checkpoint written to the path defined by the -checkpoint flag (if it exists). Output written to files after the checkpoint is discarded so output should be appended (for example using \">>\") to the output of the interrupted run. Relationships checked by the -check-hierarchy flag are only those of %s processed after resuming.", plural))
+
+	fs.BoolVar(&tolerant, "tolerant", false, fmt.Sprintf("If true, %s which can not be read or parsed (for example because a compressed file is truncated, or because they are not valid JSON or are missing a @graph property) are counted and skipped rather than stopping the run. Since a file can not be read past an error the remainder of a file which can not be read is skipped.", plural))
+
+	fs.StringVar(&rejects, "rejects", "", fmt.Sprintf("If present, write each %s skipped by the -tolerant flag to this path as a line of JSON with \"uri\", \"entry\", \"line\", \"offset\", \"error\" and \"record\" properties. Implies -tolerant.", noun))
+
+	fs.IntVar(&max_errors, "max-errors", 0, fmt.Sprintf("The maximum number of %s that can be skipped by the -tolerant flag before the run is stopped. If 0 there is no maximum. The number of skipped %s is stored in checkpoints so the maximum applies to resumed runs as a whole.", plural, plural))
+
+	fs.BoolVar(&include_name_parts, "include-name-parts", false, "If present, include the family name, given name, numeration, titles, birth, death and flourished dates (as EDTF strings) and work title derived from each personal, family and name/title heading")
+
+	fs.BoolVar(&include_components, "include-components", false, "If present, include the ID, label and facet type (for example Topic, Geographic, Temporal or GenreForm) of each component of pre-coordinated (madsrdf:ComplexSubject) headings, in order, as \"component_ids\", \"component_labels\" and \"component_types\" columns")
+
+	fs.StringVar(&components_delimiter, "components-delimiter", "|", "The delimiter used to separate multiple components in the \"component_ids\", \"component_labels\" and \"component_types\" columns")
+
+	fs.StringVar(&components_output, "components-output", "", "If present, write components to this path as a separate CSV file with \"id,position,component_id,component_label,component_type\" columns rather than as \"component_*\" columns. Implies -include-components.")
+
+	fs.StringVar(&lang, "lang", default_lang, fmt.Sprintf("A comma-separated list of preferred language tags (for example \"en,fr\") used to select the label for each %s. A tag matches labels with the same tag or a more specific tag (for example \"zh\" matches \"zh-Hani\"), \"und\" matches labels without a language tag and \"*\" matches any label. If no label matches the first label without a language tag, or failing that the first label, is used.", noun))
+
+	fs.BoolVar(&include_language, "include-language", false, fmt.Sprintf("If present, include the language tag and the (ISO 15924) script of the label for each %s as \"label_language\" and \"label_script\" columns", noun))
+
+	fs.StringVar(&labels_output, "labels-output", "", fmt.Sprintf("If present, write all the authoritative and variant labels, in all languages and scripts, for each %s to this path as a separate CSV file with \"id,label,language,script,type\" columns.", noun))
+
+	fs.BoolVar(&include_scope_note, "include-scope-note", false, fmt.Sprintf("If present, include the scope notes (skos:scopeNote) associated with each %s as a \"scope_note\" column. Multiple scope notes are separated by a single space.", noun))
+
+	fs.BoolVar(&include_variants, "include-variants", false, fmt.Sprintf("If present, include a list of variant (\"used for\") labels, and their language tags, associated with each %s", noun))
+
+	fs.StringVar(&variants_delimiter, "variants-delimiter", "|", "The delimiter used to separate multiple variant labels in the \"variants\" column")
+
+	fs.StringVar(&variants_output, "variants-output", "", "If present, write variant labels to this path as a separate CSV file with \"id,variant,language\" columns rather than as a \"variants\" column. Implies -include-variants.")
+
+	fs.BoolVar(&edges, "edges", false, fmt.Sprintf("If true, output a de-duplicated \"from,to,relation\" list of broader, narrower and related edges between %s rather than %s data. The -include-* flags are ignored.", plural, noun))
+
+	fs.BoolVar(&check_hierarchy, "check-hierarchy", false, fmt.Sprintf("If true, report asymmetric broader/narrower relationships between %s to STDERR once all the records have been processed.", plural))
+
+	fs.BoolVar(&include_all, "include-all", false, "If true will enable all the other -include-* flags")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Parse a Library of Congress MADS/RDF (`*.both.ndjson`), MARC 21 (MARCXML or ISO 2709), N-Triples or Turtle file and output CSV-encoded ID and label data.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options] uri(N) uri(N)\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// formats() returns the list of file formats, derived from the schemes of the registered `walk.Walker` implementations,
+// that can be parsed.
+func formats() []string {
+
+	schemes := walk.Schemes()
+	names := make([]string, len(schemes))
+
+	for i, s := range schemes {
+		names[i] = strings.TrimSuffix(s, "://")
+	}
+
+	return names
+}
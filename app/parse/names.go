@@ -0,0 +1,64 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/names"
+)
+
+// nameFieldnames is the list of columns written when the -include-name-parts flag is present.
+var nameFieldnames = []string{
+	"family_name",
+	"given_name",
+	"numeration",
+	"titles",
+	"birth_date",
+	"death_date",
+	"flourished",
+	"work_title",
+}
+
+// addNameParts() assigns the constituent parts of the heading for 'a' to 'out'. Dates are encoded as EDTF
+// strings and multiple titles are separated by "; ". Columns for names which are not personal, family or
+// name/title headings are left empty.
+func addNameParts(a *authority.Authority, out map[string]string) error {
+
+	for _, k := range nameFieldnames {
+		out[k] = ""
+	}
+
+	switch a.MADSType() {
+	case "PersonalName", "FamilyName", "NameTitle":
+		// pass
+	default:
+		return nil
+	}
+
+	n, err := names.Parse(a)
+
+	if err != nil {
+		return err
+	}
+
+	out["family_name"] = n.Family
+	out["given_name"] = n.Given
+	out["numeration"] = n.Numeration
+	out["titles"] = strings.Join(n.Titles, "; ")
+	out["work_title"] = n.Work
+
+	if n.Dates != nil {
+
+		if n.Dates.Birth != nil {
+			out["birth_date"] = n.Dates.Birth.EDTF()
+		}
+
+		if n.Dates.Death != nil {
+			out["death_date"] = n.Dates.Death.EDTF()
+		}
+
+		out["flourished"] = n.Dates.Flourished()
+	}
+
+	return nil
+}
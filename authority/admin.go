@@ -0,0 +1,156 @@
+package authority
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sfomuseum/go-libraryofcongress/internal/datetime"
+	"github.com/tidwall/gjson"
+)
+
+// type RecordInfo describes the administrative metadata (`ri:RecordInfo`) associated with an authority record.
+type RecordInfo struct {
+	// ChangeDate is the date that the record was changed (`ri:recordChangeDate`).
+	ChangeDate time.Time `json:"change_date"`
+	// Status is the status of the record (`ri:recordStatus`), for example "new" or "revised".
+	Status string `json:"status,omitempty"`
+	// ContentSource is the URI of the organization responsible for the change (`ri:recordContentSource`).
+	ContentSource string `json:"content_source,omitempty"`
+	// LanguageOfCataloging is the URI of the language used to catalog the record (`ri:languageOfCataloging`).
+	LanguageOfCataloging string `json:"language_of_cataloging,omitempty"`
+}
+
+// type ChangeSet describes a change (`cs:ChangeSet`) made to an authority record.
+type ChangeSet struct {
+	// CreatedDate is the date the change was made (`cs:createdDate`).
+	CreatedDate time.Time `json:"created_date"`
+	// Reason is the reason for the change (`cs:changeReason`), for example "new", "revised" or "deprecated".
+	Reason string `json:"reason,omitempty"`
+	// Creator is the URI of the organization responsible for the change (`cs:creatorName`).
+	Creator string `json:"creator,omitempty"`
+	// Subject is the URI of the authority record that was changed (`cs:subjectOfChange`).
+	Subject string `json:"subject,omitempty"`
+}
+
+// LastChange() returns the most recent `ChangeSet` for 'a' or nil if 'a' does not have a change history.
+func (a *Authority) LastChange() *ChangeSet {
+
+	if len(a.ChangeHistory) == 0 {
+		return nil
+	}
+
+	return a.ChangeHistory[len(a.ChangeHistory)-1]
+}
+
+// LastModified() returns the most recent date derived from the change history (`cs:createdDate`) and
+// administrative metadata (`ri:recordChangeDate`) for 'a'. If neither are present a zero value is returned.
+func (a *Authority) LastModified() time.Time {
+
+	var t time.Time
+
+	for _, cs := range a.ChangeHistory {
+
+		if cs.CreatedDate.After(t) {
+			t = cs.CreatedDate
+		}
+	}
+
+	for _, ri := range a.AdminMetadata {
+
+		if ri.ChangeDate.After(t) {
+			t = ri.ChangeDate
+		}
+	}
+
+	return t
+}
+
+// ChangedSince() returns a boolean value indicating whether 'a' was created, revised or deprecated after 't'.
+func (a *Authority) ChangedSince(t time.Time) bool {
+	return a.LastModified().After(t)
+}
+
+// ChangesSince() returns the list of `ChangeSet` instances for 'a' that were created after 't'.
+func (a *Authority) ChangesSince(t time.Time) []*ChangeSet {
+
+	changes := make([]*ChangeSet, 0)
+
+	for _, cs := range a.ChangeHistory {
+
+		if cs.CreatedDate.After(t) {
+			changes = append(changes, cs)
+		}
+	}
+
+	return changes
+}
+
+// sortChangeHistory() sorts the change history and administrative metadata for 'a' in ascending chronological order.
+func sortChangeHistory(a *Authority) {
+
+	sort.SliceStable(a.ChangeHistory, func(i, j int) bool {
+		return a.ChangeHistory[i].CreatedDate.Before(a.ChangeHistory[j].CreatedDate)
+	})
+
+	sort.SliceStable(a.AdminMetadata, func(i, j int) bool {
+		return a.AdminMetadata[i].ChangeDate.Before(a.AdminMetadata[j].ChangeDate)
+	})
+}
+
+// newRecordInfo() returns a new `RecordInfo` instance derived from 'n'.
+func newRecordInfo(n gjson.Result) *RecordInfo {
+
+	ri := &RecordInfo{
+		ChangeDate:           parseDateTime(n.Get("ri:recordChangeDate")),
+		Status:               literal(n.Get("ri:recordStatus")),
+		ContentSource:        n.Get("ri:recordContentSource.@id").String(),
+		LanguageOfCataloging: n.Get("ri:languageOfCataloging.@id").String(),
+	}
+
+	return ri
+}
+
+// newChangeSet() returns a new `ChangeSet` instance derived from 'n'.
+func newChangeSet(n gjson.Result) *ChangeSet {
+
+	cs := &ChangeSet{
+		CreatedDate: parseDateTime(n.Get("cs:createdDate")),
+		Reason:      literal(n.Get("cs:changeReason")),
+		Creator:     n.Get("cs:creatorName.@id").String(),
+		Subject:     n.Get("cs:subjectOfChange.@id").String(),
+	}
+
+	return cs
+}
+
+// ParseDateTime() parses 'str', an `xsd:dateTime` or `xsd:date` value, in to a `time.Time` instance. 'str' is expected
+// to be a date ("2006-01-02"), a date and time ("2006-01-02T15:04:05") or an RFC3339 timestamp. Dates and times without
+// a time zone are assumed to be UTC. It is a wrapper around the `datetime.Parse` method which is shared with the `walk`
+// package.
+func ParseDateTime(str string) (time.Time, error) {
+	return datetime.Parse(str)
+}
+
+// parseDateTime() returns a `time.Time` instance derived from 'rsp' which may be a plain string or a typed
+// JSON-LD value object (`{"@type": "xsd:dateTime", "@value": "..."}`). If 'rsp' can not be parsed a zero
+// value is returned.
+func parseDateTime(rsp gjson.Result) time.Time {
+
+	t, err := ParseDateTime(literal(rsp))
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// literal() returns the string value of 'rsp' which may be a plain string or a JSON-LD value object.
+func literal(rsp gjson.Result) string {
+
+	if rsp.IsObject() {
+		return rsp.Get("@value").String()
+	}
+
+	return rsp.String()
+}
@@ -0,0 +1,77 @@
+package authority
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangedSince(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[2])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	last := a.LastChange()
+
+	if last == nil || last.Reason != "revised" {
+		t.Fatalf("Unexpected last change: %v", last)
+	}
+
+	expected := time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC)
+
+	if !a.LastModified().Equal(expected) {
+		t.Fatalf("Unexpected last modified date: %v", a.LastModified())
+	}
+
+	since := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if !a.ChangedSince(since) {
+		t.Fatalf("Expected record to have changed since %v", since)
+	}
+
+	changes := a.ChangesSince(since)
+
+	if len(changes) != 1 || changes[0].Reason != "revised" {
+		t.Fatalf("Unexpected changes since %v: %v", since, changes)
+	}
+
+	if a.ChangedSince(expected) {
+		t.Fatalf("Did not expect record to have changed since %v", expected)
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+
+	tests := map[string]time.Time{
+		"2011-06-04":                time.Date(2011, 6, 4, 0, 0, 0, 0, time.UTC),
+		"2011-06-04T08:27:18":       time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+		"2011-06-04T08:27:18Z":      time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+		"2011-06-04T10:27:18+02:00": time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+	}
+
+	for str, expected := range tests {
+
+		d, err := ParseDateTime(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if !d.Equal(expected) {
+			t.Fatalf("Unexpected date for '%s': %v", str, d)
+		}
+	}
+
+	for _, str := range []string{"", "June 4th"} {
+
+		_, err := ParseDateTime(str)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", str)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+// Package authority provides methods for decoding Library of Congress MADS/RDF authority records, as
+// published in the id.loc.gov `*.both.ndjson` files, in to typed Go structures.
+package authority
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// MADS/RDF external authority match types.
+const (
+	// MatchClose indicates an external authority derived from `madsrdf:hasCloseExternalAuthority`.
+	MatchClose string = "close"
+	// MatchExact indicates an external authority derived from `madsrdf:hasExactExternalAuthority`.
+	MatchExact string = "exact"
+)
+
+// madsTypes is the list of MADS/RDF authority classes recognized by the `MADSType` method. Compound classes
+// (for example "NameTitle") are listed first since records may also carry the types of their components.
+var madsTypes = []string{
+	"NameTitle",
+	"ComplexSubject",
+	"HierarchicalGeographic",
+	"ConferenceName",
+	"CorporateName",
+	"FamilyName",
+	"GenreForm",
+	"Geographic",
+	"Language",
+	"PersonalName",
+	"Temporal",
+	"Title",
+	"Topic",
+}
+
+// type Authority is a typed representation of a MADS/RDF authority record.
+type Authority struct {
+	// ID is the URI of the authority, for example "http://id.loc.gov/authorities/subjects/sh85016999".
+	ID string `json:"id"`
+	// LCCN is the Library of Congress Control Number (`identifiers:lccn`) for the authority.
+	LCCN string `json:"lccn,omitempty"`
+	// Types is the list of `@type` values for the authority.
+	Types []string `json:"types"`
+	// Labels is the list of authoritative labels (`madsrdf:authoritativeLabel`) for the authority. Deprecated
+	// authorities do not have an authoritative label so their variant labels (`madsrdf:variantLabel`) are used instead.
+	// SKOS concepts which are not MADS/RDF authorities use their preferred labels (`skos:prefLabel` and `skosxl:prefLabel`).
+	Labels []*Label `json:"labels"`
+	// Elements is the list of elements (`madsrdf:elementList`) that make up the authoritative label.
+	Elements []*Element `json:"elements,omitempty"`
+	// Components is the list of components (`madsrdf:componentList`), in order, for pre-coordinated
+	// (`madsrdf:ComplexSubject`) authorities.
+	Components []*Component `json:"components,omitempty"`
+	// Variants is the list of variants (`madsrdf:hasVariant`) for the authority.
+	Variants []*Variant `json:"variants,omitempty"`
+	// AltLabels is the list of alternate labels (`skos:altLabel` and `skosxl:altLabel`) for the authority.
+	AltLabels []*Label `json:"alt_labels,omitempty"`
+	// Broader is the list of URIs for broader authorities (`skos:broader` and `madsrdf:hasBroaderAuthority`).
+	Broader []string `json:"broader,omitempty"`
+	// Narrower is the list of URIs for narrower authorities (`skos:narrower` and `madsrdf:hasNarrowerAuthority`).
+	Narrower []string `json:"narrower,omitempty"`
+	// Related is the list of URIs for related authorities (`skos:related` and `madsrdf:hasReciprocalAuthority`).
+	Related []string `json:"related,omitempty"`
+	// UseInstead is the list of URIs for the authorities that should be used instead of a deprecated
+	// authority (`madsrdf:useInstead`).
+	UseInstead []string `json:"use_instead,omitempty"`
+	// SameAs is the list of URIs that identify the same resource as the authority (`owl:sameAs`).
+	SameAs []string `json:"same_as,omitempty"`
+	// ExternalAuthorities is the list of external authorities (`madsrdf:hasCloseExternalAuthority` and
+	// `madsrdf:hasExactExternalAuthority` or `skos:closeMatch` and `skos:exactMatch`) associated with the authority.
+	ExternalAuthorities []*ExternalAuthority `json:"external_authorities,omitempty"`
+	// ScopeNotes is the list of scope notes (`skos:scopeNote`) for the authority.
+	ScopeNotes []*Label `json:"scope_notes,omitempty"`
+	// Sources is the list of sources (`madsrdf:hasSource`) consulted when establishing the authority.
+	Sources []*Source `json:"sources,omitempty"`
+	// Classifications is the list of Library of Congress Classification numbers (`madsrdf:classification`)
+	// associated with the authority.
+	Classifications []*Classification `json:"classifications,omitempty"`
+	// Collections is the list of URIs for the MADS/RDF collections (`madsrdf:isMemberOfMADSCollection`) the
+	// authority is a member of, for example "http://id.loc.gov/authorities/subjects/collection_LCSH_General".
+	Collections []string `json:"collections,omitempty"`
+	// AdminMetadata is the list of administrative metadata records (`ri:RecordInfo`) for the authority, in
+	// ascending chronological order.
+	AdminMetadata []*RecordInfo `json:"admin_metadata,omitempty"`
+	// ChangeHistory is the list of changes (`cs:ChangeSet`) made to the authority, in ascending chronological order.
+	ChangeHistory []*ChangeSet `json:"change_history,omitempty"`
+}
+
+// type Element is a typed component (for example `madsrdf:TopicElement` or `madsrdf:FullNameElement`) of
+// an authoritative or variant label.
+type Element struct {
+	// Type is the `@type` of the element.
+	Type string `json:"type"`
+	// Value is the value (`madsrdf:elementValue`) of the element.
+	Value *Label `json:"value"`
+}
+
+// type Variant is a variant (`madsrdf:Variant`) of an authority.
+type Variant struct {
+	// Types is the list of `@type` values for the variant.
+	Types []string `json:"types"`
+	// Labels is the list of variant labels (`madsrdf:variantLabel`) for the variant.
+	Labels []*Label `json:"labels"`
+	// Elements is the list of elements (`madsrdf:elementList`) that make up the variant label.
+	Elements []*Element `json:"elements,omitempty"`
+}
+
+// type ExternalAuthority is a pointer to a matching authority in an external (non-LoC) vocabulary.
+type ExternalAuthority struct {
+	// ID is the URI of the external authority.
+	ID string `json:"id"`
+	// Label is the label of the external authority, if present in the record.
+	Label string `json:"label,omitempty"`
+	// Match is the type of match, either `MatchClose` or `MatchExact`.
+	Match string `json:"match"`
+}
+
+// ParseRecord() decodes 'body', a JSON-LD document containing a `@graph` property, and returns an `Authority`
+// instance for the primary authority it describes. Blank node references (for example `madsrdf:elementList`,
+// `madsrdf:adminMetadata` and `madsrdf:hasVariant`) are resolved against the other nodes in the graph.
+func ParseRecord(body []byte) (*Authority, error) {
+
+	g, err := newGraph(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := g.primary()
+
+	if !ok {
+		return nil, fmt.Errorf("Unable to determine primary authority for record")
+	}
+
+	return newAuthority(g, n), nil
+}
+
+// ParseRecords() decodes 'body', a JSON-LD document containing a `@graph` property, and returns an `Authority` instance
+// for every authority it describes, in the order they appear in the graph. In addition to the primary authority the
+// id.loc.gov `*.both.ndjson` records include abbreviated descriptions (typically just an authoritative label) of the
+// authorities they reference, for example broader and narrower headings.
+func ParseRecords(body []byte) ([]*Authority, error) {
+
+	g, err := newGraph(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := g.authorities()
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("Unable to determine authorities for record")
+	}
+
+	authorities := make([]*Authority, len(nodes))
+
+	for i, n := range nodes {
+		authorities[i] = newAuthority(g, n)
+	}
+
+	return authorities, nil
+}
+
+// Identifier() returns the final path component of the authority's URI, for example "sh85016999".
+func (a *Authority) Identifier() string {
+	return path.Base(a.ID)
+}
+
+// Label() returns the value of the first authoritative label for 'a'.
+func (a *Authority) Label() string {
+	return firstLabel(a.Labels)
+}
+
+// HasType() returns a boolean value indicating whether 'a' has a `@type` value matching 't'.
+func (a *Authority) HasType(t string) bool {
+
+	for _, v := range a.Types {
+
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MADSType() returns the MADS/RDF authority class for 'a', without the "madsrdf:" prefix, for example "PersonalName",
+// "CorporateName" or "Topic". If 'a' does not have a known MADS/RDF authority class an empty string is returned.
+func (a *Authority) MADSType() string {
+	return madsType(a.Types)
+}
+
+// Label() returns the value of the first variant label for 'v'.
+func (v *Variant) Label() string {
+	return firstLabel(v.Labels)
+}
+
+// newAuthority() returns a new `Authority` instance derived from 'n' resolving references against 'g'.
+func newAuthority(g *graph, n gjson.Result) *Authority {
+
+	a := &Authority{
+		ID:         n.Get("@id").String(),
+		LCCN:       literal(n.Get("identifiers:lccn")),
+		Types:      types(n),
+		Labels:     labels(n.Get("madsrdf:authoritativeLabel")),
+		Elements:   elements(g, n.Get("madsrdf:elementList")),
+		Components: components(g, n.Get("madsrdf:componentList")),
+		Variants:   make([]*Variant, 0),
+		AltLabels:  altLabels(g, n),
+		Broader: uniqueReferences(
+			n.Get("skos:broader"),
+			n.Get("madsrdf:hasBroaderAuthority"),
+		),
+		Narrower: uniqueReferences(
+			n.Get("skos:narrower"),
+			n.Get("madsrdf:hasNarrowerAuthority"),
+		),
+		Related: uniqueReferences(
+			n.Get("skos:related"),
+			n.Get("madsrdf:hasReciprocalAuthority"),
+		),
+		UseInstead:          uniqueReferences(n.Get("madsrdf:useInstead")),
+		SameAs:              uniqueReferences(n.Get("owl:sameAs")),
+		ExternalAuthorities: make([]*ExternalAuthority, 0),
+		ScopeNotes:          labels(n.Get("skos:scopeNote")),
+		Sources:             sources(g, n.Get("madsrdf:hasSource")),
+		Classifications:     classifications(g, n.Get("madsrdf:classification")),
+		Collections:         uniqueReferences(n.Get("madsrdf:isMemberOfMADSCollection")),
+		AdminMetadata:       make([]*RecordInfo, 0),
+		ChangeHistory:       make([]*ChangeSet, 0),
+	}
+
+	if len(a.Labels) == 0 && hasType(n, "madsrdf:DeprecatedAuthority") {
+		a.Labels = labels(n.Get("madsrdf:variantLabel"))
+	}
+
+	if len(a.Labels) == 0 {
+		a.Labels = prefLabels(g, n)
+	}
+
+	for _, v := range g.resolve(n.Get("madsrdf:hasVariant")) {
+
+		variant := &Variant{
+			Types:    types(v),
+			Labels:   labels(v.Get("madsrdf:variantLabel")),
+			Elements: elements(g, v.Get("madsrdf:elementList")),
+		}
+
+		a.Variants = append(a.Variants, variant)
+	}
+
+	external := map[string][]gjson.Result{
+		MatchClose: {
+			n.Get("madsrdf:hasCloseExternalAuthority"),
+			n.Get("skos:closeMatch"),
+		},
+		MatchExact: {
+			n.Get("madsrdf:hasExactExternalAuthority"),
+			n.Get("skos:exactMatch"),
+		},
+	}
+
+	for _, match := range []string{MatchClose, MatchExact} {
+
+		for _, id := range uniqueReferences(external[match]...) {
+
+			ext := &ExternalAuthority{
+				ID:    id,
+				Match: match,
+			}
+
+			ext_n, ok := g.node(id)
+
+			if ok {
+
+				ext.Label = externalLabel(ext_n.Get("madsrdf:authoritativeLabel"))
+
+				if ext.Label == "" {
+					ext.Label = firstLabel(prefLabels(g, ext_n))
+				}
+			}
+
+			a.ExternalAuthorities = append(a.ExternalAuthorities, ext)
+		}
+	}
+
+	// Record info and change sets are referenced by both `madsrdf:adminMetadata` and
+	// `skos:changeNote` so we sort them by type rather than by property.
+
+	admin := make([]gjson.Result, 0)
+	admin = append(admin, g.resolve(n.Get("madsrdf:adminMetadata"))...)
+	admin = append(admin, g.resolve(n.Get("skos:changeNote"))...)
+
+	seen := make(map[string]bool)
+
+	for _, m := range admin {
+
+		id := m.Get("@id").String()
+
+		if id != "" {
+
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+		}
+
+		switch {
+		case hasType(m, "ri:RecordInfo"):
+			a.AdminMetadata = append(a.AdminMetadata, newRecordInfo(m))
+		case hasType(m, "cs:ChangeSet"):
+			a.ChangeHistory = append(a.ChangeHistory, newChangeSet(m))
+		}
+	}
+
+	sortChangeHistory(a)
+
+	return a
+}
+
+// madsType() returns the first MADS/RDF authority class, in the order defined by `madsTypes`, in 't' without
+// the "madsrdf:" prefix or an empty string if 't' does not contain a known MADS/RDF authority class.
+func madsType(t []string) string {
+
+	for _, name := range madsTypes {
+
+		for _, v := range t {
+
+			if v == "madsrdf:"+name {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// elements() returns the list of `Element` instances derived from 'rsp' (a `madsrdf:elementList` property)
+// resolving references against 'g'.
+func elements(g *graph, rsp gjson.Result) []*Element {
+
+	el := make([]*Element, 0)
+
+	for _, e := range g.resolve(rsp) {
+
+		t := ""
+		e_types := types(e)
+
+		if len(e_types) > 0 {
+			t = e_types[0]
+		}
+
+		value := newLabel(e.Get("madsrdf:elementValue"))
+
+		if value == nil {
+			continue
+		}
+
+		el = append(el, &Element{
+			Type:  t,
+			Value: value,
+		})
+	}
+
+	return el
+}
+
+// uniqueReferences() returns the de-duplicated list of `@id` values referenced by one or more 'properties'. Blank
+// nodes are excluded since they can not be referenced outside of the record they are defined in.
+func uniqueReferences(properties ...gjson.Result) []string {
+
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, rsp := range properties {
+
+		for _, id := range references(rsp) {
+
+			if seen[id] || isBlankNode(id) {
+				continue
+			}
+
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// externalLabel() returns the label for an external authority. These are published as quoted
+// strings with trailing whitespace (for example `"\"Broadband amplifiers\" "`) so the quotes and
+// whitespace are removed.
+func externalLabel(rsp gjson.Result) string {
+
+	str := strings.TrimSpace(literal(rsp))
+
+	if len(str) >= 2 && strings.HasPrefix(str, `"`) && strings.HasSuffix(str, `"`) {
+		str = str[1 : len(str)-1]
+	}
+
+	return str
+}
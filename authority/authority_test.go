@@ -0,0 +1,229 @@
+package authority
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadFixtures() returns the list of records in the "lcsh.sample.ndjson" fixture file.
+func loadFixtures(t *testing.T) [][]byte {
+	return loadFixtureFile(t, "../fixtures/lcsh.sample.ndjson")
+}
+
+// loadFixtureFile() returns the list of records in the fixture file 'rel_path'.
+func loadFixtureFile(t *testing.T, rel_path string) [][]byte {
+
+	abs_path, err := filepath.Abs(rel_path)
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+	}
+
+	fh, err := os.Open(abs_path)
+
+	if err != nil {
+		t.Fatalf("Failed to open %s, %v", abs_path, err)
+	}
+
+	defer fh.Close()
+
+	records := make([][]byte, 0)
+
+	scanner := bufio.NewScanner(fh)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+
+	for scanner.Scan() {
+		body := make([]byte, len(scanner.Bytes()))
+		copy(body, scanner.Bytes())
+		records = append(records, body)
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", abs_path, err)
+	}
+
+	return records
+}
+
+func TestParseRecord(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	if len(records) != 3 {
+		t.Fatalf("Unexpected number of fixtures: %d", len(records))
+	}
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.ID != "http://id.loc.gov/authorities/subjects/sh85016999" {
+		t.Fatalf("Unexpected ID: %s", a.ID)
+	}
+
+	if a.Identifier() != "sh85016999" {
+		t.Fatalf("Unexpected identifier: %s", a.Identifier())
+	}
+
+	if a.LCCN != "sh 85016999" {
+		t.Fatalf("Unexpected LCCN: %s", a.LCCN)
+	}
+
+	if !a.HasType("madsrdf:Topic") {
+		t.Fatalf("Expected madsrdf:Topic type")
+	}
+
+	if a.Label() != "Broadband amplifiers" {
+		t.Fatalf("Unexpected label: %s", a.Label())
+	}
+
+	if a.Labels[0].Language != "en" {
+		t.Fatalf("Unexpected label language: %s", a.Labels[0].Language)
+	}
+
+	if len(a.Elements) != 1 || a.Elements[0].Type != "madsrdf:TopicElement" || a.Elements[0].Value.Value != "Broadband amplifiers" {
+		t.Fatalf("Unexpected elements: %v", a.Elements)
+	}
+
+	if len(a.Variants) != 1 || a.Variants[0].Label() != "Wide-band amplifiers" {
+		t.Fatalf("Unexpected variants: %v", a.Variants)
+	}
+
+	if len(a.Variants[0].Elements) != 1 {
+		t.Fatalf("Expected variant element list to be resolved")
+	}
+
+	if len(a.Broader) != 1 || a.Broader[0] != "http://id.loc.gov/authorities/subjects/sh85004652" {
+		t.Fatalf("Unexpected broader: %v", a.Broader)
+	}
+
+	if len(a.Narrower) != 1 || a.Narrower[0] != "http://id.loc.gov/authorities/subjects/sh85038540" {
+		t.Fatalf("Unexpected narrower: %v", a.Narrower)
+	}
+
+	if len(a.ExternalAuthorities) != 2 {
+		t.Fatalf("Unexpected external authorities: %d", len(a.ExternalAuthorities))
+	}
+
+	ext := a.ExternalAuthorities[1]
+
+	if ext.ID != "http://id.worldcat.org/fast/839142" || ext.Label != "Broadband amplifiers" || ext.Match != MatchClose {
+		t.Fatalf("Unexpected external authority: %v", ext)
+	}
+
+	if len(a.AdminMetadata) != 2 {
+		t.Fatalf("Unexpected admin metadata: %d", len(a.AdminMetadata))
+	}
+
+	if a.AdminMetadata[0].Status != "new" || a.AdminMetadata[0].ChangeDate.Year() != 1986 {
+		t.Fatalf("Unexpected record info: %v", a.AdminMetadata[0])
+	}
+
+	if a.AdminMetadata[1].Status != "revised" || a.AdminMetadata[1].ChangeDate.Year() != 1988 {
+		t.Fatalf("Unexpected record info: %v", a.AdminMetadata[1])
+	}
+
+	if len(a.ChangeHistory) != 2 {
+		t.Fatalf("Unexpected change history: %d", len(a.ChangeHistory))
+	}
+
+	if a.ChangeHistory[0].Reason != "new" || a.ChangeHistory[0].CreatedDate.Year() != 1986 {
+		t.Fatalf("Unexpected change set: %v", a.ChangeHistory[0])
+	}
+
+	if a.ChangeHistory[1].Reason != "revised" || a.ChangeHistory[1].Subject != a.ID {
+		t.Fatalf("Unexpected change set: %v", a.ChangeHistory[1])
+	}
+}
+
+func TestParseRecordWithoutContext(t *testing.T) {
+
+	body := []byte(`{"@graph": [{"@id": "_:N1", "@type": "madsrdf:TopicElement"}, {"@id": "http://id.loc.gov/authorities/names/n90699999", "@type": ["madsrdf:Authority", "madsrdf:PersonalName"], "madsrdf:authoritativeLabel": "Birkan, Kaarin"}]}`)
+
+	a, err := ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.Identifier() != "n90699999" {
+		t.Fatalf("Unexpected identifier: %s", a.Identifier())
+	}
+
+	if a.Label() != "Birkan, Kaarin" {
+		t.Fatalf("Unexpected label: %s", a.Label())
+	}
+
+	_, err = ParseRecord([]byte(`{"@id": "http://example.com"}`))
+
+	if err == nil {
+		t.Fatalf("Expected record without @graph property to fail")
+	}
+}
+
+func TestMADSType(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	expected := []string{
+		"Topic",
+		"FamilyName",
+		"Geographic",
+	}
+
+	for i, body := range records {
+
+		a, err := ParseRecord(body)
+
+		if err != nil {
+			t.Fatalf("Failed to parse record at offset %d, %v", i, err)
+		}
+
+		if a.MADSType() != expected[i] {
+			t.Fatalf("Unexpected MADS type for %s: %s (expected %s)", a.ID, a.MADSType(), expected[i])
+		}
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	expected := [][]string{
+		{"sh85004652", "sh85016999", "sh85038540", "4146535-0", "839142"},
+		{"sh2004004999", "1589347"},
+		{"1797194", "Q31886764", "sh96009999", "1278640", "sh96010001"},
+	}
+
+	for i, body := range records {
+
+		authorities, err := ParseRecords(body)
+
+		if err != nil {
+			t.Fatalf("Failed to parse record at offset %d, %v", i, err)
+		}
+
+		ids := make([]string, len(authorities))
+
+		for j, a := range authorities {
+			ids[j] = a.Identifier()
+		}
+
+		if strings.Join(ids, ",") != strings.Join(expected[i], ",") {
+			t.Fatalf("Unexpected authorities for record at offset %d: %v (expected %v)", i, ids, expected[i])
+		}
+	}
+
+	_, err := ParseRecords([]byte(`{"@graph": [{"@id": "_:N1", "@type": "madsrdf:TopicElement"}]}`))
+
+	if err == nil {
+		t.Fatalf("Expected record without authorities to fail")
+	}
+}
@@ -0,0 +1,58 @@
+package authority
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// type Classification is a Library of Congress Classification (LCC) number, or range of numbers, associated
+// with an authority (`madsrdf:classification`).
+type Classification struct {
+	// Code is the class number or range of class numbers, for example "TK7871.58.B74" or "QA75-QA76.95".
+	Code string `json:"code"`
+	// URI is the URI of the class number, if present, for example "http://id.loc.gov/authorities/classification/TK7871.58.B74".
+	URI string `json:"uri,omitempty"`
+}
+
+// ClassificationCodes() returns the list of LC Classification codes for 'a'.
+func (a *Authority) ClassificationCodes() []string {
+
+	codes := make([]string, len(a.Classifications))
+
+	for i, c := range a.Classifications {
+		codes[i] = c.Code
+	}
+
+	return codes
+}
+
+// classifications() returns the list of `Classification` instances derived from 'rsp' (a `madsrdf:classification`
+// property) resolving references against 'g'. Values may be plain (or typed) literals or `lcc:ClassNumber` nodes.
+func classifications(g *graph, rsp gjson.Result) []*Classification {
+
+	cl := make([]*Classification, 0)
+	seen := make(map[string]bool)
+
+	for _, n := range g.resolve(rsp) {
+
+		c := &Classification{}
+
+		switch {
+		case n.IsObject() && n.Get("madsrdf:code").Exists():
+			c.Code = literal(n.Get("madsrdf:code"))
+			c.URI = n.Get("madsrdf:hasExactExternalAuthority.@id").String()
+		case n.IsObject() && n.Get("@value").Exists():
+			c.Code = literal(n)
+		case n.Type == gjson.String:
+			c.Code = n.String()
+		}
+
+		if c.Code == "" || seen[c.Code] {
+			continue
+		}
+
+		seen[c.Code] = true
+		cl = append(cl, c)
+	}
+
+	return cl
+}
@@ -0,0 +1,41 @@
+package authority
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifications(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if len(a.Classifications) != 1 {
+		t.Fatalf("Unexpected classifications: %d", len(a.Classifications))
+	}
+
+	c := a.Classifications[0]
+
+	if c.Code != "TK7871.58.B74" || c.URI != "http://id.loc.gov/authorities/classification/TK7871.58.B74" {
+		t.Fatalf("Unexpected classification: %v", c)
+	}
+
+	body := []byte(`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85029534", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": "Computers", "madsrdf:classification": ["QA75-QA76.95", {"@value": "TK7885-TK7895"}, "QA75-QA76.95"]}]}`)
+
+	a, err = ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	codes := strings.Join(a.ClassificationCodes(), ",")
+
+	if codes != "QA75-QA76.95,TK7885-TK7895" {
+		t.Fatalf("Unexpected classification codes: %s", codes)
+	}
+}
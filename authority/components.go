@@ -0,0 +1,109 @@
+package authority
+
+import (
+	"path"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// type Component is a component (`madsrdf:componentList`) of a pre-coordinated (`madsrdf:ComplexSubject`)
+// heading, for example "Italy" in "Anarchism--Italy--History--20th century".
+type Component struct {
+	// ID is the URI of the component authority. Components which are blank nodes do not have an ID.
+	ID string `json:"id,omitempty"`
+	// Type is the MADS/RDF authority class (facet) of the component, without the "madsrdf:" prefix, for
+	// example "Topic", "Geographic", "Temporal" or "GenreForm".
+	Type string `json:"type"`
+	// Labels is the list of authoritative labels (`madsrdf:authoritativeLabel`) for the component.
+	Labels []*Label `json:"labels"`
+	// Elements is the list of elements (`madsrdf:elementList`) that make up the component label.
+	Elements []*Element `json:"elements,omitempty"`
+}
+
+// Identifier() returns the final path element of the URI for 'c', for example "sh85070736". If 'c' is a
+// blank node an empty string is returned.
+func (c *Component) Identifier() string {
+
+	if c.ID == "" {
+		return ""
+	}
+
+	return path.Base(c.ID)
+}
+
+// Label() returns the value of the first authoritative label for 'c'.
+func (c *Component) Label() string {
+	return firstLabel(c.Labels)
+}
+
+// IsComplex() returns a boolean value indicating whether 'a' is a pre-coordinated heading composed of
+// two or more components.
+func (a *Authority) IsComplex() bool {
+	return len(a.Components) > 0
+}
+
+// IsSubdivision() returns a boolean value indicating whether 'a' is a (free-floating) subdivision, for example
+// "History" or "20th century", that may be appended to other headings. This is determined by membership in one
+// of the LCSH subdivision collections, for example "collection_Subdivisions" or "collection_GeneralSubdivision".
+func (a *Authority) IsSubdivision() bool {
+
+	for _, c := range a.Collections {
+
+		if strings.Contains(path.Base(c), "Subdivision") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// components() returns the list of `Component` instances derived from 'rsp' (a `madsrdf:componentList` property)
+// resolving references against 'g'.
+func components(g *graph, rsp gjson.Result) []*Component {
+
+	cl := make([]*Component, 0)
+
+	for _, n := range g.resolve(rsp) {
+
+		id := n.Get("@id").String()
+
+		if isBlankNode(id) {
+			id = ""
+		}
+
+		c := &Component{
+			ID:       id,
+			Type:     madsType(types(n)),
+			Labels:   labels(n.Get("madsrdf:authoritativeLabel")),
+			Elements: elements(g, n.Get("madsrdf:elementList")),
+		}
+
+		// Derive the facet from the element types if the component does not declare an authority
+		// class, for example "madsrdf:TemporalElement" becomes "Temporal".
+
+		if c.Type == "" && len(c.Elements) > 0 {
+			c.Type = strings.TrimSuffix(strings.TrimPrefix(c.Elements[0].Type, "madsrdf:"), "Element")
+		}
+
+		if len(c.Labels) == 0 && len(c.Elements) > 0 {
+
+			values := make([]string, len(c.Elements))
+
+			for i, e := range c.Elements {
+				values[i] = e.Value.Value
+			}
+
+			c.Labels = []*Label{
+				{
+					Value:    strings.Join(values, " "),
+					Language: c.Elements[0].Value.Language,
+				},
+			}
+		}
+
+		cl = append(cl, c)
+	}
+
+	return cl
+}
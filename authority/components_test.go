@@ -0,0 +1,101 @@
+package authority
+
+import (
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+
+	body := []byte(`{"@context": {"about": "http://id.loc.gov/authorities/subjects/sh2008100001"}, "@graph": [
+{"@id": "http://id.loc.gov/authorities/subjects/sh2008100001", "@type": ["madsrdf:Authority", "madsrdf:ComplexSubject"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Anarchism--Italy--History--20th century"}, "madsrdf:componentList": {"@list": [{"@id": "http://id.loc.gov/authorities/subjects/sh85004812"}, {"@id": "_:N2"}, {"@id": "_:N3"}, {"@id": "_:N4"}]}},
+{"@id": "http://id.loc.gov/authorities/subjects/sh85004812", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Anarchism"}},
+{"@id": "_:N2", "@type": ["madsrdf:Authority", "madsrdf:Geographic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Italy"}, "madsrdf:elementList": {"@list": [{"@id": "_:N5"}]}},
+{"@id": "_:N3", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "History"}},
+{"@id": "_:N4", "@type": "madsrdf:Authority", "madsrdf:elementList": {"@list": [{"@id": "_:N6"}]}},
+{"@id": "_:N5", "@type": "madsrdf:GeographicElement", "madsrdf:elementValue": {"@language": "en", "@value": "Italy"}},
+{"@id": "_:N6", "@type": "madsrdf:TemporalElement", "madsrdf:elementValue": {"@language": "en", "@value": "20th century"}}
+]}`)
+
+	a, err := ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if !a.IsComplex() {
+		t.Fatalf("Expected record to be a complex heading")
+	}
+
+	expected := [][3]string{
+		{"sh85004812", "Anarchism", "Topic"},
+		{"", "Italy", "Geographic"},
+		{"", "History", "Topic"},
+		{"", "20th century", "Temporal"},
+	}
+
+	if len(a.Components) != len(expected) {
+		t.Fatalf("Unexpected number of components: %d", len(a.Components))
+	}
+
+	for i, c := range a.Components {
+
+		if c.Identifier() != expected[i][0] {
+			t.Fatalf("Unexpected identifier for component %d: '%s'", i, c.Identifier())
+		}
+
+		if c.Label() != expected[i][1] {
+			t.Fatalf("Unexpected label for component %d: '%s'", i, c.Label())
+		}
+
+		if c.Type != expected[i][2] {
+			t.Fatalf("Unexpected type for component %d: '%s'", i, c.Type)
+		}
+	}
+
+	if len(a.Components[1].Elements) != 1 || a.Components[1].Elements[0].Type != "madsrdf:GeographicElement" {
+		t.Fatalf("Expected component element list to be resolved")
+	}
+
+	records := loadFixtures(t)
+
+	simple, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if simple.IsComplex() {
+		t.Fatalf("Did not expect %s to be a complex heading", simple.ID)
+	}
+}
+
+func TestIsSubdivision(t *testing.T) {
+
+	body := []byte(`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh99005024", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "History"}, "madsrdf:isMemberOfMADSCollection": [{"@id": "http://id.loc.gov/authorities/subjects/collection_Subdivisions"}, {"@id": "http://id.loc.gov/authorities/subjects/collection_GeneralSubdivision"}]}]}`)
+
+	a, err := ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if len(a.Collections) != 2 {
+		t.Fatalf("Unexpected collections: %v", a.Collections)
+	}
+
+	if !a.IsSubdivision() {
+		t.Fatalf("Expected %s to be a subdivision", a.ID)
+	}
+
+	records := loadFixtures(t)
+
+	a, err = ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.IsSubdivision() {
+		t.Fatalf("Did not expect %s to be a subdivision", a.ID)
+	}
+}
@@ -0,0 +1,96 @@
+package authority
+
+import (
+	"path"
+	"strings"
+)
+
+// RecordStatus() returns the status (`ri:recordStatus`) of the most recent administrative metadata record
+// for 'a', for example "new", "revised" or "deprecated". If 'a' has no administrative metadata an empty
+// string is returned.
+func (a *Authority) RecordStatus() string {
+
+	if len(a.AdminMetadata) == 0 {
+		return ""
+	}
+
+	return a.AdminMetadata[len(a.AdminMetadata)-1].Status
+}
+
+// IsDeprecated() returns a boolean value indicating whether 'a' is a deprecated (or cancelled) authority. An
+// authority is considered deprecated if it has a `madsrdf:DeprecatedAuthority` type or if its most recent record
+// status or change reason is "deprecated".
+func (a *Authority) IsDeprecated() bool {
+
+	if a.HasType("madsrdf:DeprecatedAuthority") {
+		return true
+	}
+
+	if a.RecordStatus() == "deprecated" {
+		return true
+	}
+
+	cs := a.LastChange()
+
+	if cs != nil && cs.Reason == "deprecated" {
+		return true
+	}
+
+	return false
+}
+
+// Status() returns "deprecated" if 'a' is a deprecated authority, otherwise it returns the value of `RecordStatus()`.
+func (a *Authority) Status() string {
+
+	if a.IsDeprecated() {
+		return "deprecated"
+	}
+
+	return a.RecordStatus()
+}
+
+// Replacements() returns the de-duplicated list of URIs for the authorities that replace 'a'. These are derived
+// from the `madsrdf:useInstead` property followed by any `owl:sameAs` pointers to other authorities in the same
+// id.loc.gov scheme as 'a'. If 'a' is not deprecated an empty list is returned.
+func (a *Authority) Replacements() []string {
+
+	replacements := make([]string, 0)
+
+	if !a.IsDeprecated() {
+		return replacements
+	}
+
+	seen := map[string]bool{
+		a.ID: true,
+	}
+
+	candidates := make([]string, 0)
+	candidates = append(candidates, a.UseInstead...)
+
+	scheme := path.Dir(a.ID)
+
+	for _, uri := range a.SameAs {
+
+		if !strings.HasPrefix(uri, "http://id.loc.gov/authorities/") {
+			continue
+		}
+
+		if strings.Contains(uri, "#") || path.Dir(uri) != scheme {
+			continue
+		}
+
+		candidates = append(candidates, uri)
+	}
+
+	for _, uri := range candidates {
+
+		if seen[uri] {
+			continue
+		}
+
+		seen[uri] = true
+		replacements = append(replacements, uri)
+	}
+
+	return replacements
+}
@@ -0,0 +1,70 @@
+package authority
+
+import (
+	"testing"
+)
+
+func TestDeprecated(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.IsDeprecated() {
+		t.Fatalf("Did not expect %s to be deprecated", a.ID)
+	}
+
+	if a.Status() != "revised" {
+		t.Fatalf("Unexpected status for %s: %s", a.ID, a.Status())
+	}
+
+	if len(a.SameAs) != 2 {
+		t.Fatalf("Unexpected same as for %s: %v", a.ID, a.SameAs)
+	}
+
+	if len(a.Replacements()) != 0 {
+		t.Fatalf("Did not expect replacements for %s", a.ID)
+	}
+
+	body := []byte(`{"@context": {"about": "http://id.loc.gov/authorities/subjects/sh00000001"}, "@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh00000001", "@type": ["madsrdf:DeprecatedAuthority", "madsrdf:Topic"], "madsrdf:variantLabel": {"@language": "en", "@value": "Old heading"}, "madsrdf:useInstead": {"@id": "http://id.loc.gov/authorities/subjects/sh00000002"}, "owl:sameAs": [{"@id": "info:lc/authorities/sh00000001"}, {"@id": "http://id.loc.gov/authorities/subjects/sh00000001#concept"}, {"@id": "http://id.loc.gov/authorities/subjects/sh00000002"}, {"@id": "http://id.loc.gov/authorities/subjects/sh00000003"}], "madsrdf:adminMetadata": {"@id": "_:N1"}}, {"@id": "_:N1", "@type": "ri:RecordInfo", "ri:recordChangeDate": {"@type": "xsd:dateTime", "@value": "2011-06-04T08:27:18"}, "ri:recordStatus": "deprecated"}]}`)
+
+	a, err = ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse deprecated record, %v", err)
+	}
+
+	if !a.IsDeprecated() {
+		t.Fatalf("Expected %s to be deprecated", a.ID)
+	}
+
+	if a.RecordStatus() != "deprecated" || a.Status() != "deprecated" {
+		t.Fatalf("Unexpected status for %s: %s", a.ID, a.Status())
+	}
+
+	if a.Label() != "Old heading" {
+		t.Fatalf("Unexpected label for %s: %s", a.ID, a.Label())
+	}
+
+	expected := []string{
+		"http://id.loc.gov/authorities/subjects/sh00000002",
+		"http://id.loc.gov/authorities/subjects/sh00000003",
+	}
+
+	replacements := a.Replacements()
+
+	if len(replacements) != len(expected) {
+		t.Fatalf("Unexpected replacements for %s: %v", a.ID, replacements)
+	}
+
+	for i, uri := range expected {
+
+		if replacements[i] != uri {
+			t.Fatalf("Unexpected replacement at offset %d: %s", i, replacements[i])
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package authority
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// type graph is an internal structure used to index the nodes in a JSON-LD `@graph` array by their `@id`
+// value so that references (notably blank node references) can be resolved.
+type graph struct {
+	// about is the URI of the primary authority described by the graph, as derived from the `@context.about` property.
+	about string
+	// nodes is the list of nodes in the graph, in the order they were encountered.
+	nodes []gjson.Result
+	// index is a lookup table mapping `@id` values to their corresponding nodes.
+	index map[string]gjson.Result
+}
+
+// newGraph() returns a new `graph` instance derived from 'body' which is expected to be a JSON-LD document
+// containing a `@graph` property.
+func newGraph(body []byte) (*graph, error) {
+
+	graph_rsp := gjson.GetBytes(body, "@graph")
+
+	if !graph_rsp.Exists() {
+		return nil, fmt.Errorf("Record is missing @graph property")
+	}
+
+	about_rsp := gjson.GetBytes(body, "@context.about")
+
+	nodes := graph_rsp.Array()
+	index := make(map[string]gjson.Result)
+
+	for _, n := range nodes {
+
+		id := n.Get("@id").String()
+
+		if id == "" {
+			continue
+		}
+
+		index[id] = n
+	}
+
+	g := &graph{
+		about: about_rsp.String(),
+		nodes: nodes,
+		index: index,
+	}
+
+	return g, nil
+}
+
+// node() returns the node matching 'id' and a boolean value indicating whether it was found.
+func (g *graph) node(id string) (gjson.Result, bool) {
+	n, ok := g.index[id]
+	return n, ok
+}
+
+// resolve() returns the list of nodes referenced by 'rsp'. 'rsp' may be a single value or a list of values
+// (including JSON-LD `@list` containers). Values which are references to other nodes in the graph (for example
+// `{"@id": "_:N..."}`) are replaced by the node they reference; all other values are returned as-is.
+func (g *graph) resolve(rsp gjson.Result) []gjson.Result {
+
+	resolved := make([]gjson.Result, 0)
+
+	for _, v := range values(rsp) {
+
+		id := v.Get("@id").String()
+
+		if id != "" {
+
+			n, ok := g.index[id]
+
+			if ok {
+				v = n
+			}
+		}
+
+		resolved = append(resolved, v)
+	}
+
+	return resolved
+}
+
+// primary() returns the node for the primary authority described by the graph. If the graph does not
+// define a `@context.about` property then the first non-blank node with an authoritative label, the first
+// deprecated authority or the first SKOS concept with a preferred label, is returned.
+func (g *graph) primary() (gjson.Result, bool) {
+
+	if g.about != "" {
+
+		n, ok := g.index[g.about]
+
+		if ok {
+			return n, true
+		}
+	}
+
+	for _, n := range g.nodes {
+
+		if isAuthority(n) {
+			return n, true
+		}
+	}
+
+	return gjson.Result{}, false
+}
+
+// authorities() returns the list of nodes in the graph which describe an authority, in the order they were encountered.
+func (g *graph) authorities() []gjson.Result {
+
+	nodes := make([]gjson.Result, 0)
+
+	for _, n := range g.nodes {
+
+		if isAuthority(n) {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// isAuthority() returns a boolean value indicating whether 'n' is a non-blank node with an authoritative label, a
+// deprecated authority or a SKOS concept with a preferred label.
+func isAuthority(n gjson.Result) bool {
+
+	id := n.Get("@id").String()
+
+	if id == "" || isBlankNode(id) {
+		return false
+	}
+
+	if n.Get("madsrdf:authoritativeLabel").Exists() {
+		return true
+	}
+
+	if hasType(n, "madsrdf:DeprecatedAuthority") {
+		return true
+	}
+
+	if hasType(n, "skos:Concept") && (n.Get("skos:prefLabel").Exists() || n.Get("skosxl:prefLabel").Exists()) {
+		return true
+	}
+
+	return false
+}
+
+// values() returns the list of values for 'rsp' flattening single values, arrays and JSON-LD `@list`
+// containers in to a single list.
+func values(rsp gjson.Result) []gjson.Result {
+
+	if !rsp.Exists() {
+		return []gjson.Result{}
+	}
+
+	list_rsp := rsp.Get("@list")
+
+	if rsp.IsObject() && list_rsp.Exists() {
+		return list_rsp.Array()
+	}
+
+	return rsp.Array()
+}
+
+// references() returns the list of `@id` values referenced by 'rsp'.
+func references(rsp gjson.Result) []string {
+
+	ids := make([]string, 0)
+
+	for _, v := range values(rsp) {
+
+		id := v.Get("@id").String()
+
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// types() returns the list of `@type` values for 'n'.
+func types(n gjson.Result) []string {
+
+	t := make([]string, 0)
+
+	for _, v := range n.Get("@type").Array() {
+		t = append(t, v.String())
+	}
+
+	return t
+}
+
+// hasType() returns a boolean value indicating whether 'n' has a `@type` value matching 't'.
+func hasType(n gjson.Result, t string) bool {
+
+	for _, v := range types(n) {
+
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBlankNode() returns a boolean value indicating whether 'id' is a JSON-LD blank node identifier.
+func isBlankNode(id string) bool {
+	return strings.HasPrefix(id, "_:")
+}
@@ -0,0 +1,89 @@
+package authority
+
+import (
+	"sort"
+	"sync"
+)
+
+// type Hierarchy is a struct used to accumulate the broader and narrower edges defined by many authority records
+// in order to identify asymmetric relationships. An asymmetric relationship is one where authority A lists B as
+// broader but B does not list A as narrower (or vice versa).
+type Hierarchy struct {
+	// seen is the set of authority URIs that have been added to the hierarchy.
+	seen map[string]bool
+	// edges is the set of broader and narrower edges that have been added to the hierarchy.
+	edges map[Edge]bool
+	// mu is an internal `sync.RWMutex` instance used to prevent race conditions.
+	mu *sync.RWMutex
+}
+
+// NewHierarchy() returns a new, empty `Hierarchy` instance.
+func NewHierarchy() *Hierarchy {
+
+	h := &Hierarchy{
+		seen:  make(map[string]bool),
+		edges: make(map[Edge]bool),
+		mu:    new(sync.RWMutex),
+	}
+
+	return h
+}
+
+// Add() records the broader and narrower edges defined by 'a'.
+func (h *Hierarchy) Add(a *Authority) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seen[a.ID] = true
+
+	for _, e := range a.Edges() {
+
+		if e.Relation == RelationRelated {
+			continue
+		}
+
+		h.edges[*e] = true
+	}
+}
+
+// Asymmetric() returns the sorted list of edges whose inverse is not present in the hierarchy. Edges pointing to
+// authorities that have not been added to the hierarchy are excluded since their inverse can not be known.
+func (h *Hierarchy) Asymmetric() []*Edge {
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	asymmetric := make([]*Edge, 0)
+
+	for e := range h.edges {
+
+		if !h.seen[e.To] {
+			continue
+		}
+
+		inv := e.Inverse()
+
+		if h.edges[*inv] {
+			continue
+		}
+
+		edge := e
+		asymmetric = append(asymmetric, &edge)
+	}
+
+	sort.Slice(asymmetric, func(i, j int) bool {
+
+		if asymmetric[i].From != asymmetric[j].From {
+			return asymmetric[i].From < asymmetric[j].From
+		}
+
+		if asymmetric[i].To != asymmetric[j].To {
+			return asymmetric[i].To < asymmetric[j].To
+		}
+
+		return asymmetric[i].Relation < asymmetric[j].Relation
+	})
+
+	return asymmetric
+}
@@ -0,0 +1,61 @@
+package authority
+
+import (
+	"testing"
+)
+
+func TestHierarchy(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	edges := a.Edges()
+
+	if len(edges) != 2 {
+		t.Fatalf("Unexpected number of edges: %d", len(edges))
+	}
+
+	if edges[0].Relation != RelationBroader || edges[0].To != "http://id.loc.gov/authorities/subjects/sh85004652" {
+		t.Fatalf("Unexpected broader edge: %v", edges[0])
+	}
+
+	if edges[1].Relation != RelationNarrower || edges[1].To != "http://id.loc.gov/authorities/subjects/sh85038540" {
+		t.Fatalf("Unexpected narrower edge: %v", edges[1])
+	}
+
+	h := NewHierarchy()
+	h.Add(a)
+
+	// Neither sh85004652 nor sh85038540 have been added so there is nothing to compare against.
+
+	if len(h.Asymmetric()) != 0 {
+		t.Fatalf("Expected no asymmetric edges")
+	}
+
+	broader := &Authority{
+		ID:       "http://id.loc.gov/authorities/subjects/sh85004652",
+		Narrower: []string{a.ID},
+	}
+
+	narrower := &Authority{
+		ID: "http://id.loc.gov/authorities/subjects/sh85038540",
+	}
+
+	h.Add(broader)
+	h.Add(narrower)
+
+	asymmetric := h.Asymmetric()
+
+	if len(asymmetric) != 1 {
+		t.Fatalf("Unexpected number of asymmetric edges: %d", len(asymmetric))
+	}
+
+	if asymmetric[0].From != a.ID || asymmetric[0].To != narrower.ID || asymmetric[0].Relation != RelationNarrower {
+		t.Fatalf("Unexpected asymmetric edge: %v", asymmetric[0])
+	}
+}
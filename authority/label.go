@@ -0,0 +1,97 @@
+package authority
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// type Label is a (optionally) language-tagged string value.
+type Label struct {
+	// Value is the string value of the label.
+	Value string `json:"value"`
+	// Language is the (BCP 47) language tag associated with the label, if present.
+	Language string `json:"language,omitempty"`
+}
+
+// String() returns the value of 'l'.
+func (l *Label) String() string {
+	return l.Value
+}
+
+// Tagged() returns the value of 'l' followed by an "@" character and its language tag (for example
+// "Wide-band amplifiers@en"). If 'l' does not have a language tag then only its value is returned.
+func (l *Label) Tagged() string {
+
+	if l.Language == "" {
+		return l.Value
+	}
+
+	return fmt.Sprintf("%s@%s", l.Value, l.Language)
+}
+
+// labels() returns the list of `Label` instances derived from 'rsp' which may be a plain string, a JSON-LD
+// value object (`{"@language": "en", "@value": "..."}`) or a list of either.
+func labels(rsp gjson.Result) []*Label {
+
+	l := make([]*Label, 0)
+
+	for _, v := range values(rsp) {
+
+		lbl := newLabel(v)
+
+		if lbl == nil {
+			continue
+		}
+
+		l = append(l, lbl)
+	}
+
+	return l
+}
+
+// newLabel() returns a new `Label` instance derived from 'v' or nil if 'v' does not contain a string value.
+func newLabel(v gjson.Result) *Label {
+
+	var lbl *Label
+
+	switch {
+	case v.IsObject():
+
+		value_rsp := v.Get("@value")
+
+		if !value_rsp.Exists() {
+			return nil
+		}
+
+		lbl = &Label{
+			Value:    value_rsp.String(),
+			Language: v.Get("@language").String(),
+		}
+
+	case v.Type == gjson.String:
+
+		lbl = &Label{
+			Value: v.String(),
+		}
+
+	default:
+		return nil
+	}
+
+	if lbl.Value == "" {
+		return nil
+	}
+
+	return lbl
+}
+
+// firstLabel() returns the value of the first element in 'l' or an empty string if 'l' is empty.
+func firstLabel(l []*Label) string {
+
+	if len(l) == 0 {
+		return ""
+	}
+
+	return l[0].Value
+}
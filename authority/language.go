@@ -0,0 +1,179 @@
+package authority
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Special language tags recognized by the `SelectLabel` method.
+const (
+	// LanguageAny matches labels in any language.
+	LanguageAny string = "*"
+	// LanguageUndetermined matches labels without a language tag.
+	LanguageUndetermined string = "und"
+)
+
+// scripts is the list of Unicode scripts, and their ISO 15924 codes, recognized by the `Script` method.
+var scripts = []struct {
+	Code  string
+	Table *unicode.RangeTable
+}{
+	{"Latn", unicode.Latin},
+	{"Cyrl", unicode.Cyrillic},
+	{"Grek", unicode.Greek},
+	{"Arab", unicode.Arabic},
+	{"Hebr", unicode.Hebrew},
+	{"Syrc", unicode.Syriac},
+	{"Armn", unicode.Armenian},
+	{"Geor", unicode.Georgian},
+	{"Ethi", unicode.Ethiopic},
+	{"Deva", unicode.Devanagari},
+	{"Beng", unicode.Bengali},
+	{"Taml", unicode.Tamil},
+	{"Thai", unicode.Thai},
+	{"Tibt", unicode.Tibetan},
+	{"Hani", unicode.Han},
+	{"Hira", unicode.Hiragana},
+	{"Kana", unicode.Katakana},
+	{"Hang", unicode.Hangul},
+}
+
+// ParseLanguages() returns the list of (trimmed, non-empty) language tags in 'str', a comma-separated list
+// of language tags, for example "en,fr".
+func ParseLanguages(str string) []string {
+
+	langs := make([]string, 0)
+
+	for _, l := range strings.Split(str, ",") {
+
+		l = strings.TrimSpace(l)
+
+		if l != "" {
+			langs = append(langs, l)
+		}
+	}
+
+	return langs
+}
+
+// MatchesLanguage() returns a boolean value indicating whether 'l' matches 'tag'. Language tags are compared
+// case-insensitively and a tag matches labels with the same tag or a more specific tag, for example "zh" matches
+// "zh-Hani" and "zh-Latn-pinyin". The special tag "*" matches any label and "und" matches labels without a
+// language tag.
+func (l *Label) MatchesLanguage(tag string) bool {
+
+	switch tag {
+	case LanguageAny:
+		return true
+	case LanguageUndetermined:
+		return l.Language == ""
+	}
+
+	lang := strings.ToLower(l.Language)
+	tag = strings.ToLower(tag)
+
+	return lang == tag || strings.HasPrefix(lang, tag+"-")
+}
+
+// Script() returns the ISO 15924 code for the script of 'l', for example "Latn" or "Hani". If the language tag
+// for 'l' contains a script subtag (for example "zh-Latn-pinyin") it is returned; otherwise the script used by
+// the majority of the letters in the value of 'l' is returned. If the script can not be determined an empty string
+// is returned. This can be used to distinguish between romanized and original script forms of the same label.
+func (l *Label) Script() string {
+
+	for i, subtag := range strings.Split(l.Language, "-") {
+
+		if i > 0 && len(subtag) == 4 {
+			return strings.ToUpper(subtag[0:1]) + strings.ToLower(subtag[1:])
+		}
+	}
+
+	counts := make(map[string]int)
+
+	for _, r := range l.Value {
+
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		for _, s := range scripts {
+
+			if unicode.Is(s.Table, r) {
+				counts[s.Code] += 1
+				break
+			}
+		}
+	}
+
+	script := ""
+	best_count := 0
+
+	for _, s := range scripts {
+
+		if counts[s.Code] > best_count {
+			script = s.Code
+			best_count = counts[s.Code]
+		}
+	}
+
+	return script
+}
+
+// SelectLabel() returns the first label in 'labels' matching the first language tag in 'langs' (using the
+// `MatchesLanguage` method) that has a match. If no labels match then the first label without a language tag
+// is returned and failing that the first label. If 'labels' is empty nil is returned.
+func SelectLabel(labels []*Label, langs ...string) *Label {
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	for _, tag := range langs {
+
+		for _, l := range labels {
+
+			if l.MatchesLanguage(tag) {
+				return l
+			}
+		}
+	}
+
+	for _, l := range labels {
+
+		if l.Language == "" {
+			return l
+		}
+	}
+
+	return labels[0]
+}
+
+// LabelFor() returns the authoritative label for 'a' in the first language in 'langs' that has a match, falling
+// back to an untagged label or the first label. If 'a' has no labels nil is returned. See `SelectLabel` for details.
+func (a *Authority) LabelFor(langs ...string) *Label {
+	return SelectLabel(a.Labels, langs...)
+}
+
+// Languages() returns the de-duplicated list of language tags for the authoritative and variant labels for 'a',
+// in the order they were first encountered. Labels without a language tag are ignored.
+func (a *Authority) Languages() []string {
+
+	langs := make([]string, 0)
+	seen := make(map[string]bool)
+
+	all := make([]*Label, 0)
+	all = append(all, a.Labels...)
+	all = append(all, a.VariantLabels()...)
+
+	for _, l := range all {
+
+		if l.Language == "" || seen[l.Language] {
+			continue
+		}
+
+		seen[l.Language] = true
+		langs = append(langs, l.Language)
+	}
+
+	return langs
+}
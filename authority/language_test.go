@@ -0,0 +1,91 @@
+package authority
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectLabel(t *testing.T) {
+
+	labels := []*Label{
+		{Value: "Tokyo (Japan)"},
+		{Value: "東京", Language: "ja-Jpan"},
+		{Value: "Tōkyō", Language: "ja-Latn"},
+		{Value: "Tokio", Language: "es"},
+	}
+
+	tests := map[string]string{
+		"es":       "Tokio",
+		"ja":       "東京",
+		"ja-Latn":  "Tōkyō",
+		"JA-LATN":  "Tōkyō",
+		"fr,es":    "Tokio",
+		"fr":       "Tokyo (Japan)",
+		"und":      "Tokyo (Japan)",
+		"*":        "Tokyo (Japan)",
+		"":         "Tokyo (Japan)",
+		"fr,ja,es": "東京",
+	}
+
+	for str, expected := range tests {
+
+		l := SelectLabel(labels, ParseLanguages(str)...)
+
+		if l == nil || l.Value != expected {
+			t.Fatalf("Unexpected label for '%s': %v (expected %s)", str, l, expected)
+		}
+	}
+
+	l := SelectLabel(labels[1:], "fr")
+
+	if l.Value != "東京" {
+		t.Fatalf("Expected first label when there are no matches or untagged labels, got %s", l.Value)
+	}
+
+	if SelectLabel([]*Label{}, "en") != nil {
+		t.Fatalf("Expected nil label for empty list")
+	}
+}
+
+func TestScript(t *testing.T) {
+
+	tests := map[*Label]string{
+		{Value: "Broadband amplifiers", Language: "en"}:       "Latn",
+		{Value: "Śreshṭha family"}:                            "Latn",
+		{Value: "东京", Language: "zh"}:                         "Hani",
+		{Value: "Dongjing", Language: "zh-latn-pinyin"}:       "Latn",
+		{Value: "Толстой, Лев, граф, 1828-1910"}:              "Cyrl",
+		{Value: "ثائر، حامد"}:                                 "Arab",
+		{Value: "1828-1910"}:                                  "",
+		{Value: "Anything", Language: "sr-Cyrl"}:              "Cyrl",
+		{Value: "Ἀριστοτέλης"}:                                "Grek",
+		{Value: "김소월, 1902-1934", Language: "ko"}:             "Hang",
+		{Value: "Kim, Sowŏl, 1902-1934", Language: "ko-Latn"}: "Latn",
+	}
+
+	for l, expected := range tests {
+
+		if l.Script() != expected {
+			t.Fatalf("Unexpected script for '%s': '%s' (expected '%s')", l.Value, l.Script(), expected)
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if strings.Join(a.Languages(), ",") != "en" {
+		t.Fatalf("Unexpected languages: %v", a.Languages())
+	}
+
+	if a.LabelFor("fr", "en").Value != "Broadband amplifiers" {
+		t.Fatalf("Unexpected label: %v", a.LabelFor("fr", "en"))
+	}
+}
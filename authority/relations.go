@@ -0,0 +1,71 @@
+package authority
+
+// Relation types for edges between authorities.
+const (
+	// RelationBroader indicates that the source of an edge has a broader authority.
+	RelationBroader string = "broader"
+	// RelationNarrower indicates that the source of an edge has a narrower authority.
+	RelationNarrower string = "narrower"
+	// RelationRelated indicates that the source of an edge has a related authority.
+	RelationRelated string = "related"
+)
+
+// type Edge is a directed relationship between two authorities.
+type Edge struct {
+	// From is the URI of the authority the relationship is defined by.
+	From string `json:"from"`
+	// To is the URI of the authority being pointed to.
+	To string `json:"to"`
+	// Relation is the type of relationship, one of `RelationBroader`, `RelationNarrower` or `RelationRelated`.
+	Relation string `json:"relation"`
+}
+
+// Edges() returns the de-duplicated list of broader, narrower and related edges defined by 'a'.
+func (a *Authority) Edges() []*Edge {
+
+	edges := make([]*Edge, 0)
+
+	relations := map[string][]string{
+		RelationBroader:  a.Broader,
+		RelationNarrower: a.Narrower,
+		RelationRelated:  a.Related,
+	}
+
+	for _, r := range []string{RelationBroader, RelationNarrower, RelationRelated} {
+
+		for _, to := range relations[r] {
+
+			e := &Edge{
+				From:     a.ID,
+				To:       to,
+				Relation: r,
+			}
+
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// Inverse() returns the inverse of 'e'. Broader edges become narrower edges (and vice versa) and related edges
+// remain related edges.
+func (e *Edge) Inverse() *Edge {
+
+	relation := e.Relation
+
+	switch relation {
+	case RelationBroader:
+		relation = RelationNarrower
+	case RelationNarrower:
+		relation = RelationBroader
+	}
+
+	inv := &Edge{
+		From:     e.To,
+		To:       e.From,
+		Relation: relation,
+	}
+
+	return inv
+}
@@ -0,0 +1,34 @@
+package authority
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// prefLabels() returns the list of `Label` instances derived from the `skos:prefLabel` and `skosxl:prefLabel`
+// properties of 'n' resolving `skosxl:Label` references against 'g'.
+func prefLabels(g *graph, n gjson.Result) []*Label {
+
+	skos_labels := labels(n.Get("skos:prefLabel"))
+	xl_labels := make([]*Label, 0)
+
+	for _, xl := range g.resolve(n.Get("skosxl:prefLabel")) {
+		xl_labels = append(xl_labels, labels(xl.Get("skosxl:literalForm"))...)
+	}
+
+	return uniqueLabels(skos_labels, xl_labels)
+}
+
+// ScopeNote() returns the values of the scope notes (`skos:scopeNote`) for 'a' separated by a single space. If 'a'
+// has no scope notes an empty string is returned.
+func (a *Authority) ScopeNote() string {
+
+	notes := make([]string, len(a.ScopeNotes))
+
+	for i, n := range a.ScopeNotes {
+		notes[i] = n.Value
+	}
+
+	return strings.Join(notes, " ")
+}
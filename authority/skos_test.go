@@ -0,0 +1,91 @@
+package authority
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSKOSConcept(t *testing.T) {
+
+	records := loadFixtureFile(t, "../fixtures/tgm.sample.ndjson")
+
+	if len(records) != 3 {
+		t.Fatalf("Unexpected number of fixtures: %d", len(records))
+	}
+
+	a, err := ParseRecord(records[0])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.Identifier() != "tgm007721" || a.Label() != "Photographs" {
+		t.Fatalf("Unexpected concept: %s (%s)", a.Identifier(), a.Label())
+	}
+
+	if a.MADSType() != "" {
+		t.Fatalf("Unexpected MADS type: %s", a.MADSType())
+	}
+
+	variants := make([]string, 0)
+
+	for _, v := range a.VariantLabels() {
+		variants = append(variants, v.Value)
+	}
+
+	if strings.Join(variants, ",") != "Photographic prints,Photos" {
+		t.Fatalf("Unexpected variants: %v", variants)
+	}
+
+	if a.ScopeNote() != "Use for photographic prints and collections of photographs in general." {
+		t.Fatalf("Unexpected scope note: %s", a.ScopeNote())
+	}
+
+	if len(a.ExternalAuthorities) != 1 {
+		t.Fatalf("Unexpected number of external authorities: %d", len(a.ExternalAuthorities))
+	}
+
+	ext := a.ExternalAuthorities[0]
+
+	if ext.ID != "http://id.loc.gov/authorities/subjects/sh85101206" || ext.Match != MatchClose || ext.Label != "Photographs" {
+		t.Fatalf("Unexpected external authority: %v", ext)
+	}
+
+	a, err = ParseRecord(records[1])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if strings.Join(a.Broader, ",") != "http://id.loc.gov/vocabulary/graphicMaterials/tgm007721" {
+		t.Fatalf("Unexpected broader: %v", a.Broader)
+	}
+
+	if len(a.Related) != 1 {
+		t.Fatalf("Unexpected related: %v", a.Related)
+	}
+
+	if a.ScopeNote() != "For photographs of people. Search also under PORTRAITS." {
+		t.Fatalf("Unexpected scope note: %s", a.ScopeNote())
+	}
+
+	if len(a.VariantLabels()) != 1 || a.VariantLabels()[0].Value != "Photographic portraits" {
+		t.Fatalf("Unexpected variants: %v", a.VariantLabels())
+	}
+
+	if len(a.ExternalAuthorities) != 2 || a.ExternalAuthorities[0].Match != MatchClose || a.ExternalAuthorities[1].Match != MatchExact {
+		t.Fatalf("Unexpected external authorities: %v", a.ExternalAuthorities)
+	}
+
+	// The third record does not have a @context.about property and a skosxl:prefLabel
+
+	a, err = ParseRecord(records[2])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.Identifier() != "tgm008083" || a.Label() != "Portraits" {
+		t.Fatalf("Unexpected concept: %s (%s)", a.Identifier(), a.Label())
+	}
+}
@@ -0,0 +1,44 @@
+package authority
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// type Source is a citation for a source (`madsrdf:Source`) consulted when establishing an authority, for example
+// the title page of a book in which a name appears.
+type Source struct {
+	// Citation is the citation for the source (`madsrdf:citationSource`).
+	Citation string `json:"citation"`
+	// Note is the information found in the source (`madsrdf:citationNote`), if present.
+	Note string `json:"note,omitempty"`
+	// URI is the URI of the source (`madsrdf:citationURI`), if present.
+	URI string `json:"uri,omitempty"`
+}
+
+// sources() returns the list of `Source` instances derived from 'rsp' (a `madsrdf:hasSource` property) resolving
+// references against 'g'.
+func sources(g *graph, rsp gjson.Result) []*Source {
+
+	sl := make([]*Source, 0)
+
+	for _, n := range g.resolve(rsp) {
+
+		s := &Source{
+			Citation: literal(n.Get("madsrdf:citationSource")),
+			Note:     literal(n.Get("madsrdf:citationNote")),
+			URI:      literal(n.Get("madsrdf:citationURI")),
+		}
+
+		if n.Get("madsrdf:citationURI.@id").Exists() {
+			s.URI = n.Get("madsrdf:citationURI.@id").String()
+		}
+
+		if s.Citation == "" {
+			continue
+		}
+
+		sl = append(sl, s)
+	}
+
+	return sl
+}
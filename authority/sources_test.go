@@ -0,0 +1,33 @@
+package authority
+
+import (
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+
+	body := []byte(`{"@context": {"about": "http://id.loc.gov/authorities/names/n79021164"}, "@graph": [
+{"@id": "http://id.loc.gov/authorities/names/n79021164", "@type": ["madsrdf:PersonalName", "madsrdf:Authority"], "madsrdf:authoritativeLabel": "Twain, Mark, 1835-1910", "madsrdf:hasSource": [{"@id": "_:n1"}, {"@id": "_:n2"}, {"@id": "_:n3"}]},
+{"@id": "_:n1", "@type": "madsrdf:Source", "madsrdf:citationSource": "His The celebrated jumping frog of Calaveras County, 1867:", "madsrdf:citationNote": {"@language": "en", "@value": "t.p. (Mark Twain)"}},
+{"@id": "_:n2", "@type": "madsrdf:Source", "madsrdf:citationSource": "Wikipedia", "madsrdf:citationURI": {"@id": "https://en.wikipedia.org/wiki/Mark_Twain"}},
+{"@id": "_:n3", "@type": "madsrdf:Source", "madsrdf:citationNote": "No citation"}
+]}`)
+
+	a, err := ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if len(a.Sources) != 2 {
+		t.Fatalf("Unexpected number of sources: %d", len(a.Sources))
+	}
+
+	if a.Sources[0].Note != "t.p. (Mark Twain)" {
+		t.Fatalf("Unexpected note: %s", a.Sources[0].Note)
+	}
+
+	if a.Sources[1].URI != "https://en.wikipedia.org/wiki/Mark_Twain" {
+		t.Fatalf("Unexpected URI: %s", a.Sources[1].URI)
+	}
+}
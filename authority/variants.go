@@ -0,0 +1,59 @@
+package authority
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// VariantLabels() returns the de-duplicated list of variant ("used for" or "see from") labels for 'a'. Labels
+// are derived from the `madsrdf:variantLabel` property of each `madsrdf:hasVariant` node as well as the
+// `skos:altLabel` and `skosxl:altLabel` properties (stored in `AltLabels`). Two labels are considered
+// duplicates if they have the same value and language.
+func (a *Authority) VariantLabels() []*Label {
+
+	all := make([][]*Label, 0)
+
+	for _, v := range a.Variants {
+		all = append(all, v.Labels)
+	}
+
+	all = append(all, a.AltLabels)
+
+	return uniqueLabels(all...)
+}
+
+// altLabels() returns the list of `Label` instances derived from the `skos:altLabel` and `skosxl:altLabel`
+// properties of 'n' resolving `skosxl:Label` references against 'g'.
+func altLabels(g *graph, n gjson.Result) []*Label {
+
+	skos_labels := labels(n.Get("skos:altLabel"))
+	xl_labels := make([]*Label, 0)
+
+	for _, xl := range g.resolve(n.Get("skosxl:altLabel")) {
+		xl_labels = append(xl_labels, labels(xl.Get("skosxl:literalForm"))...)
+	}
+
+	return uniqueLabels(skos_labels, xl_labels)
+}
+
+// uniqueLabels() returns the de-duplicated list of labels in one or more lists of labels, preserving the order
+// in which they were first encountered.
+func uniqueLabels(lists ...[]*Label) []*Label {
+
+	unique := make([]*Label, 0)
+	seen := make(map[Label]bool)
+
+	for _, l := range lists {
+
+		for _, lbl := range l {
+
+			if seen[*lbl] {
+				continue
+			}
+
+			seen[*lbl] = true
+			unique = append(unique, lbl)
+		}
+	}
+
+	return unique
+}
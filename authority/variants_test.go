@@ -0,0 +1,48 @@
+package authority
+
+import (
+	"testing"
+)
+
+func TestVariantLabels(t *testing.T) {
+
+	records := loadFixtures(t)
+
+	a, err := ParseRecord(records[1])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if len(a.AltLabels) != 2 {
+		t.Fatalf("Unexpected alt labels: %d", len(a.AltLabels))
+	}
+
+	expected := []string{
+		"Śreṣṭha family@en",
+		"Shreshtha family@en",
+	}
+
+	variants := a.VariantLabels()
+
+	if len(variants) != len(expected) {
+		t.Fatalf("Unexpected variant labels: %d", len(variants))
+	}
+
+	for i, v := range variants {
+
+		if v.Tagged() != expected[i] {
+			t.Fatalf("Unexpected variant label at offset %d: %s (expected %s)", i, v.Tagged(), expected[i])
+		}
+	}
+
+	a, err = ParseRecord(records[2])
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if len(a.VariantLabels()) != 0 {
+		t.Fatalf("Expected no variant labels")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"sync"
 
@@ -14,10 +15,13 @@ import (
 // type Catalog is a struct used deduplicate IDs seen in the various LoC authority files.
 // It is necessary specifically for the LCNAF file which is so big that tracking IDs in memory
 // trigger "out of memory" errors so instead we track "attendance" on disk using a temporary SQLite
-// database.
+// database. Catalogs can also be stored in a persistent SQLite database so that "attendance" can be
+// restored when an interrupted walk is resumed.
 type Catalog struct {
-	// path is the path to the temporary SQLite database on disk.
+	// path is the path to the SQLite database on disk.
 	path string
+	// temporary is a boolean flag indicating that the SQLite database should be removed when the catalog is closed.
+	temporary bool
 	// db is the `sql.DB` instance mapped to the temporary SQLite database on disk.
 	db *sql.DB
 	// mu is an internal `sync.RWMutex` instance used to prevent race conditions.
@@ -28,17 +32,56 @@ type Catalog struct {
 // the form of:
 //
 //	tmp://
+//	file:///path/to/catalog.db
+//
+// A "tmp://" catalog is stored in a temporary SQLite database which is removed when the catalog is closed.
+// A "file://" catalog is stored in a SQLite database at the path specified, which is created if it does not
+// already exist and is not removed when the catalog is closed. Any other value for 'uri', including the empty
+// string, is treated as "tmp://".
 func NewCatalog(ctx context.Context, uri string) (*Catalog, error) {
 
-	tmpfile, err := ioutil.TempFile("", "catalog")
+	scheme := "tmp"
 
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create temp file, %w", err)
+	u, err := url.Parse(uri)
+
+	if err == nil && u.Scheme == "file" {
+		scheme = u.Scheme
 	}
 
-	tmpfile.Close()
+	var path string
+	temporary := false
+
+	// Temporary databases are discarded if the application is interrupted so there is no need to
+	// journal writes. Persistent databases use a write-ahead log so they survive interruptions.
+
+	journal_mode := "OFF"
+	synchronous := "OFF"
+
+	switch scheme {
+	case "file":
+
+		path = u.Path
+
+		if path == "" {
+			return nil, fmt.Errorf("Missing path")
+		}
+
+		journal_mode = "WAL"
+		synchronous = "NORMAL"
+
+	default:
 
-	path := tmpfile.Name()
+		tmpfile, err := ioutil.TempFile("", "catalog")
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create temp file, %w", err)
+		}
+
+		tmpfile.Close()
+
+		path = tmpfile.Name()
+		temporary = true
+	}
 
 	dsn := fmt.Sprintf("%s", path)
 
@@ -49,8 +92,8 @@ func NewCatalog(ctx context.Context, uri string) (*Catalog, error) {
 	}
 
 	pragma := []string{
-		"PRAGMA JOURNAL_MODE=OFF",
-		"PRAGMA SYNCHRONOUS=OFF",
+		fmt.Sprintf("PRAGMA JOURNAL_MODE=%s", journal_mode),
+		fmt.Sprintf("PRAGMA SYNCHRONOUS=%s", synchronous),
 		"PRAGMA LOCKING_MODE=EXCLUSIVE",
 		"PRAGMA PAGE_SIZE=4096",
 		"PRAGMA CACHE_SIZE=1000000",
@@ -65,7 +108,7 @@ func NewCatalog(ctx context.Context, uri string) (*Catalog, error) {
 		}
 	}
 
-	_, err = db.ExecContext(ctx, `CREATE TABLE seen(id TEXT PRIMARY KEY);`)
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS seen(id TEXT PRIMARY KEY);`)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create database table, %v", err)
@@ -74,9 +117,10 @@ func NewCatalog(ctx context.Context, uri string) (*Catalog, error) {
 	mu := new(sync.RWMutex)
 
 	c := &Catalog{
-		path: path,
-		db:   db,
-		mu:   mu,
+		path:      path,
+		temporary: temporary,
+		db:        db,
+		mu:        mu,
 	}
 
 	return c, nil
@@ -138,7 +182,52 @@ func (c *Catalog) Store(ctx context.Context, id string) error {
 	return nil
 }
 
-// Close() removes the temporary SQLite database from disk.
+// Position() returns the position of the most recently stored entry in the SQLite database. Entries are stored in
+// increasing order so the position can be passed to `Truncate` to remove all the entries stored after it.
+func (c *Catalog) Position(ctx context.Context) (int64, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var position int64
+
+	row := c.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(rowid), 0) FROM seen")
+	err := row.Scan(&position)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to query position, %w", err)
+	}
+
+	return position, nil
+}
+
+// Truncate() removes all the entries stored after 'position' (as returned by `Position`) from the SQLite database.
+func (c *Catalog) Truncate(ctx context.Context, position int64) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.db.ExecContext(ctx, "DELETE FROM seen WHERE rowid > ?", position)
+
+	if err != nil {
+		return fmt.Errorf("Failed to truncate to position %d, %w", position, err)
+	}
+
+	return nil
+}
+
+// Close() closes the SQLite database and, if it is temporary, removes it from disk.
 func (c *Catalog) Close(ctx context.Context) error {
+
+	err := c.db.Close()
+
+	if err != nil {
+		return fmt.Errorf("Failed to close database, %w", err)
+	}
+
+	if !c.temporary {
+		return nil
+	}
+
 	return os.Remove(c.path)
 }
@@ -2,6 +2,8 @@ package libraryofcongress
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -60,3 +62,109 @@ func TestCatalog(t *testing.T) {
 	}
 
 }
+
+func TestCatalogLegacyURI(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Catalogs were always temporary, regardless of the URI passed to NewCatalog, before persistent
+	// catalogs were introduced
+
+	for _, uri := range []string{"", "catalog", "sqlite://", "%"} {
+
+		c, err := NewCatalog(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create catalog for '%s', %v", uri, err)
+		}
+
+		if !c.temporary {
+			t.Fatalf("Expected catalog for '%s' to be temporary", uri)
+		}
+
+		err = c.Close(ctx)
+
+		if err != nil {
+			t.Fatalf("Failed to close catalog for '%s', %v", uri, err)
+		}
+
+		_, err = os.Stat(c.path)
+
+		if !os.IsNotExist(err) {
+			t.Fatalf("Expected catalog for '%s' to be removed, %v", uri, err)
+		}
+	}
+}
+
+func TestCatalogPersistent(t *testing.T) {
+
+	ctx := context.Background()
+
+	uri := "file://" + filepath.Join(t.TempDir(), "catalog.db")
+
+	c, err := NewCatalog(ctx, uri)
+
+	if err != nil {
+		t.Fatalf("Failed to create catalog, %v", err)
+	}
+
+	for _, k := range []string{"test", "test2"} {
+
+		err = c.Store(ctx, k)
+
+		if err != nil {
+			t.Fatalf("Failed to store '%s', %v", k, err)
+		}
+	}
+
+	position, err := c.Position(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to determine position, %v", err)
+	}
+
+	err = c.Store(ctx, "test3")
+
+	if err != nil {
+		t.Fatalf("Failed to store 'test3', %v", err)
+	}
+
+	err = c.Close(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to close catalog, %v", err)
+	}
+
+	c, err = NewCatalog(ctx, uri)
+
+	if err != nil {
+		t.Fatalf("Failed to reopen catalog, %v", err)
+	}
+
+	defer c.Close(ctx)
+
+	err = c.Truncate(ctx, position)
+
+	if err != nil {
+		t.Fatalf("Failed to truncate catalog, %v", err)
+	}
+
+	expected := map[string]bool{
+		"test":  true,
+		"test2": true,
+		"test3": false,
+	}
+
+	for k, v := range expected {
+
+		exists, err := c.Exists(ctx, k)
+
+		if err != nil {
+			t.Fatalf("Failed to determine whether '%s' exists, %v", k, err)
+		}
+
+		if exists != v {
+			t.Fatalf("Unexpected value for '%s': %t", k, exists)
+		}
+	}
+}
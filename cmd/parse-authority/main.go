@@ -0,0 +1,42 @@
+// parse-authority is a command-line tool to parse any of the Library of Congress MADS/RDF (`*.both.ndjson`) files
+// published by id.loc.gov, for example LC Genre/Form Terms (LCGFT), Demographic Group Terms (LCDGT), Medium of Performance
+// Thesaurus for Music (LCMPT) or Children's Subject Headings, and output CSV-encoded ID and label data. The vocabulary
+// being parsed is specified using the -dataset flag and all the other flags are the same as those for the parse-lcsh
+// and parse-lcnaf tools.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/app/parse"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+func main() {
+
+	ctx := context.Background()
+
+	fs := parse.DefaultFlagSet(nil)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "parse-authority is a command-line tool to parse any of the Library of Congress MADS/RDF (`*.both.ndjson`) files published by id.loc.gov and output CSV-encoded ID and label data.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options] -dataset={DATASET} {DATASET}.both.ndjson\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Where {DATASET} is one of: %s.\n\n", strings.Join(dataset.Names(), ", "))
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	err := parse.RunWithFlagSet(ctx, fs, nil)
+
+	if err != nil {
+		log.Fatalf("Failed to parse data, %v", err)
+	}
+}
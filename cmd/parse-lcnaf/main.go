@@ -1,131 +1,44 @@
 // parse-lcnaf is a command-line tool to parse the Library of Congress `lcnaf.both.ndjson` (or `lcnaf.both.ndjson.zip`)
-// file and output CSV-encoded subject heading ID and (English) label data.
+// file and output CSV-encoded name authority ID and label (English, by default) data. It can also be configured to include the
+// type of each name, broader names as well as Wikidata, VIAF, ISNI and other concordances.
 package main
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"path/filepath"
-	"strings"
 
-	"github.com/sfomuseum/go-csvdict"
-	"github.com/sfomuseum/go-libraryofcongress"
-	"github.com/sfomuseum/go-libraryofcongress/walk"
-	"github.com/tidwall/gjson"
+	"github.com/sfomuseum/go-libraryofcongress/app/parse"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
 )
 
 func main() {
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "parse-lcnaf is a command-line tool to parse the Library of Congress `lcnaf.both.ndjson` (or `lcnaf.both.ndjson.zip`) file and output CSV-encoded subject heading ID and (English) label data.\n\n")
-		fmt.Fprintf(os.Stderr, "Usage:\n\t %s lcnaf.both.ndjson.zip\n\n", os.Args[0])
-		flag.PrintDefaults()
-	}
-
-	flag.Parse()
-
-	uris := flag.Args()
 	ctx := context.Background()
 
-	w, err := walk.NewWalker(ctx, "ndjson://")
-
-	if err != nil {
-		log.Fatalf("Failed to create walker, %v", err)
-	}
-
-	catalog, err := libraryofcongress.NewCatalog(ctx, "tmp://")
+	ds, err := dataset.GetDataset(ctx, "lcnaf")
 
 	if err != nil {
-		log.Fatalf("Failed to create catalog, %v", err)
+		log.Fatalf("Failed to load dataset, %v", err)
 	}
 
-	defer catalog.Close(ctx)
+	fs := parse.DefaultFlagSet(ds)
 
-	writers := []io.Writer{
-		os.Stdout,
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "parse-lcnaf is a command-line tool to parse the Library of Congress `lcnaf.both.ndjson` (or `lcnaf.both.ndjson.zip`) file and output CSV-encoded name authority ID and label (English, by default) data. It can also be configured to include the type of each name, broader names as well as Wikidata, VIAF, ISNI and other concordances.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options] lcnaf.both.ndjson.zip\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
 	}
 
-	mw := io.MultiWriter(writers...)
-
-	fieldnames := []string{
-		"id",
-		"label",
-	}
-
-	csv_wr, err := csvdict.NewWriter(mw, fieldnames)
+	err = parse.RunWithFlagSet(ctx, fs, ds)
 
 	if err != nil {
-		log.Fatalf("Failed to create CSV writer, %v", err)
+		log.Fatalf("Failed to parse LCNAF data, %v", err)
 	}
-
-	csv_wr.WriteHeader()
-
-	cb_func := walkCallbackFunc(csv_wr, catalog)
-
-	err = w.WalkURIs(ctx, cb_func, uris...)
-
-	if err != nil {
-		log.Fatalf("Failed to walk LCSH data, %v", err)
-	}
-}
-
-func walkCallbackFunc(csv_wr *csvdict.Writer, catalog *libraryofcongress.Catalog) walk.WalkCallbackFunction {
-
-	fn := func(ctx context.Context, body []byte) error {
-
-		rsp := gjson.GetBytes(body, "@graph")
-
-		if !rsp.Exists() {
-			return fmt.Errorf("Record is missing @graph property")
-		}
-
-		for _, item := range rsp.Array() {
-
-			id_rsp := item.Get("@id")
-			id := id_rsp.String()
-
-			if !strings.HasPrefix(id, "http://id.loc.gov/authorities/names/") {
-				continue
-			}
-
-			sh_id := filepath.Base(id)
-
-			label_rsp := item.Get("madsrdf:authoritativeLabel")
-			label := label_rsp.String()
-
-			if label == "" {
-				continue
-			}
-
-			exists, err := catalog.ExistsOrStore(ctx, sh_id)
-
-			if err != nil {
-				return fmt.Errorf("Failed to determine whether %s exists, %w", sh_id, err)
-			}
-
-			if exists {
-				continue
-			}
-
-			out := map[string]string{
-				"id":    sh_id,
-				"label": label,
-			}
-
-			err = csv_wr.WriteRow(out)
-
-			if err != nil {
-				return fmt.Errorf("Failed to write %s (%s), %v", id, label, err)
-			}
-		}
-
-		csv_wr.Flush()
-		return nil
-	}
-
-	return fn
 }
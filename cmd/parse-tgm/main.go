@@ -0,0 +1,45 @@
+// parse-tgm is a command-line tool to parse the Library of Congress Thesaurus for Graphic Materials (TGM) SKOS data
+// and output CSV-encoded term ID and label (English, by default) data. It can also be configured to include broader
+// terms, scope notes and variant labels for each term as well as pointers to the Library of Congress Subject Headings
+// (LCSH) that each term corresponds to.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sfomuseum/go-libraryofcongress/app/parse"
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+func main() {
+
+	ctx := context.Background()
+
+	ds, err := dataset.GetDataset(ctx, "tgm")
+
+	if err != nil {
+		log.Fatalf("Failed to load dataset, %v", err)
+	}
+
+	fs := parse.DefaultFlagSet(ds)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "parse-tgm is a command-line tool to parse the Library of Congress Thesaurus for Graphic Materials (TGM) SKOS data and output CSV-encoded term ID and label (English, by default) data. It can also be configured to include broader terms, scope notes and variant labels for each term as well as pointers to the Library of Congress Subject Headings (LCSH) that each term corresponds to.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options] uri(N) uri(N)\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	err = parse.RunWithFlagSet(ctx, fs, ds)
+
+	if err != nil {
+		log.Fatalf("Failed to parse TGM data, %v", err)
+	}
+}
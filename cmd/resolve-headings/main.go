@@ -0,0 +1,329 @@
+// resolve-headings is a command-line tool to resolve the subject heading strings (for example "Anarchism--Italy--History--20th century")
+// in a column of a CSV file to Library of Congress subject heading IDs. The CSV file is enriched with "{COLUMN}_ids", "{COLUMN}_confidence"
+// and "{COLUMN}_unmatched" columns containing the IDs, and confidence scores, of each component of the heading and any subdivisions that
+// could not be resolved.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sfomuseum/go-csvdict"
+	"github.com/sfomuseum/go-libraryofcongress/resolver"
+
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+func main() {
+
+	var data multiString
+	flag.Var(&data, "data", "The URI of an `lcsh.both.ndjson` (or `lcsh.both.ndjson.zip`) file used to resolve headings. This flag may be specified multiple times. Other MADS/RDF files, for example `lcnaf.both.ndjson.zip`, may also be included in order to resolve geographic and name subdivisions.")
+
+	column := flag.String("column", "", "The name of the CSV column containing heading strings to resolve")
+
+	multiple_delimiter := flag.String("multiple-delimiter", "", "If present, the delimiter used to separate multiple heading strings in a single column value. Results for each heading string will be separated by the same delimiter.")
+
+	delimiter := flag.String("delimiter", "|", "The delimiter used to separate the results for each component of a heading in the \"{COLUMN}_ids\" and \"{COLUMN}_confidence\" columns")
+
+	in_place := flag.Bool("in-place", false, "If true, overwrite the input CSV file with the enriched data rather than writing it to STDOUT")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "resolve-headings is a command-line tool to resolve the subject heading strings in a column of a CSV file to Library of Congress subject heading IDs.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options] headings.csv\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if len(data) == 0 {
+		log.Fatalf("Missing -data flag")
+	}
+
+	if *column == "" {
+		log.Fatalf("Missing -column flag")
+	}
+
+	args := flag.Args()
+
+	if len(args) != 1 {
+		log.Fatalf("Expected a single CSV file to resolve")
+	}
+
+	ctx := context.Background()
+
+	opts := &runOptions{
+		Data:              data,
+		Path:              args[0],
+		InPlace:           *in_place,
+		Column:            *column,
+		MultipleDelimiter: *multiple_delimiter,
+		Delimiter:         *delimiter,
+	}
+
+	err := run(ctx, opts, os.Stdout)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// type multiString implements the `flag.Value` interface for flags, like -data, which may be specified multiple times.
+// Values are not split on commas since URIs may contain them.
+type multiString []string
+
+// String() returns the values of 'm' as a comma-separated list.
+func (m *multiString) String() string {
+	return strings.Join(*m, ",")
+}
+
+// Set() appends 'value' to the values of 'm'.
+func (m *multiString) Set(value string) error {
+
+	if value == "" {
+		return fmt.Errorf("Invalid empty value")
+	}
+
+	*m = append(*m, value)
+	return nil
+}
+
+// type runOptions defines configuration options for the `run` function.
+type runOptions struct {
+	// Data is the list of URIs for the MADS/RDF files used to resolve headings.
+	Data []string
+	// Path is the path of the CSV file containing heading strings to resolve.
+	Path string
+	// InPlace is a boolean flag indicating that 'Path' should be overwritten with the enriched data.
+	InPlace bool
+	// Column is the name of the CSV column containing heading strings to resolve.
+	Column string
+	// MultipleDelimiter is the (optional) delimiter used to separate multiple heading strings in a single column value.
+	MultipleDelimiter string
+	// Delimiter is the delimiter used to separate the results for each component of a heading.
+	Delimiter string
+}
+
+// run() resolves the heading strings in the CSV file defined by 'opts' writing the enriched data to 'wr' or, if
+// 'opts.InPlace' is true, to a temporary file which replaces the CSV file once all the rows have been written. The
+// temporary file is removed if an error occurs.
+func run(ctx context.Context, opts *runOptions, wr io.Writer) error {
+
+	r := resolver.NewResolver()
+
+	err := r.IndexURIs(ctx, opts.Data...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to index data, %w", err)
+	}
+
+	path := opts.Path
+
+	in_fh, err := os.Open(path)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %w", path, err)
+	}
+
+	defer in_fh.Close()
+
+	var tmp_fh *os.File
+
+	if opts.InPlace {
+
+		tmp_fh, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+
+		if err != nil {
+			return fmt.Errorf("Failed to create temporary file for %s, %w", path, err)
+		}
+
+		// Both of these are no-ops once the temporary file has been closed and renamed
+
+		defer os.Remove(tmp_fh.Name())
+		defer tmp_fh.Close()
+
+		info, err := in_fh.Stat()
+
+		if err != nil {
+			return fmt.Errorf("Failed to stat %s, %w", path, err)
+		}
+
+		err = tmp_fh.Chmod(info.Mode())
+
+		if err != nil {
+			return fmt.Errorf("Failed to assign permissions to temporary file for %s, %w", path, err)
+		}
+
+		wr = tmp_fh
+	}
+
+	resolve_opts := &resolveOptions{
+		Resolver:          r,
+		Column:            opts.Column,
+		MultipleDelimiter: opts.MultipleDelimiter,
+		Delimiter:         opts.Delimiter,
+	}
+
+	err = resolveCSV(resolve_opts, in_fh, wr)
+
+	if err != nil {
+		return fmt.Errorf("Failed to resolve headings in %s, %w", path, err)
+	}
+
+	if opts.InPlace {
+
+		err = tmp_fh.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to close temporary file for %s, %w", path, err)
+		}
+
+		err = os.Rename(tmp_fh.Name(), path)
+
+		if err != nil {
+			return fmt.Errorf("Failed to replace %s, %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// type resolveOptions defines configuration options for the `resolveCSV` function.
+type resolveOptions struct {
+	// Resolver is the `resolver.Resolver` instance used to resolve heading strings.
+	Resolver *resolver.Resolver
+	// Column is the name of the CSV column containing heading strings to resolve.
+	Column string
+	// MultipleDelimiter is the (optional) delimiter used to separate multiple heading strings in a single column value.
+	MultipleDelimiter string
+	// Delimiter is the delimiter used to separate the results for each component of a heading.
+	Delimiter string
+}
+
+// resolveCSV() reads CSV data from 'r', resolves the heading strings in the column defined by 'opts' and writes
+// the CSV data, with additional "{COLUMN}_ids", "{COLUMN}_confidence" and "{COLUMN}_unmatched" columns, to 'wr'.
+func resolveCSV(opts *resolveOptions, r io.Reader, wr io.Writer) error {
+
+	csv_r, err := csvdict.NewReader(r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create CSV reader, %w", err)
+	}
+
+	has_column := false
+
+	for _, k := range csv_r.Fieldnames {
+
+		if k == opts.Column {
+			has_column = true
+			break
+		}
+	}
+
+	if !has_column {
+		return fmt.Errorf("CSV data is missing '%s' column", opts.Column)
+	}
+
+	ids_col := fmt.Sprintf("%s_ids", opts.Column)
+	confidence_col := fmt.Sprintf("%s_confidence", opts.Column)
+	unmatched_col := fmt.Sprintf("%s_unmatched", opts.Column)
+
+	fieldnames := make([]string, 0)
+
+	for _, k := range csv_r.Fieldnames {
+
+		switch k {
+		case ids_col, confidence_col, unmatched_col:
+			// Replace the results of a previous run
+		default:
+			fieldnames = append(fieldnames, k)
+		}
+	}
+
+	fieldnames = append(fieldnames, ids_col, confidence_col, unmatched_col)
+
+	csv_wr, err := csvdict.NewWriter(wr, fieldnames)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create CSV writer, %w", err)
+	}
+
+	csv_wr.WriteHeader()
+
+	for {
+
+		row, err := csv_r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to read CSV row, %w", err)
+		}
+
+		headings := []string{
+			row[opts.Column],
+		}
+
+		if opts.MultipleDelimiter != "" {
+			headings = strings.Split(row[opts.Column], opts.MultipleDelimiter)
+		}
+
+		count := len(headings)
+
+		ids := make([]string, count)
+		confidence := make([]string, count)
+		unmatched := make([]string, count)
+
+		for i, h := range headings {
+
+			if strings.TrimSpace(h) == "" {
+				continue
+			}
+
+			rsp := opts.Resolver.Resolve(h)
+
+			c_ids := make([]string, len(rsp.Components))
+			c_confidence := make([]string, len(rsp.Components))
+
+			for j, c := range rsp.Components {
+				c_ids[j] = c.ID
+				c_confidence[j] = strconv.FormatFloat(c.Confidence, 'f', 2, 64)
+			}
+
+			ids[i] = strings.Join(c_ids, opts.Delimiter)
+			confidence[i] = strings.Join(c_confidence, opts.Delimiter)
+			unmatched[i] = rsp.Remainder()
+		}
+
+		out := make(map[string]string)
+
+		for _, k := range fieldnames {
+			out[k] = row[k]
+		}
+
+		out[ids_col] = strings.Join(ids, opts.MultipleDelimiter)
+		out[confidence_col] = strings.Join(confidence, opts.MultipleDelimiter)
+		out[unmatched_col] = strings.Join(unmatched, opts.MultipleDelimiter)
+
+		err = csv_wr.WriteRow(out)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write CSV row, %w", err)
+		}
+	}
+
+	csv_wr.Flush()
+
+	return csv_wr.Error()
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/resolver"
+)
+
+// testRecords is a list of (abbreviated) LCSH records used to populate a `resolver.Resolver` instance for testing. There
+// are two main headings labelled "Mercury" so that label is ambiguous.
+var testRecords = []string{
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85004812", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Anarchism"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/names/n79021206", "@type": ["madsrdf:Authority", "madsrdf:Geographic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Italy"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85083790", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Mercury"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85083794", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Mercury"}, "madsrdf:hasVariant": {"@id": "_:N1"}}, {"@id": "_:N1", "@type": ["madsrdf:Variant", "madsrdf:Topic"], "madsrdf:variantLabel": {"@language": "en", "@value": "Mercury (Planet)"}}]}`,
+}
+
+func TestResolveCSV(t *testing.T) {
+
+	r := resolver.NewResolver()
+
+	for i, body := range testRecords {
+
+		a, err := authority.ParseRecord([]byte(body))
+
+		if err != nil {
+			t.Fatalf("Failed to parse test record %d, %v", i, err)
+		}
+
+		r.Add(a)
+	}
+
+	tests := map[string]struct {
+		Heading           string
+		MultipleDelimiter string
+		Expected          string
+	}{
+		"matched": {
+			Heading:  "Anarchism--Italy",
+			Expected: "Anarchism--Italy,sh85004812|n79021206,1.00|1.00,",
+		},
+		"variant": {
+			Heading:  "Mercury (Planet)",
+			Expected: "Mercury (Planet),sh85083794,0.80,",
+		},
+		"unmatched": {
+			Heading:  "Anarchism--Foo",
+			Expected: "Anarchism--Foo,sh85004812|,1.00|0.00,Foo",
+		},
+		"ambiguous": {
+			Heading:  "Mercury",
+			Expected: "Mercury,sh85083790,0.50,",
+		},
+		"empty": {
+			Heading:  "",
+			Expected: ",,,",
+		},
+		"multiple": {
+			Heading:           "Anarchism;Foo",
+			MultipleDelimiter: ";",
+			Expected:          "Anarchism;Foo,sh85004812;,1.00;0.00,;Foo",
+		},
+	}
+
+	for name, test := range tests {
+
+		opts := &resolveOptions{
+			Resolver:          r,
+			Column:            "subject",
+			MultipleDelimiter: test.MultipleDelimiter,
+			Delimiter:         "|",
+		}
+
+		in := "id,subject\n1," + test.Heading + "\n"
+
+		var out bytes.Buffer
+
+		err := resolveCSV(opts, strings.NewReader(in), &out)
+
+		if err != nil {
+			t.Fatalf("Failed to resolve %s heading, %v", name, err)
+		}
+
+		expected := "id,subject,subject_ids,subject_confidence,subject_unmatched\n1," + test.Expected + "\n"
+
+		if out.String() != expected {
+			t.Fatalf("Unexpected output for %s heading: '%s' (expected '%s')", name, out.String(), expected)
+		}
+	}
+
+	// The results of a previous run are replaced rather than duplicated
+
+	in := "id,subject,subject_ids,subject_confidence,subject_unmatched\n1,Anarchism,x,0.00,x\n"
+
+	var out bytes.Buffer
+
+	err := resolveCSV(&resolveOptions{Resolver: r, Column: "subject", Delimiter: "|"}, strings.NewReader(in), &out)
+
+	if err != nil {
+		t.Fatalf("Failed to resolve previously resolved headings, %v", err)
+	}
+
+	if out.String() != "id,subject,subject_ids,subject_confidence,subject_unmatched\n1,Anarchism,sh85004812,1.00,\n" {
+		t.Fatalf("Unexpected output for previously resolved headings: '%s'", out.String())
+	}
+
+	err = resolveCSV(&resolveOptions{Resolver: r, Column: "heading", Delimiter: "|"}, strings.NewReader("id,subject\n"), &out)
+
+	if err == nil {
+		t.Fatalf("Expected missing column to fail")
+	}
+}
+
+func TestRunInPlace(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "headings.csv")
+
+	err := os.WriteFile(path, []byte("id,subject\n1,Wide-band amplifiers\n"), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	opts := &runOptions{
+		Data:      []string{"../../fixtures/lcsh.sample.ndjson"},
+		Path:      path,
+		InPlace:   true,
+		Column:    "subject",
+		Delimiter: "|",
+	}
+
+	err = run(ctx, opts, nil)
+
+	if err != nil {
+		t.Fatalf("Failed to run, %v", err)
+	}
+
+	body, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", path, err)
+	}
+
+	if string(body) != "id,subject,subject_ids,subject_confidence,subject_unmatched\n1,Wide-band amplifiers,sh85016999,0.80,\n" {
+		t.Fatalf("Unexpected output: '%s'", body)
+	}
+
+	// A failed run should leave the input, and nothing else, in place
+
+	opts.Column = "heading"
+
+	err = run(ctx, opts, nil)
+
+	if err == nil {
+		t.Fatalf("Expected missing column to fail")
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", dir, err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != "headings.csv" {
+		t.Fatalf("Unexpected files after failed run: %v", entries)
+	}
+}
+
+func TestMultiString(t *testing.T) {
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var data multiString
+	fs.Var(&data, "data", "")
+
+	err := fs.Parse([]string{"-data", "lcsh.both.ndjson.zip", "-data", "https://example.com/data?files=lcsh,lcnaf"})
+
+	if err != nil {
+		t.Fatalf("Failed to parse flags, %v", err)
+	}
+
+	if len(data) != 2 || data[0] != "lcsh.both.ndjson.zip" || data[1] != "https://example.com/data?files=lcsh,lcnaf" {
+		t.Fatalf("Unexpected values: %v", data)
+	}
+
+	err = fs.Parse([]string{"-data", ""})
+
+	if err == nil {
+		t.Fatalf("Expected empty value to fail")
+	}
+}
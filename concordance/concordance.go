@@ -0,0 +1,207 @@
+// Package concordance provides interfaces and methods for recognizing pointers to external (non-LoC) vocabularies,
+// for example Wikidata, VIAF or Getty, in Library of Congress authority records.
+package concordance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+// type Concordance defines an interface for recognizing the URIs of an external vocabulary and deriving
+// vocabulary-specific identifiers from them.
+type Concordance interface {
+	// Name returns the name of the external vocabulary, for example "wikidata".
+	Name() string
+	// Identifier returns the vocabulary-specific identifier for a URI and a boolean value indicating whether
+	// the URI belongs to the external vocabulary.
+	Identifier(string) (string, bool)
+}
+
+// type ConcordanceInitializeFunc is a function used to initialize an implementation of the `Concordance` interface.
+type ConcordanceInitializeFunc func(ctx context.Context, uri string) (Concordance, error)
+
+// type Match is an external authority that has been recognized by a `Concordance` implementation.
+type Match struct {
+	// Concordance is the name of the `Concordance` implementation that recognized the external authority.
+	Concordance string `json:"concordance"`
+	// ID is the vocabulary-specific identifier for the external authority, for example "Q31886764".
+	ID string `json:"id"`
+	// URI is the URI of the external authority.
+	URI string `json:"uri"`
+	// Match is the type of match, either `authority.MatchClose` or `authority.MatchExact`.
+	Match string `json:"match"`
+}
+
+// concordances is a `aaronland/go-roster.Roster` instance used to maintain a list of registered `ConcordanceInitializeFunc` initialization functions.
+var concordances roster.Roster
+
+// ensureConcordanceRoster() ensures that a `aaronland/go-roster.Roster` instance used to maintain a list of registered `ConcordanceInitializeFunc`
+// initialization functions is present
+func ensureConcordanceRoster() error {
+
+	if concordances == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new roster, %w", err)
+		}
+
+		concordances = r
+	}
+
+	return nil
+}
+
+// RegisterConcordance() associates 'scheme' with 'init_func' in an internal list of avilable `Concordance` implementations.
+func RegisterConcordance(ctx context.Context, scheme string, f ConcordanceInitializeFunc) error {
+
+	err := ensureConcordanceRoster()
+
+	if err != nil {
+		return fmt.Errorf("Failed to ensure roster, %w", err)
+	}
+
+	return concordances.Register(ctx, scheme, f)
+}
+
+// Schemes() returns the list of schemes that have been "registered".
+func Schemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureConcordanceRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range concordances.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
+
+// NewConcordance() returns a new `Concordance` instance derived from 'uri'. The semantics of and requirements for
+// 'uri' as specific to the package implementing the interface.
+func NewConcordance(ctx context.Context, uri string) (Concordance, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	scheme := u.Scheme
+
+	err = ensureConcordanceRoster()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to ensure roster, %w", err)
+	}
+
+	i, err := concordances.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive concordance for '%s', %w", scheme, err)
+	}
+
+	f := i.(ConcordanceInitializeFunc)
+	return f(ctx, uri)
+}
+
+// NewConcordances() returns a list of `Concordance` instances for each of the (comma-separated) names in 'str',
+// for example "wikidata,viaf". The name "all" will be expanded to include every registered scheme. Duplicate
+// names are ignored.
+func NewConcordances(ctx context.Context, str string) ([]Concordance, error) {
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, n := range strings.Split(str, ",") {
+
+		n = strings.ToLower(strings.TrimSpace(n))
+
+		if n == "" {
+			continue
+		}
+
+		candidates := []string{n}
+
+		if n == "all" {
+
+			candidates = make([]string, 0)
+
+			for _, s := range Schemes() {
+				candidates = append(candidates, strings.TrimSuffix(s, "://"))
+			}
+		}
+
+		for _, c := range candidates {
+
+			if seen[c] {
+				continue
+			}
+
+			seen[c] = true
+			names = append(names, c)
+		}
+	}
+
+	list := make([]Concordance, len(names))
+
+	for i, n := range names {
+
+		uri := fmt.Sprintf("%s://", n)
+		c, err := NewConcordance(ctx, uri)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create concordance for '%s', %w", n, err)
+		}
+
+		list[i] = c
+	}
+
+	return list, nil
+}
+
+// Matches() returns the list of external authorities associated with 'a' that are recognized by one or more
+// 'concordances'.
+func Matches(a *authority.Authority, concordances ...Concordance) []*Match {
+
+	matches := make([]*Match, 0)
+
+	for _, ext := range a.ExternalAuthorities {
+
+		for _, c := range concordances {
+
+			id, ok := c.Identifier(ext.ID)
+
+			if !ok {
+				continue
+			}
+
+			m := &Match{
+				Concordance: c.Name(),
+				ID:          id,
+				URI:         ext.ID,
+				Match:       ext.Match,
+			}
+
+			matches = append(matches, m)
+			break
+		}
+	}
+
+	return matches
+}
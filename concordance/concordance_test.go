@@ -0,0 +1,95 @@
+package concordance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestRegisterConcordance(t *testing.T) {
+
+	ctx := context.Background()
+
+	err := RegisterConcordance(ctx, "wikidata", NewPrefixConcordance)
+
+	if err == nil {
+		t.Fatalf("Expected wikidata concordance to be registered.")
+	}
+}
+
+func TestNewConcordances(t *testing.T) {
+
+	ctx := context.Background()
+
+	list, err := NewConcordances(ctx, "wikidata, viaf")
+
+	if err != nil {
+		t.Fatalf("Failed to create concordances, %v", err)
+	}
+
+	if len(list) != 2 || list[0].Name() != "wikidata" || list[1].Name() != "viaf" {
+		t.Fatalf("Unexpected concordances: %v", list)
+	}
+
+	all, err := NewConcordances(ctx, "all")
+
+	if err != nil {
+		t.Fatalf("Failed to create all concordances, %v", err)
+	}
+
+	if len(all) != len(Schemes()) {
+		t.Fatalf("Unexpected number of concordances: %d", len(all))
+	}
+
+	all, err = NewConcordances(ctx, "wikidata,all")
+
+	if err != nil {
+		t.Fatalf("Failed to create all concordances, %v", err)
+	}
+
+	if len(all) != len(Schemes()) || all[0].Name() != "wikidata" {
+		t.Fatalf("Unexpected concordances: %v", all)
+	}
+
+	_, err = NewConcordances(ctx, "wikidata,bogus")
+
+	if err == nil {
+		t.Fatalf("Expected unknown concordance to fail")
+	}
+}
+
+func TestMatches(t *testing.T) {
+
+	ctx := context.Background()
+
+	a := &authority.Authority{
+		ID: "http://id.loc.gov/authorities/names/n79021164",
+		ExternalAuthorities: []*authority.ExternalAuthority{
+			{ID: "http://www.wikidata.org/entity/Q42", Match: authority.MatchClose},
+			{ID: "http://viaf.org/viaf/113230702", Match: authority.MatchExact},
+			{ID: "http://isni.org/isni/0000000121251313", Match: authority.MatchExact},
+			{ID: "http://example.com/42", Match: authority.MatchClose},
+		},
+	}
+
+	list, err := NewConcordances(ctx, "viaf,wikidata")
+
+	if err != nil {
+		t.Fatalf("Failed to create concordances, %v", err)
+	}
+
+	matches := Matches(a, list...)
+
+	if len(matches) != 2 {
+		t.Fatalf("Unexpected number of matches: %d", len(matches))
+	}
+
+	if matches[0].Concordance != "wikidata" || matches[0].ID != "Q42" || matches[0].Match != authority.MatchClose {
+		t.Fatalf("Unexpected match: %v", matches[0])
+	}
+
+	if matches[1].Concordance != "viaf" || matches[1].ID != "113230702" || matches[1].Match != authority.MatchExact {
+		t.Fatalf("Unexpected match: %v", matches[1])
+	}
+}
@@ -0,0 +1,85 @@
+package concordance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+)
+
+// type DatasetConcordance implements the `Concordance` interface for the Library of Congress vocabularies defined
+// in the `dataset` package. This is used to recognize pointers between LoC vocabularies, for example from terms in
+// the Thesaurus for Graphic Materials (TGM) to the subject headings (LCSH) they correspond to.
+type DatasetConcordance struct {
+	Concordance
+	// dataset is the `dataset.Dataset` instance for the vocabulary.
+	dataset *dataset.Dataset
+	// prefix is the URI prefix (without a scheme) for the vocabulary.
+	prefix string
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	for _, name := range dataset.Names() {
+		RegisterConcordance(ctx, name, NewDatasetConcordance)
+	}
+}
+
+// NewDatasetConcordance creates a new instance that implements the `Concordance` interface for one of the
+// vocabularies registered with the `dataset` package configured by 'uri' which is expected to take the form of:
+//
+//	{NAME}://
+//
+// Where {NAME} is the name of the dataset, for example "lcsh" or "tgm".
+func NewDatasetConcordance(ctx context.Context, uri string) (Concordance, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	ds, err := dataset.GetDataset(ctx, u.Scheme)
+
+	if err != nil {
+		return nil, fmt.Errorf("Unknown concordance '%s', %w", u.Scheme, err)
+	}
+
+	c := &DatasetConcordance{
+		dataset: ds,
+		prefix:  stripScheme(ds.Prefix),
+	}
+
+	return c, nil
+}
+
+// Name() returns the name of the vocabulary.
+func (c *DatasetConcordance) Name() string {
+	return c.dataset.Name
+}
+
+// Identifier() returns the identifier for 'uri', for example "sh85101206", and a boolean value indicating
+// whether 'uri' is the URI of a record in the vocabulary.
+func (c *DatasetConcordance) Identifier(uri string) (string, bool) {
+
+	str_uri := stripScheme(uri)
+
+	if !strings.HasPrefix(str_uri, c.prefix) {
+		return "", false
+	}
+
+	id := strings.TrimPrefix(str_uri, c.prefix)
+
+	id, _, _ = strings.Cut(id, "#")
+	id = strings.Trim(id, "/")
+
+	if id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+
+	return id, true
+}
@@ -0,0 +1,44 @@
+package concordance
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDatasetConcordance(t *testing.T) {
+
+	ctx := context.Background()
+
+	c, err := NewConcordance(ctx, "lcsh://")
+
+	if err != nil {
+		t.Fatalf("Failed to create concordance, %v", err)
+	}
+
+	if c.Name() != "lcsh" {
+		t.Fatalf("Unexpected name: %s", c.Name())
+	}
+
+	tests := map[string]string{
+		"http://id.loc.gov/authorities/subjects/sh85101206":         "sh85101206",
+		"https://id.loc.gov/authorities/subjects/sh85101206":        "sh85101206",
+		"http://id.loc.gov/authorities/subjects/sh85101206#concept": "sh85101206",
+		"http://id.loc.gov/authorities/subjects/collection_LCSH/x":  "",
+		"http://id.loc.gov/vocabulary/graphicMaterials/tgm007721":   "",
+	}
+
+	for uri, expected := range tests {
+
+		id, ok := c.Identifier(uri)
+
+		if ok != (expected != "") || id != expected {
+			t.Fatalf("Unexpected identifier for %s: '%s' (expected '%s')", uri, id, expected)
+		}
+	}
+
+	_, err = NewConcordance(ctx, "tgm://")
+
+	if err != nil {
+		t.Fatalf("Failed to create TGM concordance, %v", err)
+	}
+}
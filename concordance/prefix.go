@@ -0,0 +1,151 @@
+package concordance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// prefixes is a lookup table of known external vocabularies and the URI prefixes used by their authorities.
+// Prefixes are matched regardless of whether they use the "http" or "https" scheme.
+var prefixes = map[string][]string{
+	"aat": {
+		"http://vocab.getty.edu/aat/",
+	},
+	"bnf": {
+		"http://data.bnf.fr/ark:/12148/",
+		"http://catalogue.bnf.fr/ark:/12148/",
+	},
+	"geonames": {
+		"http://sws.geonames.org/",
+		"http://www.geonames.org/",
+	},
+	"gnd": {
+		"http://d-nb.info/gnd/",
+	},
+	"isni": {
+		"http://isni.org/isni/",
+		"http://www.isni.org/isni/",
+	},
+	"ndl": {
+		"http://id.ndl.go.jp/auth/ndlsh/",
+		"http://id.ndl.go.jp/auth/ndlna/",
+		"http://id.ndl.go.jp/auth/entity/",
+	},
+	"tgn": {
+		"http://vocab.getty.edu/tgn/",
+	},
+	"ulan": {
+		"http://vocab.getty.edu/ulan/",
+	},
+	"viaf": {
+		"http://viaf.org/viaf/",
+	},
+	"wikidata": {
+		"http://www.wikidata.org/entity/",
+	},
+	"worldcat": {
+		"http://id.worldcat.org/fast/",
+	},
+}
+
+// type PrefixConcordance implements the `Concordance` interface for external vocabularies whose authority URIs
+// all share one or more prefixes.
+type PrefixConcordance struct {
+	Concordance
+	// name is the name of the external vocabulary.
+	name string
+	// prefixes is the list of URI prefixes (without a scheme) for the external vocabulary.
+	prefixes []string
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	for name := range prefixes {
+		RegisterConcordance(ctx, name, NewPrefixConcordance)
+	}
+}
+
+// NewPrefixConcordance creates a new instance that implements the `Concordance` interface for one of the known
+// external vocabularies configured by 'uri' which is expected to take the form of:
+//
+//	{NAME}://
+//
+// Where {NAME} may be: aat, bnf, geonames, gnd, isni, ndl, tgn, ulan, viaf, wikidata or worldcat (FAST).
+func NewPrefixConcordance(ctx context.Context, uri string) (Concordance, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	name := strings.ToLower(u.Scheme)
+
+	uris, ok := prefixes[name]
+
+	if !ok {
+		return nil, fmt.Errorf("Unknown concordance '%s'", name)
+	}
+
+	c := &PrefixConcordance{
+		name:     name,
+		prefixes: make([]string, len(uris)),
+	}
+
+	for i, p := range uris {
+		c.prefixes[i] = stripScheme(p)
+	}
+
+	return c, nil
+}
+
+// Name() returns the name of the external vocabulary.
+func (c *PrefixConcordance) Name() string {
+	return c.name
+}
+
+// Identifier() returns the identifier for 'uri' and a boolean value indicating whether 'uri' starts with one
+// of the prefixes for the external vocabulary. The identifier is the first path component following the prefix
+// with any trailing fragment removed, for example "http://sws.geonames.org/1880251/" becomes "1880251".
+func (c *PrefixConcordance) Identifier(uri string) (string, bool) {
+
+	str_uri := stripScheme(uri)
+
+	for _, p := range c.prefixes {
+
+		if !strings.HasPrefix(str_uri, p) {
+			continue
+		}
+
+		id := strings.TrimPrefix(str_uri, p)
+
+		id, _, _ = strings.Cut(id, "#")
+		id = strings.Trim(id, "/")
+		id, _, _ = strings.Cut(id, "/")
+
+		if id == "" {
+			return "", false
+		}
+
+		return id, true
+	}
+
+	return "", false
+}
+
+// stripScheme() removes a leading "http://" or "https://" from 'uri'.
+func stripScheme(uri string) string {
+
+	for _, scheme := range []string{"http://", "https://"} {
+
+		if strings.HasPrefix(uri, scheme) {
+			return strings.TrimPrefix(uri, scheme)
+		}
+	}
+
+	return uri
+}
@@ -0,0 +1,53 @@
+package concordance
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrefixConcordance(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]map[string]string{
+		"aat://":      {"http://vocab.getty.edu/aat/300264092": "300264092"},
+		"bnf://":      {"http://data.bnf.fr/ark:/12148/cb11932084t#about": "cb11932084t"},
+		"geonames://": {"https://sws.geonames.org/1880251/": "1880251"},
+		"gnd://":      {"http://d-nb.info/gnd/4146535-0": "4146535-0"},
+		"isni://":     {"http://isni.org/isni/0000000121251313": "0000000121251313"},
+		"ndl://":      {"http://id.ndl.go.jp/auth/ndlsh/00562813": "00562813"},
+		"tgn://":      {"http://vocab.getty.edu/tgn/7000874": "7000874"},
+		"ulan://":     {"http://vocab.getty.edu/ulan/500115493": "500115493"},
+		"viaf://":     {"http://viaf.org/viaf/113230702": "113230702"},
+		"wikidata://": {"http://www.wikidata.org/entity/Q31886764": "Q31886764"},
+		"worldcat://": {"http://id.worldcat.org/fast/839142": "839142"},
+	}
+
+	for uri, candidates := range tests {
+
+		c, err := NewConcordance(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create concordance for %s, %v", uri, err)
+		}
+
+		for candidate, expected := range candidates {
+
+			id, ok := c.Identifier(candidate)
+
+			if !ok {
+				t.Fatalf("Expected %s to match %s", uri, candidate)
+			}
+
+			if id != expected {
+				t.Fatalf("Unexpected identifier for %s: %s (expected %s)", candidate, id, expected)
+			}
+		}
+
+		_, ok := c.Identifier("http://example.com/1234")
+
+		if ok {
+			t.Fatalf("Did not expect %s to match example.com", uri)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package dataset provides a registry of the Library of Congress authority vocabularies (datasets) published by
+// id.loc.gov as MADS/RDF or SKOS (`*.both.ndjson`) files, for example LCSH, LCNAF or TGM, and the conventions used
+// to identify and label the records in each vocabulary.
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+)
+
+// type Dataset defines a Library of Congress authority vocabulary and the conventions used by its records.
+type Dataset struct {
+	// Name is the short name of the vocabulary, for example "lcsh".
+	Name string `json:"name"`
+	// Title is the full name of the vocabulary, for example "Library of Congress Subject Headings".
+	Title string `json:"title"`
+	// Prefix is the URI prefix shared by all the records in the vocabulary, for example "http://id.loc.gov/authorities/subjects/".
+	Prefix string `json:"prefix"`
+	// Noun is the (singular) name used to describe records in the vocabulary, for example "subject heading".
+	Noun string `json:"noun"`
+	// LCCNPrefixes is the list of (alphabetic) Library of Congress Control Number prefixes used by records in the
+	// vocabulary, for example "sh" for LCSH. Records in vocabularies which do not use LCCNs as identifiers have none.
+	LCCNPrefixes []string `json:"lccn_prefixes,omitempty"`
+	// Languages is the default list of preferred language tags used to select the label for records in the vocabulary.
+	Languages []string `json:"languages"`
+	// Catalog is a boolean flag indicating that the vocabulary is too large to track the records that have been
+	// processed in memory and that an on-disk `libraryofcongress.Catalog` should be used instead.
+	Catalog bool `json:"catalog,omitempty"`
+}
+
+// Contains() returns a boolean value indicating whether 'uri' is the URI of a record in 'ds'.
+func (ds *Dataset) Contains(uri string) bool {
+	return strings.HasPrefix(uri, ds.Prefix)
+}
+
+// Identifier() returns the vocabulary-specific identifier for 'uri', for example "sh85016999", and a boolean value
+// indicating whether 'uri' is the URI of a record in 'ds'.
+func (ds *Dataset) Identifier(uri string) (string, bool) {
+
+	if !ds.Contains(uri) {
+		return "", false
+	}
+
+	id := strings.TrimPrefix(uri, ds.Prefix)
+
+	if id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+
+	return id, true
+}
+
+// Identifiers() returns the list of vocabulary-specific identifiers for the URIs in 'uris' which are records in 'ds'.
+// URIs for records in other vocabularies are ignored.
+func (ds *Dataset) Identifiers(uris []string) []string {
+
+	ids := make([]string, 0)
+
+	for _, uri := range uris {
+
+		id, ok := ds.Identifier(uri)
+
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// URI() returns the URI for the record in 'ds' whose vocabulary-specific identifier is 'id'.
+func (ds *Dataset) URI(id string) string {
+	return ds.Prefix + id
+}
+
+// Plural() returns the plural form of the name used to describe records in 'ds', for example "subject headings".
+func (ds *Dataset) Plural() string {
+	return ds.Noun + "s"
+}
+
+// String() returns the short name of 'ds'.
+func (ds *Dataset) String() string {
+	return ds.Name
+}
+
+// datasets is a `aaronland/go-roster.Roster` instance used to maintain a list of registered `Dataset` instances.
+var datasets roster.Roster
+
+// ensureDatasetRoster() ensures that a `aaronland/go-roster.Roster` instance used to maintain a list of registered
+// `Dataset` instances is present
+func ensureDatasetRoster() error {
+
+	if datasets == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new roster, %w", err)
+		}
+
+		datasets = r
+	}
+
+	return nil
+}
+
+// RegisterDataset() adds 'ds' to an internal list of available `Dataset` instances, keyed by its name.
+func RegisterDataset(ctx context.Context, ds *Dataset) error {
+
+	err := ensureDatasetRoster()
+
+	if err != nil {
+		return fmt.Errorf("Failed to ensure roster, %w", err)
+	}
+
+	if ds.Name == "" {
+		return fmt.Errorf("Dataset is missing a name")
+	}
+
+	if ds.Prefix == "" {
+		return fmt.Errorf("Dataset '%s' is missing a prefix", ds.Name)
+	}
+
+	return datasets.Register(ctx, ds.Name, ds)
+}
+
+// Names() returns the sorted list of names of the datasets that have been "registered".
+func Names() []string {
+
+	ctx := context.Background()
+	names := []string{}
+
+	err := ensureDatasetRoster()
+
+	if err != nil {
+		return names
+	}
+
+	for _, dr := range datasets.Drivers(ctx) {
+		names = append(names, strings.ToLower(dr))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// GetDataset() returns the registered `Dataset` instance whose name is 'name'.
+func GetDataset(ctx context.Context, name string) (*Dataset, error) {
+
+	err := ensureDatasetRoster()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to ensure roster, %w", err)
+	}
+
+	i, err := datasets.Driver(ctx, strings.TrimSpace(name))
+
+	if err != nil {
+		return nil, fmt.Errorf("Unknown dataset '%s', %w", name, err)
+	}
+
+	return i.(*Dataset), nil
+}
+
+// DatasetForURI() returns the registered `Dataset` instance containing 'uri', the URI of an authority record, and a
+// boolean value indicating whether a matching dataset was found.
+func DatasetForURI(ctx context.Context, uri string) (*Dataset, bool) {
+
+	for _, name := range Names() {
+
+		ds, err := GetDataset(ctx, name)
+
+		if err != nil {
+			continue
+		}
+
+		if ds.Contains(uri) {
+			return ds, true
+		}
+	}
+
+	return nil, false
+}
+
+// DatasetForLCCN() returns the registered `Dataset` instance whose records are identified by 'lccn', a Library of
+// Congress Control Number (for example "sh 85016999" or "n79021164"), and a boolean value indicating whether a
+// matching dataset was found. The prefix of 'lccn' is compared case-insensitively and whitespace is ignored.
+func DatasetForLCCN(ctx context.Context, lccn string) (*Dataset, bool) {
+
+	lccn = NormalizeLCCN(lccn)
+
+	prefix := strings.TrimRightFunc(lccn, func(r rune) bool {
+		return r >= '0' && r <= '9'
+	})
+
+	if prefix == "" || prefix == lccn {
+		return nil, false
+	}
+
+	for _, name := range Names() {
+
+		ds, err := GetDataset(ctx, name)
+
+		if err != nil {
+			continue
+		}
+
+		for _, p := range ds.LCCNPrefixes {
+
+			if p == prefix {
+				return ds, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// NormalizeLCCN() returns 'lccn', a Library of Congress Control Number, with whitespace removed and any alphabetic
+// prefix lower-cased, for example "sh 85016999 " becomes "sh85016999".
+func NormalizeLCCN(lccn string) string {
+
+	lccn = strings.Join(strings.Fields(lccn), "")
+	return strings.ToLower(lccn)
+}
@@ -0,0 +1,142 @@
+package dataset
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+
+	expected := "cyac,lcdgt,lcgft,lcmpt,lcnaf,lcsh,tgm"
+	names := strings.Join(Names(), ",")
+
+	if names != expected {
+		t.Fatalf("Unexpected names: %s (expected %s)", names, expected)
+	}
+}
+
+func TestGetDataset(t *testing.T) {
+
+	ctx := context.Background()
+
+	ds, err := GetDataset(ctx, "LCGFT")
+
+	if err != nil {
+		t.Fatalf("Failed to get dataset, %v", err)
+	}
+
+	if ds.Prefix != "http://id.loc.gov/authorities/genreForms/" {
+		t.Fatalf("Unexpected prefix: %s", ds.Prefix)
+	}
+
+	if ds.Plural() != "genre/form terms" {
+		t.Fatalf("Unexpected plural: %s", ds.Plural())
+	}
+
+	_, err = GetDataset(ctx, "bogus")
+
+	if err == nil {
+		t.Fatalf("Expected error for unknown dataset")
+	}
+
+	err = RegisterDataset(ctx, &Dataset{Name: "lcsh", Prefix: "http://example.com/"})
+
+	if err == nil {
+		t.Fatalf("Expected error registering duplicate dataset")
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string][2]string{
+		"http://id.loc.gov/authorities/subjects/sh85016999":             {"lcsh", "sh85016999"},
+		"http://id.loc.gov/authorities/names/n79021164":                 {"lcnaf", "n79021164"},
+		"http://id.loc.gov/authorities/genreForms/gf2014026339":         {"lcgft", "gf2014026339"},
+		"http://id.loc.gov/authorities/demographicTerms/dg2015060010":   {"lcdgt", "dg2015060010"},
+		"http://id.loc.gov/authorities/performanceMediums/mp2013015550": {"lcmpt", "mp2013015550"},
+		"http://id.loc.gov/authorities/childrensSubjects/sj96004895":    {"cyac", "sj96004895"},
+		"http://id.loc.gov/vocabulary/graphicMaterials/tgm007721":       {"tgm", "tgm007721"},
+	}
+
+	for uri, expected := range tests {
+
+		ds, ok := DatasetForURI(ctx, uri)
+
+		if !ok {
+			t.Fatalf("Failed to find dataset for %s", uri)
+		}
+
+		if ds.Name != expected[0] {
+			t.Fatalf("Unexpected dataset for %s: %s (expected %s)", uri, ds.Name, expected[0])
+		}
+
+		id, ok := ds.Identifier(uri)
+
+		if !ok || id != expected[1] {
+			t.Fatalf("Unexpected identifier for %s: %s (expected %s)", uri, id, expected[1])
+		}
+	}
+
+	_, ok := DatasetForURI(ctx, "http://id.loc.gov/vocabulary/relators/aut")
+
+	if ok {
+		t.Fatalf("Expected no dataset for relator URI")
+	}
+
+	ds, _ := GetDataset(ctx, "lcsh")
+
+	_, ok = ds.Identifier("http://id.loc.gov/authorities/subjects/collection_LCSH_General/x")
+
+	if ok {
+		t.Fatalf("Expected nested URI to be rejected")
+	}
+
+	ids := ds.Identifiers([]string{
+		"http://id.loc.gov/authorities/subjects/sh85016999",
+		"http://id.loc.gov/authorities/names/n79021164",
+	})
+
+	if strings.Join(ids, ",") != "sh85016999" {
+		t.Fatalf("Unexpected identifiers: %v", ids)
+	}
+}
+
+func TestDatasetForLCCN(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]string{
+		"sh 85016999":  "lcsh",
+		"n  79021164 ": "lcnaf",
+		"no2001012345": "lcnaf",
+		"GF2014026339": "lcgft",
+		"sj 96004895":  "cyac",
+	}
+
+	for lccn, expected := range tests {
+
+		ds, ok := DatasetForLCCN(ctx, lccn)
+
+		if !ok || ds.Name != expected {
+			t.Fatalf("Unexpected dataset for '%s': %v (expected %s)", lccn, ds, expected)
+		}
+
+		uri := ds.URI(NormalizeLCCN(lccn))
+
+		if !ds.Contains(uri) {
+			t.Fatalf("Expected %s to contain %s", ds.Name, uri)
+		}
+	}
+
+	for _, lccn := range []string{"85016999", "tgm007721", "xx123", ""} {
+
+		_, ok := DatasetForLCCN(ctx, lccn)
+
+		if ok {
+			t.Fatalf("Expected no dataset for '%s'", lccn)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package dataset
+
+import (
+	"context"
+)
+
+// known is the list of Library of Congress authority vocabularies published by id.loc.gov that are registered by default.
+var known = []*Dataset{
+	{
+		Name:         "lcsh",
+		Title:        "Library of Congress Subject Headings",
+		Prefix:       "http://id.loc.gov/authorities/subjects/",
+		Noun:         "subject heading",
+		LCCNPrefixes: []string{"sh"},
+		Languages:    []string{"en"},
+	},
+	{
+		// LCNAF labels are not language-tagged and are frequently in languages other than English
+		// so fall back to (the first) untagged label if there is no English label.
+		Name:         "lcnaf",
+		Title:        "Library of Congress Name Authority File",
+		Prefix:       "http://id.loc.gov/authorities/names/",
+		Noun:         "name",
+		LCCNPrefixes: []string{"n", "nb", "no", "nr"},
+		Languages:    []string{"en", "und"},
+		Catalog:      true,
+	},
+	{
+		Name:         "lcgft",
+		Title:        "Library of Congress Genre/Form Terms",
+		Prefix:       "http://id.loc.gov/authorities/genreForms/",
+		Noun:         "genre/form term",
+		LCCNPrefixes: []string{"gf"},
+		Languages:    []string{"en"},
+	},
+	{
+		Name:         "lcdgt",
+		Title:        "Library of Congress Demographic Group Terms",
+		Prefix:       "http://id.loc.gov/authorities/demographicTerms/",
+		Noun:         "demographic group term",
+		LCCNPrefixes: []string{"dg"},
+		Languages:    []string{"en"},
+	},
+	{
+		Name:         "lcmpt",
+		Title:        "Library of Congress Medium of Performance Thesaurus for Music",
+		Prefix:       "http://id.loc.gov/authorities/performanceMediums/",
+		Noun:         "medium of performance term",
+		LCCNPrefixes: []string{"mp"},
+		Languages:    []string{"en"},
+	},
+	{
+		Name:         "cyac",
+		Title:        "Children's Subject Headings",
+		Prefix:       "http://id.loc.gov/authorities/childrensSubjects/",
+		Noun:         "children's subject heading",
+		LCCNPrefixes: []string{"sj"},
+		Languages:    []string{"en"},
+	},
+	{
+		// TGM is published as SKOS concepts rather than MADS/RDF authorities.
+		Name:      "tgm",
+		Title:     "Thesaurus for Graphic Materials",
+		Prefix:    "http://id.loc.gov/vocabulary/graphicMaterials/",
+		Noun:      "term",
+		Languages: []string{"en"},
+	},
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	for _, ds := range known {
+		RegisterDataset(ctx, ds)
+	}
+}
@@ -0,0 +1,32 @@
+// Package datetime provides methods for parsing the `xsd:dateTime` and `xsd:date` values in LoC data files. It is
+// shared by the `authority` and `walk` packages so that neither depends on the other to parse dates.
+package datetime
+
+import (
+	"fmt"
+	"time"
+)
+
+// layouts is the list of layouts used to parse `xsd:dateTime` and `xsd:date` values.
+var layouts = []string{
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+// Parse() parses 'str', an `xsd:dateTime` or `xsd:date` value, in to a `time.Time` instance. 'str' is expected to be
+// a date ("2006-01-02"), a date and time ("2006-01-02T15:04:05") or an RFC3339 timestamp. Dates and times without a
+// time zone are assumed to be UTC.
+func Parse(str string) (time.Time, error) {
+
+	for _, layout := range layouts {
+
+		t, err := time.Parse(layout, str)
+
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid date '%s'", str)
+}
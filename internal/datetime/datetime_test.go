@@ -0,0 +1,38 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := map[string]time.Time{
+		"2011-06-04":                time.Date(2011, 6, 4, 0, 0, 0, 0, time.UTC),
+		"2011-06-04T08:27:18":       time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+		"2011-06-04T08:27:18Z":      time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+		"2011-06-04T10:27:18+02:00": time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+	}
+
+	for str, expected := range tests {
+
+		d, err := Parse(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if !d.Equal(expected) {
+			t.Fatalf("Unexpected date for '%s': %v", str, d)
+		}
+	}
+
+	for _, str := range []string{"", "June 4th", "04/06/2011"} {
+
+		_, err := Parse(str)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", str)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package lcc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// type Entry is an identifier and the class number range it has been assigned.
+type Entry struct {
+	// ID is the identifier associated with the range, for example "sh85029534".
+	ID string `json:"id"`
+	// Range is the class number range associated with 'ID'.
+	Range *Range `json:"range"`
+}
+
+// type Index is an index of identifiers (for example subject headings) by Library of Congress Classification
+// number range.
+type Index struct {
+	mu      *sync.RWMutex
+	entries []*Entry
+	sorted  bool
+}
+
+// NewIndex() returns a new (empty) `Index` instance.
+func NewIndex() *Index {
+
+	idx := &Index{
+		mu:      new(sync.RWMutex),
+		entries: make([]*Entry, 0),
+		sorted:  true,
+	}
+
+	return idx
+}
+
+// Add() parses 'code', a class number or range of class numbers, and adds it to 'idx' associated with 'id'.
+func (idx *Index) Add(id string, code string) error {
+
+	r, err := ParseRange(code)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse '%s' for %s, %w", code, id, err)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = append(idx.entries, &Entry{
+		ID:    id,
+		Range: r,
+	})
+
+	idx.sorted = false
+	return nil
+}
+
+// Len() returns the number of entries in 'idx'.
+func (idx *Index) Len() int {
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.entries)
+}
+
+// Within() returns the list of entries, in shelf order, whose ranges fall entirely within 'code' (a class number
+// or range of class numbers, for example "QA75-QA76.95").
+func (idx *Index) Within(code string) ([]*Entry, error) {
+
+	return idx.query(code, func(e *Entry, r *Range) bool {
+		return e.Range.Within(r)
+	})
+}
+
+// Overlapping() returns the list of entries, in shelf order, whose ranges overlap 'code' (a class number or range
+// of class numbers, for example "QA75-QA76.95").
+func (idx *Index) Overlapping(code string) ([]*Entry, error) {
+
+	return idx.query(code, func(e *Entry, r *Range) bool {
+		return e.Range.Overlaps(r)
+	})
+}
+
+// query() returns the list of entries, in shelf order, that start before the end of 'code' and satisfy 'match'.
+func (idx *Index) query(code string, match func(*Entry, *Range) bool) ([]*Entry, error) {
+
+	r, err := ParseRange(code)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse '%s', %w", code, err)
+	}
+
+	idx.sort()
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	results := make([]*Entry, 0)
+
+	for _, e := range idx.entries {
+
+		// Entries are sorted by the start of their range so there is nothing left to match
+		// once an entry starts after the end of 'r'.
+
+		if !beforeEnd(e.Range.Start, r.End) {
+			break
+		}
+
+		if match(e, r) {
+			results = append(results, e)
+		}
+	}
+
+	return results, nil
+}
+
+// sort() sorts the entries in 'idx' by the start, and then the end, of their ranges if they are not already sorted.
+func (idx *Index) sort() {
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.sorted {
+		return
+	}
+
+	sort.SliceStable(idx.entries, func(i, j int) bool {
+
+		a := idx.entries[i].Range
+		b := idx.entries[j].Range
+
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+
+		return a.End < b.End
+	})
+
+	idx.sorted = true
+}
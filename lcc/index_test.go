@@ -0,0 +1,77 @@
+package lcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+
+	idx := NewIndex()
+
+	entries := map[string]string{
+		"sh85029534": "QA75-QA76.95",
+		"sh85042288": "QA76.9.D3",
+		"sh85016999": "TK7871.58.B74",
+		"sh85082139": "QA1-QA939",
+		"sh85133147": "QA76.76.T48",
+		"sh85148118": "QA76.95-QA77",
+	}
+
+	for id, code := range entries {
+
+		err := idx.Add(id, code)
+
+		if err != nil {
+			t.Fatalf("Failed to add %s, %v", id, err)
+		}
+	}
+
+	if idx.Len() != len(entries) {
+		t.Fatalf("Unexpected length: %d", idx.Len())
+	}
+
+	err := idx.Add("sh00000000", "not a class number")
+
+	if err == nil {
+		t.Fatalf("Expected invalid class number to fail")
+	}
+
+	within, err := idx.Within("QA75-QA76.95")
+
+	if err != nil {
+		t.Fatalf("Failed to query index, %v", err)
+	}
+
+	if ids(within) != "sh85029534,sh85133147,sh85042288" {
+		t.Fatalf("Unexpected results: %s", ids(within))
+	}
+
+	overlapping, err := idx.Overlapping("QA76.9")
+
+	if err != nil {
+		t.Fatalf("Failed to query index, %v", err)
+	}
+
+	if ids(overlapping) != "sh85082139,sh85029534,sh85042288" {
+		t.Fatalf("Unexpected results: %s", ids(overlapping))
+	}
+
+	_, err = idx.Within("QA76.95-QA75")
+
+	if err == nil {
+		t.Fatalf("Expected invalid range to fail")
+	}
+}
+
+// ids() returns a comma-separated list of the IDs in 'entries'.
+func ids(entries []*Entry) string {
+
+	l := make([]string, len(entries))
+
+	for i, e := range entries {
+		l[i] = e.ID
+	}
+
+	return strings.Join(l, ",")
+}
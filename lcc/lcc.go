@@ -0,0 +1,257 @@
+// Package lcc provides methods for parsing and normalizing Library of Congress Classification (LCC) numbers,
+// for example "QA76.9.D3", and ranges of class numbers, for example "QA75-QA76.95", so that they sort and
+// compare correctly.
+package lcc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// re_class_number matches the class letters, whole number and decimal extension of a class number, for example
+// "QA", "76" and ".95" in "QA76.95".
+var re_class_number = regexp.MustCompile(`^([A-Z]{1,3})\s*(\d+)?(\.\d+)?(.*)$`)
+
+// re_cutter matches a Cutter number, for example "D3" or "A1".
+var re_cutter = regexp.MustCompile(`^[A-Z]\d*$`)
+
+// type ClassNumber is a parsed Library of Congress Classification number.
+type ClassNumber struct {
+	// Raw is the unparsed class number, for example "QA76.9.D3 S45".
+	Raw string `json:"raw"`
+	// Class is the class letters, for example "QA".
+	Class string `json:"class"`
+	// Number is the whole number portion of the class number, for example "76". It is empty for class numbers
+	// that only consist of class letters, for example "QA".
+	Number string `json:"number,omitempty"`
+	// Decimal is the decimal extension of the class number, without a leading full stop, for example "9".
+	Decimal string `json:"decimal,omitempty"`
+	// Cutters is the list of Cutter numbers and any other trailing elements, for example "D3" and "S45".
+	Cutters []string `json:"cutters,omitempty"`
+}
+
+// Parse() parses 'str' in to a `ClassNumber` instance. An error is returned if 'str' does not start with between
+// one and three class letters.
+func Parse(str string) (*ClassNumber, error) {
+
+	raw := strings.TrimSpace(str)
+	str = strings.ToUpper(raw)
+
+	m := re_class_number.FindStringSubmatch(str)
+
+	if len(m) == 0 {
+		return nil, fmt.Errorf("Invalid class number '%s'", raw)
+	}
+
+	cn := &ClassNumber{
+		Raw:     raw,
+		Class:   m[1],
+		Number:  m[2],
+		Decimal: strings.TrimPrefix(m[3], "."),
+		Cutters: make([]string, 0),
+	}
+
+	rest := strings.FieldsFunc(m[4], func(r rune) bool {
+		return r == '.' || r == ' '
+	})
+
+	cn.Cutters = append(cn.Cutters, rest...)
+
+	// Class numbers like "QAB" have no whole number so anything else is a malformed class number.
+
+	if cn.Number == "" && len(cn.Cutters) > 0 {
+		return nil, fmt.Errorf("Invalid class number '%s'", raw)
+	}
+
+	return cn, nil
+}
+
+// Normalize() parses 'str' and returns its normalized form. Normalized class numbers sort and compare (as strings)
+// in Library of Congress shelf order.
+func Normalize(str string) (string, error) {
+
+	cn, err := Parse(str)
+
+	if err != nil {
+		return "", err
+	}
+
+	return cn.Normalize(), nil
+}
+
+// Normalize() returns the normalized form of 'cn'. Class letters are padded to three characters, whole numbers are
+// zero-padded to four digits and Cutter numbers are separated by a single space. For example "QA76.9.D3" becomes
+// "QA 0076.9 D3". Normalized class numbers sort and compare (as strings) in Library of Congress shelf order and a
+// class number is always a prefix of the class numbers it contains.
+func (cn *ClassNumber) Normalize() string {
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%-3s", cn.Class))
+
+	if cn.Number == "" {
+		return sb.String()
+	}
+
+	n, err := strconv.Atoi(cn.Number)
+
+	if err == nil {
+		sb.WriteString(fmt.Sprintf("%04d", n))
+	} else {
+		sb.WriteString(cn.Number)
+	}
+
+	if cn.Decimal != "" {
+		sb.WriteString(".")
+		sb.WriteString(cn.Decimal)
+	}
+
+	for _, c := range cn.Cutters {
+		sb.WriteString(" ")
+		sb.WriteString(c)
+	}
+
+	return sb.String()
+}
+
+// String() returns the raw (unparsed) class number.
+func (cn *ClassNumber) String() string {
+	return cn.Raw
+}
+
+// type Range is an (inclusive) range of Library of Congress Classification numbers.
+type Range struct {
+	// Raw is the unparsed range, for example "QA75-QA76.95".
+	Raw string `json:"raw"`
+	// Start is the normalized form of the first class number in the range.
+	Start string `json:"start"`
+	// End is the normalized form of the last class number in the range.
+	End string `json:"end"`
+}
+
+// ParseRange() parses 'str' in to a `Range` instance. 'str' may be a single class number (for example "QA76.9.D3")
+// or two class numbers separated by a hyphen (for example "QA75-QA76.95"). The end of a range may omit the class
+// letters of the start of the range (for example "QA75-76.95") or be a Cutter number replacing the final Cutter
+// number of the start of the range (for example "PN1993.5.A1-Z").
+func ParseRange(str string) (*Range, error) {
+
+	raw := strings.TrimSpace(str)
+
+	start_str, end_str, is_range := strings.Cut(raw, "-")
+
+	start, err := Parse(start_str)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse start of range '%s', %w", raw, err)
+	}
+
+	end := start
+
+	if is_range {
+
+		end_str = strings.ToUpper(strings.TrimSpace(end_str))
+
+		switch {
+		case end_str == "":
+			return nil, fmt.Errorf("Invalid range '%s'", raw)
+		case end_str[0] >= '0' && end_str[0] <= '9':
+			end_str = start.Class + end_str
+		case len(start.Cutters) > 0 && re_cutter.MatchString(strings.TrimPrefix(end_str, ".")):
+
+			cutters := make([]string, len(start.Cutters))
+			copy(cutters, start.Cutters)
+			cutters[len(cutters)-1] = strings.TrimPrefix(end_str, ".")
+
+			end = &ClassNumber{
+				Raw:     end_str,
+				Class:   start.Class,
+				Number:  start.Number,
+				Decimal: start.Decimal,
+				Cutters: cutters,
+			}
+		}
+
+		if end == start {
+
+			v, err := Parse(end_str)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse end of range '%s', %w", raw, err)
+			}
+
+			end = v
+		}
+	}
+
+	r := &Range{
+		Raw:   raw,
+		Start: start.Normalize(),
+		End:   end.Normalize(),
+	}
+
+	if r.End < r.Start {
+		return nil, fmt.Errorf("Invalid range '%s', end precedes start", raw)
+	}
+
+	return r, nil
+}
+
+// Contains() returns a boolean value indicating whether 'r' contains 'key', a normalized class number. The end
+// of a range is inclusive of any class numbers it contains, for example "QA76.95" contains "QA76.95.D3".
+func (r *Range) Contains(key string) bool {
+	return key >= r.Start && beforeEnd(key, r.End)
+}
+
+// Within() returns a boolean value indicating whether 'r' falls entirely within 'other'.
+func (r *Range) Within(other *Range) bool {
+	return other.Contains(r.Start) && other.Contains(r.End)
+}
+
+// Overlaps() returns a boolean value indicating whether any part of 'r' falls within 'other'.
+func (r *Range) Overlaps(other *Range) bool {
+	return beforeEnd(r.Start, other.End) && beforeEnd(other.Start, r.End)
+}
+
+// String() returns the raw (unparsed) range.
+func (r *Range) String() string {
+	return r.Raw
+}
+
+// beforeEnd() returns a boolean value indicating whether 'key' precedes, or is contained by, 'end'. A class number
+// contains the class numbers which extend it with a Cutter number (for example "QA76.9" contains "QA76.9.D3"), a
+// whole number contains its decimal extensions (for example "QA76" contains "QA76.5") and a Cutter number contains
+// the Cutter numbers which extend it (for example "PN1993.5.Z" contains "PN1993.5.Z8"). Decimal extensions do not
+// contain longer decimal extensions (for example "QA76.9" does not contain "QA76.95").
+func beforeEnd(key string, end string) bool {
+
+	if key <= end {
+		return true
+	}
+
+	if !strings.HasPrefix(key, end) {
+		return false
+	}
+
+	// Class letters only, for example "QA "
+
+	if len(end) <= 3 {
+		return true
+	}
+
+	// Cutter numbers are separated from the class number by a space
+
+	if strings.LastIndex(end, " ") > 3 {
+		return true
+	}
+
+	switch key[len(end)] {
+	case ' ':
+		return true
+	case '.':
+		return !strings.Contains(end, ".")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,225 @@
+package lcc
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+
+	tests := map[string]string{
+		"QA76.9.D3":     "QA 0076.9 D3",
+		"qa76.9 .d3":    "QA 0076.9 D3",
+		"TK7871.58.B74": "TK 7871.58 B74",
+		"QA76.9.D3 S45": "QA 0076.9 D3 S45",
+		"E184.A1":       "E  0184 A1",
+		"KF4558 15th":   "KF 4558 15TH",
+		"QA":            "QA ",
+	}
+
+	for str, expected := range tests {
+
+		v, err := Normalize(str)
+
+		if err != nil {
+			t.Fatalf("Failed to normalize '%s', %v", str, err)
+		}
+
+		if v != expected {
+			t.Fatalf("Unexpected normalization for '%s': '%s' (expected '%s')", str, v, expected)
+		}
+	}
+
+	for _, str := range []string{"", "76.9", "QABC76", "QA.D3"} {
+
+		_, err := Normalize(str)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", str)
+		}
+	}
+}
+
+func TestNormalizeSort(t *testing.T) {
+
+	// Class numbers in shelf order
+
+	expected := []string{
+		"Q1",
+		"QA9",
+		"QA75",
+		"QA76",
+		"QA76.D3",
+		"QA76.5",
+		"QA76.9",
+		"QA76.9.D3",
+		"QA76.9.D35",
+		"QA76.95",
+		"QA760",
+		"QB1",
+	}
+
+	keys := make([]string, len(expected))
+	lookup := make(map[string]string)
+
+	for i, str := range expected {
+
+		k, err := Normalize(str)
+
+		if err != nil {
+			t.Fatalf("Failed to normalize '%s', %v", str, err)
+		}
+
+		keys[len(expected)-1-i] = k
+		lookup[k] = str
+	}
+
+	sort.Strings(keys)
+
+	for i, k := range keys {
+
+		if lookup[k] != expected[i] {
+			t.Fatalf("Unexpected sort order at position %d: %s (expected %s)", i, lookup[k], expected[i])
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+
+	tests := map[string][2]string{
+		"QA75-QA76.95":  {"QA 0075", "QA 0076.95"},
+		"QA75-76.95":    {"QA 0075", "QA 0076.95"},
+		"QA76.9.D3":     {"QA 0076.9 D3", "QA 0076.9 D3"},
+		"PN1993.5.A1-Z": {"PN 1993.5 A1", "PN 1993.5 Z"},
+		"Z699-Z699.5":   {"Z  0699", "Z  0699.5"},
+	}
+
+	for str, expected := range tests {
+
+		r, err := ParseRange(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if r.Start != expected[0] || r.End != expected[1] {
+			t.Fatalf("Unexpected range for '%s': '%s' - '%s'", str, r.Start, r.End)
+		}
+	}
+
+	for _, str := range []string{"QA76-", "QA76.95-QA75", "-QA75"} {
+
+		_, err := ParseRange(str)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", str)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+
+	r, err := ParseRange("QA75-QA76.95")
+
+	if err != nil {
+		t.Fatalf("Failed to parse range, %v", err)
+	}
+
+	tests := map[string]bool{
+		"QA74.9":       false,
+		"QA75":         true,
+		"QA76.9.D3":    true,
+		"QA76.95":      true,
+		"QA76.95.D3":   true,
+		"QA76.96":      false,
+		"QA760":        false,
+		"QB75":         false,
+		"QA75.5-QA76":  true,
+		"QA70-QA75.5":  false,
+		"QA76.9-QA77":  false,
+		"QA76.95.A1-Z": true,
+	}
+
+	for str, expected := range tests {
+
+		other, err := ParseRange(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if other.Within(r) != expected {
+			t.Fatalf("Unexpected result for '%s' within '%s': %t", str, r, !expected)
+		}
+	}
+
+	overlaps := []string{"QA70-QA75.5", "QA76.9-QA77", "QA1-QA999"}
+
+	for _, str := range overlaps {
+
+		other, err := ParseRange(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if !other.Overlaps(r) || !r.Overlaps(other) {
+			t.Fatalf("Expected '%s' to overlap '%s'", str, r)
+		}
+	}
+
+	tests_contains := map[string]map[string]bool{
+		"QA75-QA76": {
+			"QA76.5":    true,
+			"QA76.D3":   true,
+			"QA77":      false,
+			"QA760":     false,
+			"QA74.9.A1": false,
+		},
+		"QA": {
+			"QA1":     true,
+			"QA939":   true,
+			"QB1":     false,
+			"Q300":    false,
+			"QA76.D3": true,
+		},
+		"PN1993.5.A1-Z": {
+			"PN1993.5.U6": true,
+			"PN1993.5.Z8": true,
+			"PN1993.6":    false,
+		},
+	}
+
+	for range_str, keys := range tests_contains {
+
+		r, err := ParseRange(range_str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", range_str, err)
+		}
+
+		for str, expected := range keys {
+
+			k, err := Normalize(str)
+
+			if err != nil {
+				t.Fatalf("Failed to normalize '%s', %v", str, err)
+			}
+
+			if r.Contains(k) != expected {
+				t.Fatalf("Unexpected result for '%s' contains '%s': %t", range_str, str, !expected)
+			}
+		}
+	}
+
+	other, _ := ParseRange("QA77-QA78")
+
+	if other.Overlaps(r) || r.Overlaps(other) {
+		t.Fatalf("Did not expect '%s' to overlap '%s'", other, r)
+	}
+
+	if !strings.HasPrefix(r.End, "QA") {
+		t.Fatalf("Unexpected end: %s", r.End)
+	}
+}
@@ -0,0 +1,366 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ISO 2709 structural characters.
+const (
+	// SubfieldDelimiter is the character that precedes each subfield code.
+	SubfieldDelimiter byte = 0x1F
+	// FieldTerminator is the character that terminates each field (and the directory).
+	FieldTerminator byte = 0x1E
+	// RecordTerminator is the character that terminates each record.
+	RecordTerminator byte = 0x1D
+)
+
+// ISO 2709 record layout.
+const (
+	// leaderLength is the length of the record leader.
+	leaderLength int = 24
+	// directoryEntryLength is the length of each entry in the record directory.
+	directoryEntryLength int = 12
+	// maxRecordLength is the maximum length of a record, as encoded in leader positions 00-04.
+	maxRecordLength int = 99999
+)
+
+// Character coding schemes (leader position 09).
+const (
+	// EncodingMARC8 indicates that a record is encoded as MARC-8.
+	EncodingMARC8 byte = ' '
+	// EncodingUTF8 indicates that a record is encoded as UTF-8 (UCS/Unicode).
+	EncodingUTF8 byte = 'a'
+)
+
+// type ISO2709Decoder reads MARC 21 records from a binary (ISO 2709) MARC file, for example a `.mrc` file.
+type ISO2709Decoder struct {
+	reader *bufio.Reader
+	offset int64
+}
+
+// NewISO2709Decoder() returns a new `ISO2709Decoder` instance for reading records from 'r'.
+func NewISO2709Decoder(r io.Reader) *ISO2709Decoder {
+
+	d := &ISO2709Decoder{
+		reader: bufio.NewReader(r),
+	}
+
+	return d
+}
+
+// Next() returns the next record in the file. It returns `io.EOF` when there are no more records. Whitespace
+// (for example line breaks) between records is ignored.
+func (d *ISO2709Decoder) Next() (*Record, error) {
+
+	for {
+
+		b, err := d.reader.Peek(1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if b[0] != ' ' && b[0] != '\n' && b[0] != '\r' && b[0] != '\t' {
+			break
+		}
+
+		d.reader.Discard(1)
+		d.offset += 1
+	}
+
+	head, err := d.reader.Peek(5)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read record length at offset %d, %w", d.offset, unexpectedEOF(err))
+	}
+
+	length, err := parseNumber(head)
+
+	if err != nil || length < leaderLength+1 {
+		return nil, fmt.Errorf("Invalid record length '%s' at offset %d", head, d.offset)
+	}
+
+	data := make([]byte, length)
+
+	_, err = io.ReadFull(d.reader, data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read record at offset %d, %w", d.offset, unexpectedEOF(err))
+	}
+
+	// Advance the offset before unmarshaling the record so that a malformed record does not prevent
+	// subsequent records from being read
+
+	offset := d.offset
+	d.offset += int64(length)
+
+	r, err := UnmarshalISO2709(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal record at offset %d, %w", offset, err)
+	}
+
+	return r, nil
+}
+
+// InputOffset() returns the byte offset of the current position of the decoder in the underlying file.
+func (d *ISO2709Decoder) InputOffset() int64 {
+	return d.offset
+}
+
+// UnmarshalISO2709() decodes 'data', a single ISO 2709 encoded record, in to a `Record` instance. Values are decoded
+// as UTF-8 or MARC-8 depending on the character coding scheme (position 09) of the leader; in either case the values
+// in the returned record are UTF-8 strings. The leader is returned as-is so that the original encoding is preserved.
+func UnmarshalISO2709(data []byte) (*Record, error) {
+
+	if len(data) < leaderLength+1 {
+		return nil, fmt.Errorf("Record is too short")
+	}
+
+	leader := data[0:leaderLength]
+
+	base, err := parseNumber(leader[12:17])
+
+	if err != nil || base <= leaderLength || base > len(data) {
+		return nil, fmt.Errorf("Invalid base address of data '%s'", leader[12:17])
+	}
+
+	decode := decodeUTF8
+
+	if leader[9] != EncodingUTF8 {
+		decode = DecodeMARC8
+	}
+
+	r := &Record{
+		Leader:        string(leader),
+		ControlFields: make([]*ControlField, 0),
+		DataFields:    make([]*DataField, 0),
+	}
+
+	directory := data[leaderLength : base-1]
+
+	if len(directory)%directoryEntryLength != 0 {
+		return nil, fmt.Errorf("Invalid directory length (%d)", len(directory))
+	}
+
+	for i := 0; i < len(directory); i += directoryEntryLength {
+
+		entry := directory[i : i+directoryEntryLength]
+
+		tag := string(entry[0:3])
+
+		field_length, err := parseNumber(entry[3:7])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid length for field %s, %w", tag, err)
+		}
+
+		start, err := parseNumber(entry[7:12])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid starting position for field %s, %w", tag, err)
+		}
+
+		if base+start < base || base+start+field_length > len(data) {
+			return nil, fmt.Errorf("Field %s extends beyond the end of the record", tag)
+		}
+
+		field := bytes.TrimSuffix(data[base+start:base+start+field_length], []byte{FieldTerminator})
+
+		if isControlTag(tag) {
+
+			r.ControlFields = append(r.ControlFields, &ControlField{
+				Tag:   tag,
+				Value: decode(field),
+			})
+
+			continue
+		}
+
+		if len(field) < 2 {
+			return nil, fmt.Errorf("Field %s is missing indicators", tag)
+		}
+
+		f := &DataField{
+			Tag:       tag,
+			Ind1:      string(field[0:1]),
+			Ind2:      string(field[1:2]),
+			Subfields: make([]*Subfield, 0),
+		}
+
+		for _, sf := range bytes.Split(field[2:], []byte{SubfieldDelimiter})[1:] {
+
+			if len(sf) == 0 {
+				continue
+			}
+
+			f.Subfields = append(f.Subfields, &Subfield{
+				Code:  string(sf[0:1]),
+				Value: decode(sf[1:]),
+			})
+		}
+
+		r.DataFields = append(r.DataFields, f)
+	}
+
+	return r, nil
+}
+
+// MarshalISO2709() encodes 'r' as an ISO 2709 record. Values are encoded as UTF-8 or MARC-8 depending on the character
+// coding scheme (position 09) of the leader of 'r'. The record length, indicator count, subfield code count, base
+// address of data and entry map (positions 00-04, 10-17 and 20-23) of the leader are derived from the encoded record;
+// all other positions are copied from the leader of 'r'.
+func MarshalISO2709(r *Record) ([]byte, error) {
+
+	leader := []byte(fmt.Sprintf("%-24s", r.Leader))[0:leaderLength]
+
+	encode := encodeUTF8
+
+	if leader[9] != EncodingUTF8 {
+		encode = EncodeMARC8
+	}
+
+	var directory bytes.Buffer
+	var fields bytes.Buffer
+
+	add := func(tag string, field []byte) error {
+
+		if len(tag) != 3 {
+			return fmt.Errorf("Invalid tag '%s'", tag)
+		}
+
+		field = append(field, FieldTerminator)
+
+		if len(field) > 9999 {
+			return fmt.Errorf("Field %s is too long (%d bytes)", tag, len(field))
+		}
+
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, len(field), fields.Len())
+		fields.Write(field)
+
+		return nil
+	}
+
+	for _, f := range r.ControlFields {
+
+		value, err := encode(f.Value)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to encode field %s, %w", f.Tag, err)
+		}
+
+		err = add(f.Tag, value)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range r.DataFields {
+
+		field := []byte{indicator(f.Ind1), indicator(f.Ind2)}
+
+		for _, sf := range f.Subfields {
+
+			if len(sf.Code) != 1 {
+				return nil, fmt.Errorf("Invalid subfield code '%s' in field %s", sf.Code, f.Tag)
+			}
+
+			value, err := encode(sf.Value)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to encode subfield %s in field %s, %w", sf.Code, f.Tag, err)
+			}
+
+			field = append(field, SubfieldDelimiter, sf.Code[0])
+			field = append(field, value...)
+		}
+
+		err := add(f.Tag, field)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	directory.WriteByte(FieldTerminator)
+
+	base := leaderLength + directory.Len()
+	length := base + fields.Len() + 1
+
+	if length > maxRecordLength {
+		return nil, fmt.Errorf("Record is too long (%d bytes)", length)
+	}
+
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[10:17], fmt.Sprintf("22%05d", base))
+	copy(leader[20:24], "4500")
+
+	var buf bytes.Buffer
+	buf.Grow(length)
+
+	buf.Write(leader)
+	buf.Write(directory.Bytes())
+	buf.Write(fields.Bytes())
+	buf.WriteByte(RecordTerminator)
+
+	return buf.Bytes(), nil
+}
+
+// parseNumber() returns the value of 'b', a fixed-length ISO 2709 number. Unlike `strconv.Atoi` signs, and any other
+// characters which are not ASCII digits, are rejected so that the value is never negative.
+func parseNumber(b []byte) (int, error) {
+
+	if len(b) == 0 {
+		return 0, fmt.Errorf("Invalid number ''")
+	}
+
+	for _, c := range b {
+
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("Invalid number '%s'", b)
+		}
+	}
+
+	return strconv.Atoi(string(b))
+}
+
+// isControlTag() returns a boolean value indicating whether 'tag' is a control field (001-009) tag.
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// indicator() returns the first byte of 'ind' or a blank (space) if 'ind' is empty.
+func indicator(ind string) byte {
+
+	if ind == "" {
+		return ' '
+	}
+
+	return ind[0]
+}
+
+// decodeUTF8() returns 'b' as a string replacing any invalid UTF-8 sequences with U+FFFD.
+func decodeUTF8(b []byte) string {
+	return strings.ToValidUTF8(string(b), string(replacementCharacter))
+}
+
+// encodeUTF8() returns 's' as a byte slice.
+func encodeUTF8(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+// unexpectedEOF() returns `io.ErrUnexpectedEOF` if 'err' is `io.EOF`, otherwise 'err' is returned.
+func unexpectedEOF(err error) error {
+
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
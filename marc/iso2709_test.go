@@ -0,0 +1,227 @@
+package marc
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readISO2709Fixture(t *testing.T, path string) ([]byte, []*Record) {
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", path, err)
+	}
+
+	dec := NewISO2709Decoder(bytes.NewReader(data))
+	records := make([]*Record, 0)
+
+	for {
+
+		r, err := dec.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to decode record in %s, %v", path, err)
+		}
+
+		records = append(records, r)
+	}
+
+	if dec.InputOffset() != int64(len(data)) {
+		t.Fatalf("Unexpected offset for %s: %d (expected %d)", path, dec.InputOffset(), len(data))
+	}
+
+	return data, records
+}
+
+func TestISO2709RoundTrip(t *testing.T) {
+
+	tests := map[string]int{
+		"../fixtures/authorities.sample.mrc":       5,
+		"../fixtures/authorities.marc8.sample.mrc": 3,
+	}
+
+	for path, expected_count := range tests {
+
+		data, records := readISO2709Fixture(t, path)
+
+		if len(records) != expected_count {
+			t.Fatalf("Unexpected number of records in %s: %d", path, len(records))
+		}
+
+		var buf bytes.Buffer
+
+		for _, r := range records {
+
+			enc, err := MarshalISO2709(r)
+
+			if err != nil {
+				t.Fatalf("Failed to marshal record in %s, %v", path, err)
+			}
+
+			buf.Write(enc)
+		}
+
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Round-tripped records in %s do not match the original", path)
+		}
+	}
+}
+
+func TestISO2709MARCXML(t *testing.T) {
+
+	_, records := readISO2709Fixture(t, "../fixtures/authorities.sample.mrc")
+
+	fh, err := os.Open("../fixtures/authorities.sample.marcxml")
+
+	if err != nil {
+		t.Fatalf("Failed to open fixture, %v", err)
+	}
+
+	defer fh.Close()
+
+	dec := NewXMLDecoder(fh)
+
+	for i, r := range records {
+
+		xml_r, err := dec.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to decode MARCXML record, %v", err)
+		}
+
+		// The record lengths and base addresses in the MARCXML fixture are not meaningful so only compare
+		// the record status, type and character coding scheme
+
+		if r.Leader[5:10] != xml_r.Leader[5:10] || r.Leader[9] != EncodingUTF8 {
+			t.Fatalf("Unexpected leader for record %d: '%s'", i, r.Leader)
+		}
+
+		if len(r.ControlFields) != len(xml_r.ControlFields) || len(r.DataFields) != len(xml_r.DataFields) {
+			t.Fatalf("Unexpected number of fields for record %d", i)
+		}
+
+		for j, f := range r.DataFields {
+
+			xml_f := xml_r.DataFields[j]
+
+			if f.Tag != xml_f.Tag || f.Ind1 != xml_f.Ind1 || f.Ind2 != xml_f.Ind2 || len(f.Subfields) != len(xml_f.Subfields) {
+				t.Fatalf("Unexpected field %s for record %d", f.Tag, i)
+			}
+
+			for k, sf := range f.Subfields {
+
+				if sf.Code != xml_f.Subfields[k].Code || sf.Value != xml_f.Subfields[k].Value {
+					t.Fatalf("Unexpected subfield %s in field %s for record %d: '%s'", sf.Code, f.Tag, i, sf.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestISO2709MARC8(t *testing.T) {
+
+	_, records := readISO2709Fixture(t, "../fixtures/authorities.marc8.sample.mrc")
+
+	r := records[0]
+
+	if r.Leader[9] != EncodingMARC8 {
+		t.Fatalf("Unexpected character coding scheme: '%c'", r.Leader[9])
+	}
+
+	heading := r.FieldsWithTag("100")[0]
+
+	if strings.Join(heading.SubfieldValues("a"), "") != "Dvořák, Antonín," {
+		t.Fatalf("Unexpected heading: %s", heading.Subfield("a"))
+	}
+
+	variant := records[2].FieldsWithTag("450")[1]
+
+	if variant.Subfield("a") != "D₂O" {
+		t.Fatalf("Unexpected variant: %s", variant.Subfield("a"))
+	}
+}
+
+func TestISO2709Invalid(t *testing.T) {
+
+	dec := NewISO2709Decoder(strings.NewReader("00050cz  a2200037n  4500"))
+
+	_, err := dec.Next()
+
+	if err == nil || err == io.EOF {
+		t.Fatalf("Expected error for truncated record, got %v", err)
+	}
+
+	_, err = UnmarshalISO2709([]byte("00030cz  a22abcden  4500xxxxxx"))
+
+	if err == nil {
+		t.Fatalf("Expected error for invalid base address")
+	}
+
+	// Directory entries with signed (or otherwise non-numeric) lengths or starting positions
+
+	valid, err := MarshalISO2709(&Record{
+		Leader: "00000cz  a2200000n  4500",
+		DataFields: []*DataField{
+			{Tag: "100", Ind1: "1", Subfields: []*Subfield{{Code: "a", Value: "Smith, John"}}},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to encode record, %v", err)
+	}
+
+	for _, entry := range []string{"100-99900000", "1000005-9999", "100+01800000", "1000018 0000", "1000018+0000"} {
+
+		data := append([]byte{}, valid...)
+		copy(data[leaderLength:leaderLength+directoryEntryLength], entry)
+
+		_, err = UnmarshalISO2709(data)
+
+		if err == nil {
+			t.Fatalf("Expected error for directory entry '%s'", entry)
+		}
+
+		// The decoder reports the error and moves on to the next record
+
+		dec := NewISO2709Decoder(bytes.NewReader(append(data, valid...)))
+
+		_, err = dec.Next()
+
+		if err == nil || err == io.EOF {
+			t.Fatalf("Expected error decoding directory entry '%s', got %v", entry, err)
+		}
+
+		_, err = dec.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to decode record following directory entry '%s', %v", entry, err)
+		}
+	}
+
+	_, err = NewISO2709Decoder(strings.NewReader("+0030cz  a2200025n  4500\x1exxxx\x1d")).Next()
+
+	if err == nil || err == io.EOF {
+		t.Fatalf("Expected error for signed record length, got %v", err)
+	}
+
+	r := &Record{
+		Leader: "00000cz   2200000n  4500",
+		DataFields: []*DataField{
+			{Tag: "100", Subfields: []*Subfield{{Code: "a", Value: "Москва"}}},
+		},
+	}
+
+	_, err = MarshalISO2709(r)
+
+	if err == nil {
+		t.Fatalf("Expected error encoding Cyrillic characters as MARC-8")
+	}
+}
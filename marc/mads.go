@@ -0,0 +1,938 @@
+package marc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/dataset"
+)
+
+// type MADSOptions defines options for converting MARC 21 authority records in to MADS/RDF.
+type MADSOptions struct {
+	// BaseURI is an optional URI prefix used to derive the URI of records which do not have a Library of Congress
+	// Control Number (010 $a) belonging to a known `dataset.Dataset` from their control number (001).
+	BaseURI string
+}
+
+// headingTypes maps the final two characters of heading (1XX), see from (4XX) and see also (5XX) tags to
+// their MADS/RDF authority class.
+var headingTypes = map[string]string{
+	"00": "PersonalName",
+	"10": "CorporateName",
+	"11": "ConferenceName",
+	"30": "Title",
+	"48": "Temporal",
+	"50": "Topic",
+	"51": "Geographic",
+	"55": "GenreForm",
+	"62": "Topic",
+	"80": "Topic",
+	"81": "Geographic",
+	"82": "Temporal",
+	"85": "GenreForm",
+}
+
+// subdivisionTypes maps subdivision subfield codes to their MADS/RDF authority class.
+var subdivisionTypes = map[string]string{
+	"v": "GenreForm",
+	"x": "Topic",
+	"y": "Temporal",
+	"z": "Geographic",
+}
+
+// subdivisionCollections maps the tags of (free-floating) subdivision records to the LCSH collection they are a member of.
+var subdivisionCollections = map[string]string{
+	"180": "collection_GeneralSubdivisions",
+	"181": "collection_GeographicSubdivisions",
+	"182": "collection_ChronologicalSubdivisions",
+	"185": "collection_FormSubdivisions",
+}
+
+// externalSources maps the source codes (024 $2) of standard identifiers to the URI prefix for that source.
+var externalSources = map[string]string{
+	"wikidata": "http://www.wikidata.org/entity/",
+	"viaf":     "http://viaf.org/viaf/",
+	"isni":     "http://isni.org/isni/",
+	"gnd":      "http://d-nb.info/gnd/",
+	"orcid":    "https://orcid.org/",
+	"fast":     "http://id.worldcat.org/fast/",
+}
+
+// recordStatus maps the record status (leader position 05) of a MARC 21 authority record to an `ri:recordStatus` value.
+var recordStatus = map[string]string{
+	"n": "new",
+	"a": "revised",
+	"c": "revised",
+	"d": "deprecated",
+	"s": "deprecated",
+	"x": "deprecated",
+}
+
+// type heading is an internal structure describing a heading (1XX), see from (4XX) or see also (5XX) field.
+type heading struct {
+	// tag is the tag of the field, or the tag being linked to for alternate graphic representation (880) fields.
+	tag string
+	// madsType is the MADS/RDF authority class of the heading, without the "madsrdf:" prefix.
+	madsType string
+	// main is the list of subfields that make up the main (non-subdivision) part of the heading.
+	main []*Subfield
+	// subdivisions is the list of subdivision ($v, $x, $y and $z) subfields, in order.
+	subdivisions []*Subfield
+}
+
+// type madsGraph is an internal structure used to accumulate the nodes in a JSON-LD `@graph`.
+type madsGraph struct {
+	nodes []map[string]interface{}
+	count int
+}
+
+// ToMADS() converts 'r', a MARC 21 authority record, in to a JSON-LD document with a `@graph` property using the same
+// MADS/RDF vocabulary as the id.loc.gov `*.both.ndjson` files so that it can be processed by `authority.ParseRecord`.
+// The following fields are mapped:
+// * 1XX headings to `madsrdf:authoritativeLabel`, `madsrdf:elementList` and (for headings with subdivisions) `madsrdf:componentList`.
+// * 4XX (and linked 880) see from tracings to `madsrdf:hasVariant`.
+// * 5XX see also tracings to `madsrdf:hasBroaderAuthority` ($w/0 "g"), `madsrdf:hasNarrowerAuthority` ($w/0 "h") or `madsrdf:hasReciprocalAuthority`.
+// * 024 standard identifiers to `madsrdf:hasExactExternalAuthority`.
+// * 053 LC classification numbers to `madsrdf:classification`.
+// * 670 sources to `madsrdf:hasSource`.
+// * 680 public notes to `skos:scopeNote`.
+// * 682 deleted heading information ($0) to `madsrdf:useInstead`.
+// * 005, 008 and 040 to `ri:RecordInfo` administrative metadata.
+//
+// MARC 21 does not record the language of individual headings so labels are not language-tagged.
+func ToMADS(ctx context.Context, r *Record, opts *MADSOptions) ([]byte, error) {
+
+	if opts == nil {
+		opts = &MADSOptions{}
+	}
+
+	uri, ds := recordURI(ctx, r, opts)
+
+	if uri == "" {
+		return nil, fmt.Errorf("Unable to determine URI for record")
+	}
+
+	fields := r.Fields(func(tag string) bool {
+		return strings.HasPrefix(tag, "1")
+	})
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("Record %s is missing a heading (1XX) field", uri)
+	}
+
+	h, ok := newHeading(fields[0].Tag, fields[0])
+
+	if !ok {
+		return nil, fmt.Errorf("Record %s has an unsupported heading field (%s)", uri, fields[0].Tag)
+	}
+
+	g := &madsGraph{
+		nodes: make([]map[string]interface{}, 0),
+	}
+
+	n := map[string]interface{}{
+		"@id": uri,
+	}
+
+	g.nodes = append(g.nodes, n)
+
+	status := recordStatus[r.Status()]
+	label := h.label()
+
+	node_types := []string{
+		"madsrdf:" + h.nodeType(),
+		"madsrdf:Authority",
+	}
+
+	if status == "deprecated" {
+		node_types = []string{
+			"madsrdf:" + h.nodeType(),
+			"madsrdf:DeprecatedAuthority",
+		}
+		n["madsrdf:variantLabel"] = label
+	} else {
+		n["madsrdf:authoritativeLabel"] = label
+	}
+
+	n["@type"] = node_types
+
+	g.addHeading(n, h)
+
+	lccn := lccn(r)
+
+	if lccn != "" {
+		n["identifiers:lccn"] = lccn
+	}
+
+	if ds != nil && ds.Name == "lcsh" {
+
+		collection, ok := subdivisionCollections[h.tag]
+
+		if ok {
+			n["madsrdf:isMemberOfMADSCollection"] = []map[string]string{
+				ref(ds.URI("collection_Subdivisions")),
+				ref(ds.URI(collection)),
+			}
+		}
+	}
+
+	g.addVariants(n, r)
+	g.addRelations(ctx, n, r, opts)
+	g.addExternalAuthorities(n, r)
+	g.addClassifications(n, r)
+	g.addSources(n, r)
+	g.addNotes(ctx, n, r, opts)
+	g.addRecordInfo(n, r, status)
+
+	doc := map[string]interface{}{
+		"@context": map[string]string{
+			"about": uri,
+		},
+		"@graph": g.nodes,
+	}
+
+	enc, err := json.Marshal(doc)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal record %s, %w", uri, err)
+	}
+
+	return enc, nil
+}
+
+// recordURI() returns the URI for 'r' and the `dataset.Dataset` it belongs to, if known. URIs are derived from
+// the LCCN (010 $a) of 'r' or from its control number (001) and 'opts.BaseURI'.
+func recordURI(ctx context.Context, r *Record, opts *MADSOptions) (string, *dataset.Dataset) {
+
+	lccn := lccn(r)
+
+	if lccn != "" {
+
+		ds, ok := dataset.DatasetForLCCN(ctx, lccn)
+
+		if ok {
+			return ds.URI(lccn), ds
+		}
+	}
+
+	id := r.ControlField("001")
+
+	if id == "" {
+		id = lccn
+	}
+
+	if id == "" {
+		return "", nil
+	}
+
+	id = dataset.NormalizeLCCN(id)
+
+	if opts.BaseURI == "" {
+		return id, nil
+	}
+
+	uri := opts.BaseURI + id
+	ds, _ := dataset.DatasetForURI(ctx, uri)
+
+	return uri, ds
+}
+
+// lccn() returns the normalized Library of Congress Control Number (010 $a) for 'r' or an empty string.
+func lccn(r *Record) string {
+
+	for _, f := range r.FieldsWithTag("010") {
+
+		v := f.Subfield("a")
+
+		if v != "" {
+			return dataset.NormalizeLCCN(v)
+		}
+	}
+
+	return ""
+}
+
+// newHeading() returns a new `heading` instance derived from 'f' (whose effective tag is 'tag') and a boolean value
+// indicating whether 'f' is a supported heading.
+func newHeading(tag string, f *DataField) (*heading, bool) {
+
+	if len(tag) != 3 {
+		return nil, false
+	}
+
+	t, ok := headingTypes[tag[1:]]
+
+	if !ok {
+		return nil, false
+	}
+
+	h := &heading{
+		tag:          tag,
+		madsType:     t,
+		main:         make([]*Subfield, 0),
+		subdivisions: make([]*Subfield, 0),
+	}
+
+	// Subdivision records (18X) consist entirely of subdivisions
+
+	is_subdivision := tag[1] == '8'
+
+	for _, sf := range f.Subfields {
+
+		if strings.TrimSpace(sf.Value) == "" || !isHeadingSubfield(sf.Code) {
+			continue
+		}
+
+		_, ok := subdivisionTypes[sf.Code]
+
+		switch {
+		case ok:
+			h.subdivisions = append(h.subdivisions, sf)
+		case is_subdivision:
+			// pass
+		default:
+			h.main = append(h.main, sf)
+
+			if sf.Code == "t" && (t == "PersonalName" || t == "CorporateName" || t == "ConferenceName") {
+				h.madsType = "NameTitle"
+			}
+		}
+	}
+
+	if t == "PersonalName" && f.Ind1 == "3" && h.madsType == "PersonalName" {
+		h.madsType = "FamilyName"
+	}
+
+	if len(h.main) == 0 && len(h.subdivisions) == 0 {
+		return nil, false
+	}
+
+	return h, true
+}
+
+// isHeadingSubfield() returns a boolean value indicating whether 'code' is a subfield that forms part of a heading.
+// Control subfields (numeric codes as well as $i and $w) are excluded.
+func isHeadingSubfield(code string) bool {
+
+	if len(code) != 1 {
+		return false
+	}
+
+	c := code[0]
+
+	if c < 'a' || c > 'z' {
+		return false
+	}
+
+	return c != 'i' && c != 'w'
+}
+
+// label() returns the label for 'h'. Subdivisions are appended to the main heading separated by "--", for example
+// "Anarchism--Italy--History--20th century".
+func (h *heading) label() string {
+
+	parts := make([]string, 0)
+
+	if len(h.main) > 0 {
+		parts = append(parts, subfieldsLabel(h.main))
+	}
+
+	for _, sf := range h.subdivisions {
+		parts = append(parts, strings.TrimSpace(sf.Value))
+	}
+
+	return strings.Join(parts, "--")
+}
+
+// isComplex() returns a boolean value indicating whether 'h' is a pre-coordinated heading with more than one component.
+func (h *heading) isComplex() bool {
+
+	count := len(h.subdivisions)
+
+	if len(h.main) > 0 {
+		count += 1
+	}
+
+	return count > 1
+}
+
+// nodeType() returns the MADS/RDF authority class for 'h'. Headings with more than one component are "ComplexSubject".
+func (h *heading) nodeType() string {
+
+	if h.isComplex() {
+		return "ComplexSubject"
+	}
+
+	return h.madsType
+}
+
+// subfieldsLabel() returns the (trimmed) values of 'subfields' joined by a space.
+func subfieldsLabel(subfields []*Subfield) string {
+
+	values := make([]string, len(subfields))
+
+	for i, sf := range subfields {
+		values[i] = strings.TrimSpace(sf.Value)
+	}
+
+	return strings.Join(values, " ")
+}
+
+// blank() assigns a new blank node identifier to 'n', adds it to 'g' and returns a reference to it.
+func (g *madsGraph) blank(n map[string]interface{}) map[string]string {
+
+	g.count += 1
+	id := fmt.Sprintf("_:b%d", g.count)
+
+	n["@id"] = id
+	g.nodes = append(g.nodes, n)
+
+	return ref(id)
+}
+
+// addHeading() assigns the element list and, for pre-coordinated headings, the component list for 'h' to 'n'.
+func (g *madsGraph) addHeading(n map[string]interface{}, h *heading) {
+
+	if !h.isComplex() {
+
+		if len(h.main) > 0 {
+			n["madsrdf:elementList"] = g.elements(h.madsType, h.main)
+		} else {
+			n["madsrdf:elementList"] = g.elements(subdivisionTypes[h.subdivisions[0].Code], h.subdivisions)
+		}
+
+		return
+	}
+
+	components := make([]map[string]string, 0)
+
+	if len(h.main) > 0 {
+
+		c := map[string]interface{}{
+			"@type":                      []string{"madsrdf:" + h.madsType, "madsrdf:Authority"},
+			"madsrdf:authoritativeLabel": subfieldsLabel(h.main),
+			"madsrdf:elementList":        g.elements(h.madsType, h.main),
+		}
+
+		components = append(components, g.blank(c))
+	}
+
+	for _, sf := range h.subdivisions {
+
+		t := subdivisionTypes[sf.Code]
+
+		c := map[string]interface{}{
+			"@type":                      []string{"madsrdf:" + t, "madsrdf:Authority"},
+			"madsrdf:authoritativeLabel": strings.TrimSpace(sf.Value),
+			"madsrdf:elementList":        g.elements(t, []*Subfield{sf}),
+		}
+
+		components = append(components, g.blank(c))
+	}
+
+	n["madsrdf:componentList"] = map[string]interface{}{
+		"@list": components,
+	}
+}
+
+// elements() adds the MADS/RDF elements for 'subfields', the subfields of a heading whose authority class is 't',
+// to 'g' and returns a JSON-LD list referencing them.
+func (g *madsGraph) elements(t string, subfields []*Subfield) map[string]interface{} {
+
+	type element struct {
+		Type  string
+		Value string
+	}
+
+	el := make([]*element, 0)
+
+	switch t {
+	case "PersonalName", "FamilyName", "CorporateName", "ConferenceName", "NameTitle", "Title":
+
+		in_title := t == "Title"
+
+		for _, sf := range subfields {
+
+			if sf.Code == "t" {
+				in_title = true
+			}
+
+			el = append(el, &element{
+				Type:  nameElementType(t, sf.Code, in_title),
+				Value: strings.TrimSpace(sf.Value),
+			})
+		}
+
+	default:
+
+		el = append(el, &element{
+			Type:  t + "Element",
+			Value: subfieldsLabel(subfields),
+		})
+	}
+
+	refs := make([]map[string]string, len(el))
+
+	for i, e := range el {
+
+		n := map[string]interface{}{
+			"@type":                "madsrdf:" + e.Type,
+			"madsrdf:elementValue": e.Value,
+		}
+
+		refs[i] = g.blank(n)
+	}
+
+	return map[string]interface{}{
+		"@list": refs,
+	}
+}
+
+// nameElementType() returns the MADS/RDF element class, without the "madsrdf:" prefix, for subfield 'code' of a
+// name or title heading whose authority class is 't'. 'in_title' indicates whether the subfield follows a title ($t)
+// subfield or is part of a uniform title (130) heading.
+func nameElementType(t string, code string, in_title bool) string {
+
+	if in_title {
+
+		switch code {
+		case "a":
+
+			if t == "Title" {
+				return "MainTitleElement"
+			}
+
+			return "TitleElement"
+
+		case "n":
+			return "PartNumberElement"
+		case "p":
+			return "PartNameElement"
+		default:
+			return "TitleElement"
+		}
+	}
+
+	switch t {
+	case "PersonalName", "FamilyName", "NameTitle":
+		// pass
+	default:
+		return "NameElement"
+	}
+
+	switch code {
+	case "a":
+
+		if t == "FamilyName" {
+			return "FamilyNameElement"
+		}
+
+		return "FullNameElement"
+
+	case "q":
+		return "FullNameElement"
+	case "b", "c":
+		return "TermsOfAddressNameElement"
+	case "d":
+		return "DateNameElement"
+	default:
+		return "NameElement"
+	}
+}
+
+// addVariants() adds a `madsrdf:Variant` node for each see from tracing (4XX) and alternate graphic representation
+// (880) of a heading or see from tracing in 'r' to 'g' and assigns them to 'n'.
+func (g *madsGraph) addVariants(n map[string]interface{}, r *Record) {
+
+	variants := make([]map[string]string, 0)
+
+	for _, f := range r.DataFields {
+
+		tag := f.Tag
+
+		if tag == "880" {
+			tag = linkedTag(f)
+		}
+
+		if !strings.HasPrefix(tag, "4") && !(f.Tag == "880" && strings.HasPrefix(tag, "1")) {
+			continue
+		}
+
+		h, ok := newHeading(tag, f)
+
+		if !ok {
+			continue
+		}
+
+		v := map[string]interface{}{
+			"@type":                []string{"madsrdf:" + h.nodeType(), "madsrdf:Variant"},
+			"madsrdf:variantLabel": h.label(),
+		}
+
+		if !h.isComplex() && len(h.main) > 0 {
+			v["madsrdf:elementList"] = g.elements(h.madsType, h.main)
+		}
+
+		variants = append(variants, g.blank(v))
+	}
+
+	if len(variants) > 0 {
+		n["madsrdf:hasVariant"] = variants
+	}
+}
+
+// linkedTag() returns the tag of the field that 'f', an alternate graphic representation (880) field, is linked
+// to by its linkage ($6) subfield, for example "100" for "100-01/(B".
+func linkedTag(f *DataField) string {
+
+	link := f.Subfield("6")
+
+	if len(link) < 3 {
+		return ""
+	}
+
+	return link[0:3]
+}
+
+// addRelations() adds the see also tracings (5XX) in 'r' to 'n' as broader, narrower or reciprocal authorities
+// depending on the relationship code ($w/0) of each tracing. Tracings are resolved to URIs using their authority
+// record control number ($0), either a URI or a "(DLC)" prefixed LCCN. Tracings which can not be resolved are
+// added to 'g' as blank nodes.
+func (g *madsGraph) addRelations(ctx context.Context, n map[string]interface{}, r *Record, opts *MADSOptions) {
+
+	relations := map[string][]map[string]string{}
+
+	for _, f := range r.Fields(func(tag string) bool { return strings.HasPrefix(tag, "5") }) {
+
+		h, ok := newHeading(f.Tag, f)
+
+		if !ok {
+			continue
+		}
+
+		var target map[string]string
+
+		uri := relationURI(ctx, f, opts)
+
+		if uri != "" {
+			target = ref(uri)
+		} else {
+
+			t := map[string]interface{}{
+				"@type":                      []string{"madsrdf:" + h.nodeType(), "madsrdf:Authority"},
+				"madsrdf:authoritativeLabel": h.label(),
+			}
+
+			target = g.blank(t)
+		}
+
+		rel := "madsrdf:hasReciprocalAuthority"
+
+		switch {
+		case strings.HasPrefix(f.Subfield("w"), "g"):
+			rel = "madsrdf:hasBroaderAuthority"
+		case strings.HasPrefix(f.Subfield("w"), "h"):
+			rel = "madsrdf:hasNarrowerAuthority"
+		}
+
+		relations[rel] = append(relations[rel], target)
+	}
+
+	for rel, targets := range relations {
+		n[rel] = targets
+	}
+}
+
+// relationURI() returns the URI of the authority referenced by the authority record control number ($0) subfields
+// of 'f' or an empty string if it can not be determined.
+func relationURI(ctx context.Context, f *DataField, opts *MADSOptions) string {
+
+	for _, v := range f.SubfieldValues("0") {
+
+		if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+			return v
+		}
+
+		if !strings.HasPrefix(v, "(DLC)") {
+			continue
+		}
+
+		lccn := dataset.NormalizeLCCN(strings.TrimPrefix(v, "(DLC)"))
+		ds, ok := dataset.DatasetForLCCN(ctx, lccn)
+
+		if ok {
+			return ds.URI(lccn)
+		}
+
+		if opts.BaseURI != "" {
+			return opts.BaseURI + lccn
+		}
+	}
+
+	return ""
+}
+
+// addExternalAuthorities() adds the standard identifiers (024) in 'r' to 'n' as exact external authorities.
+// Identifiers are resolved using their real world object URI ($1) or authority record URI ($0) subfields or,
+// failing that, by combining their value ($a) with the URI prefix for their source ($2).
+func (g *madsGraph) addExternalAuthorities(n map[string]interface{}, r *Record) {
+
+	external := make([]map[string]string, 0)
+	seen := make(map[string]bool)
+
+	for _, f := range r.FieldsWithTag("024") {
+
+		uri := ""
+
+		for _, code := range []string{"1", "0"} {
+
+			v := f.Subfield(code)
+
+			if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+				uri = v
+				break
+			}
+		}
+
+		value := f.Subfield("a")
+		source := strings.ToLower(f.Subfield("2"))
+
+		if uri == "" && (source == "uri" || strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")) {
+			uri = value
+		}
+
+		if uri == "" && value != "" {
+
+			prefix, ok := externalSources[source]
+
+			if ok {
+
+				switch source {
+				case "isni":
+					value = strings.Join(strings.Fields(value), "")
+				case "fast":
+					value = strings.TrimLeft(strings.TrimPrefix(value, "fst"), "0")
+				}
+
+				uri = prefix + value
+			}
+		}
+
+		if uri == "" || seen[uri] {
+			continue
+		}
+
+		seen[uri] = true
+		external = append(external, ref(uri))
+	}
+
+	if len(external) > 0 {
+		n["madsrdf:hasExactExternalAuthority"] = external
+	}
+}
+
+// addClassifications() adds the LC classification numbers (053) in 'r' to 'n'. Ranges are encoded as "{START}-{END}".
+func (g *madsGraph) addClassifications(n map[string]interface{}, r *Record) {
+
+	classifications := make([]string, 0)
+
+	for _, f := range r.FieldsWithTag("053") {
+
+		start := f.Subfield("a")
+
+		if start == "" {
+			continue
+		}
+
+		code := start
+		end := f.Subfield("b")
+
+		if end != "" {
+			code = fmt.Sprintf("%s-%s", start, end)
+		}
+
+		classifications = append(classifications, code)
+	}
+
+	if len(classifications) > 0 {
+		n["madsrdf:classification"] = classifications
+	}
+}
+
+// addSources() adds a `madsrdf:Source` node for each source data found (670) field in 'r' to 'g' and assigns them to 'n'.
+func (g *madsGraph) addSources(n map[string]interface{}, r *Record) {
+
+	sources := make([]map[string]string, 0)
+
+	for _, f := range r.FieldsWithTag("670") {
+
+		citation := f.Subfield("a")
+
+		if citation == "" {
+			continue
+		}
+
+		s := map[string]interface{}{
+			"@type":                  "madsrdf:Source",
+			"madsrdf:citationSource": citation,
+		}
+
+		note := f.Subfield("b")
+
+		if note != "" {
+			s["madsrdf:citationNote"] = note
+		}
+
+		uri := f.Subfield("u")
+
+		if uri != "" {
+			s["madsrdf:citationURI"] = uri
+		}
+
+		sources = append(sources, g.blank(s))
+	}
+
+	if len(sources) > 0 {
+		n["madsrdf:hasSource"] = sources
+	}
+}
+
+// addNotes() adds the public general notes (680) in 'r' to 'n' as scope notes and the replacement headings
+// referenced by the deleted heading information (682) fields as `madsrdf:useInstead` pointers.
+func (g *madsGraph) addNotes(ctx context.Context, n map[string]interface{}, r *Record, opts *MADSOptions) {
+
+	notes := make([]string, 0)
+
+	for _, f := range r.FieldsWithTag("680") {
+
+		note := strings.Join(f.SubfieldValues("i"), " ")
+
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	if len(notes) > 0 {
+		n["skos:scopeNote"] = notes
+	}
+
+	use_instead := make([]map[string]string, 0)
+
+	for _, f := range r.FieldsWithTag("682") {
+
+		uri := relationURI(ctx, f, opts)
+
+		if uri != "" {
+			use_instead = append(use_instead, ref(uri))
+		}
+	}
+
+	if len(use_instead) > 0 {
+		n["madsrdf:useInstead"] = use_instead
+	}
+}
+
+// addRecordInfo() adds `ri:RecordInfo` nodes derived from the date entered on file (008/00-05) and the date and
+// time of latest transaction (005) in 'r' to 'g' and assigns them to 'n'. The organization that created the
+// record is derived from the original cataloging agency (040 $a).
+func (g *madsGraph) addRecordInfo(n map[string]interface{}, r *Record, status string) {
+
+	source := ""
+
+	for _, f := range r.FieldsWithTag("040") {
+
+		v := f.Subfield("a")
+
+		if v != "" {
+			source = "http://id.loc.gov/vocabulary/organizations/" + strings.ToLower(v)
+			break
+		}
+	}
+
+	admin := make([]map[string]string, 0)
+
+	add := func(date string, status string) {
+
+		ri := map[string]interface{}{
+			"@type": "ri:RecordInfo",
+			"ri:recordChangeDate": map[string]string{
+				"@type":  "xsd:dateTime",
+				"@value": date,
+			},
+			"ri:recordStatus": status,
+		}
+
+		if source != "" {
+			ri["ri:recordContentSource"] = ref(source)
+		}
+
+		admin = append(admin, g.blank(ri))
+	}
+
+	created, ok := enteredDate(r.ControlField("008"))
+
+	if ok {
+		add(created, "new")
+	}
+
+	latest, ok := transactionDate(r.ControlField("005"))
+
+	if ok && status != "" && latest != created {
+		add(latest, status)
+	}
+
+	if len(admin) > 0 {
+		n["madsrdf:adminMetadata"] = admin
+	}
+}
+
+// enteredDate() returns the date entered on file (positions 00-05, "yymmdd") of 'str', a fixed-length data elements
+// (008) field, encoded as an `xsd:dateTime` string and a boolean value indicating whether it could be parsed. Years
+// before 68 are assumed to be in the 21st century.
+func enteredDate(str string) (string, bool) {
+
+	if len(str) < 6 || !isDigits(str[0:6]) {
+		return "", false
+	}
+
+	century := "19"
+
+	if str[0:2] < "68" {
+		century = "20"
+	}
+
+	return fmt.Sprintf("%s%s-%s-%sT00:00:00", century, str[0:2], str[2:4], str[4:6]), true
+}
+
+// transactionDate() returns 'str', a date and time of latest transaction (005) field ("yyyymmddhhmmss.f"), encoded
+// as an `xsd:dateTime` string and a boolean value indicating whether it could be parsed.
+func transactionDate(str string) (string, bool) {
+
+	if len(str) < 14 || !isDigits(str[0:14]) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s-%s-%sT%s:%s:%s", str[0:4], str[4:6], str[6:8], str[8:10], str[10:12], str[12:14]), true
+}
+
+// isDigits() returns a boolean value indicating whether 'str' consists entirely of ASCII digits.
+func isDigits(str string) bool {
+
+	for _, c := range str {
+
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ref() returns a JSON-LD node reference to 'id'.
+func ref(id string) map[string]string {
+	return map[string]string{
+		"@id": id,
+	}
+}
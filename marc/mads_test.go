@@ -0,0 +1,194 @@
+package marc
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestToMADS(t *testing.T) {
+
+	ctx := context.Background()
+
+	fh, err := os.Open("../fixtures/authorities.sample.marcxml")
+
+	if err != nil {
+		t.Fatalf("Failed to open fixture, %v", err)
+	}
+
+	defer fh.Close()
+
+	dec := NewXMLDecoder(fh)
+	authorities := make([]*authority.Authority, 0)
+
+	for {
+
+		r, err := dec.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to decode record, %v", err)
+		}
+
+		body, err := ToMADS(ctx, r, nil)
+
+		if err != nil {
+			t.Fatalf("Failed to convert record, %v", err)
+		}
+
+		a, err := authority.ParseRecord(body)
+
+		if err != nil {
+			t.Fatalf("Failed to parse record, %v", err)
+		}
+
+		authorities = append(authorities, a)
+	}
+
+	// Personal name
+
+	a := authorities[0]
+
+	if a.ID != "http://id.loc.gov/authorities/names/n79021164" || a.Label() != "Twain, Mark, 1835-1910" {
+		t.Fatalf("Unexpected authority: %s (%s)", a.ID, a.Label())
+	}
+
+	if a.MADSType() != "PersonalName" || len(a.Elements) != 2 || a.Elements[1].Type != "madsrdf:DateNameElement" {
+		t.Fatalf("Unexpected type or elements for %s", a.ID)
+	}
+
+	variants := make([]string, 0)
+
+	for _, v := range a.VariantLabels() {
+		variants = append(variants, v.Value)
+	}
+
+	if strings.Join(variants, "|") != "Clemens, Samuel Langhorne, 1835-1910|Snodgrass, Quintus Curtius, 1835-1910|Твен, Марк, 1835-1910" {
+		t.Fatalf("Unexpected variants: %v", variants)
+	}
+
+	external := make([]string, 0)
+
+	for _, ext := range a.ExternalAuthorities {
+		external = append(external, ext.ID)
+	}
+
+	if strings.Join(external, ",") != "http://www.wikidata.org/entity/Q7245,http://isni.org/isni/0000000120995006" {
+		t.Fatalf("Unexpected external authorities: %v", external)
+	}
+
+	if len(a.Sources) != 2 || a.Sources[0].Note != "t.p. (Mark Twain)" || a.Sources[1].URI != "https://en.wikipedia.org/wiki/Mark_Twain" {
+		t.Fatalf("Unexpected sources: %v", a.Sources)
+	}
+
+	if a.Status() != "revised" || a.LastModified().Format("2006-01-02") != "2023-03-14" {
+		t.Fatalf("Unexpected status or last modified date: %s %v", a.Status(), a.LastModified())
+	}
+
+	// Topical heading with see also references
+
+	a = authorities[1]
+
+	if strings.Join(a.Broader, ",") != "http://id.loc.gov/authorities/subjects/sh85004652" {
+		t.Fatalf("Unexpected broader: %v", a.Broader)
+	}
+
+	// The reciprocal see also reference does not have an identifier so it can not be resolved
+
+	if len(a.Related) != 0 {
+		t.Fatalf("Unexpected related: %v", a.Related)
+	}
+
+	if strings.Join(a.ClassificationCodes(), ",") != "TK7871.58.B74" {
+		t.Fatalf("Unexpected classifications: %v", a.ClassificationCodes())
+	}
+
+	// Pre-coordinated heading
+
+	a = authorities[2]
+
+	if a.Label() != "Anarchism--Italy--History--20th century" || !a.IsComplex() {
+		t.Fatalf("Unexpected complex heading: %s", a.Label())
+	}
+
+	component_types := make([]string, 0)
+
+	for _, c := range a.Components {
+		component_types = append(component_types, c.Type)
+	}
+
+	if strings.Join(component_types, ",") != "Topic,Geographic,Topic,Temporal" {
+		t.Fatalf("Unexpected component types: %v", component_types)
+	}
+
+	// Free-floating subdivision
+
+	a = authorities[3]
+
+	if a.Label() != "History" || !a.IsSubdivision() || a.ScopeNote() == "" {
+		t.Fatalf("Unexpected subdivision: %s", a.Label())
+	}
+
+	// Deleted heading
+
+	a = authorities[4]
+
+	if !a.IsDeprecated() || strings.Join(a.Replacements(), ",") != "http://id.loc.gov/authorities/subjects/sh85004652" {
+		t.Fatalf("Unexpected deprecated heading: %s %v", a.ID, a.Replacements())
+	}
+}
+
+func TestToMADSBaseURI(t *testing.T) {
+
+	ctx := context.Background()
+
+	r := &Record{
+		Leader: "00000nz  a2200000n  4500",
+		ControlFields: []*ControlField{
+			{Tag: "001", Value: "fst01204155"},
+		},
+		DataFields: []*DataField{
+			{
+				Tag: "151",
+				Subfields: []*Subfield{
+					{Code: "a", Value: "California"},
+				},
+			},
+		},
+	}
+
+	opts := &MADSOptions{
+		BaseURI: "http://id.worldcat.org/fast/",
+	}
+
+	body, err := ToMADS(ctx, r, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to convert record, %v", err)
+	}
+
+	a, err := authority.ParseRecord(body)
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	if a.ID != "http://id.worldcat.org/fast/fst01204155" || a.MADSType() != "Geographic" {
+		t.Fatalf("Unexpected authority: %s (%s)", a.ID, a.MADSType())
+	}
+
+	r.DataFields = []*DataField{}
+
+	_, err = ToMADS(ctx, r, opts)
+
+	if err == nil {
+		t.Fatalf("Expected error for record without a heading")
+	}
+}
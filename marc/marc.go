@@ -0,0 +1,139 @@
+// Package marc provides methods for reading MARC 21 authority records, encoded as MARCXML or as binary (ISO 2709)
+// MARC using either the MARC-8 or UTF-8 character sets, and converting them in to the same JSON-LD (MADS/RDF)
+// representation that id.loc.gov uses for its `*.both.ndjson` files so that they can be processed by the `authority`
+// package.
+package marc
+
+import (
+	"strings"
+)
+
+// type Record is a MARC 21 record.
+type Record struct {
+	// Leader is the (24 character) leader of the record.
+	Leader string `xml:"leader" json:"leader"`
+	// ControlFields is the list of control fields (001-009) in the record.
+	ControlFields []*ControlField `xml:"controlfield" json:"controlfields,omitempty"`
+	// DataFields is the list of data fields (010-999) in the record.
+	DataFields []*DataField `xml:"datafield" json:"datafields,omitempty"`
+}
+
+// type ControlField is a MARC 21 control field, for example "001" (control number) or "005" (date and time of
+// latest transaction).
+type ControlField struct {
+	// Tag is the three character tag of the field, for example "001".
+	Tag string `xml:"tag,attr" json:"tag"`
+	// Value is the value of the field.
+	Value string `xml:",chardata" json:"value"`
+}
+
+// type DataField is a MARC 21 data field, for example "100" (heading - personal name).
+type DataField struct {
+	// Tag is the three character tag of the field, for example "100".
+	Tag string `xml:"tag,attr" json:"tag"`
+	// Ind1 is the first indicator of the field.
+	Ind1 string `xml:"ind1,attr" json:"ind1"`
+	// Ind2 is the second indicator of the field.
+	Ind2 string `xml:"ind2,attr" json:"ind2"`
+	// Subfields is the list of subfields, in order, for the field.
+	Subfields []*Subfield `xml:"subfield" json:"subfields"`
+}
+
+// type Subfield is a subfield of a MARC 21 data field.
+type Subfield struct {
+	// Code is the (single character) subfield code, for example "a".
+	Code string `xml:"code,attr" json:"code"`
+	// Value is the value of the subfield.
+	Value string `xml:",chardata" json:"value"`
+}
+
+// ControlField() returns the (trimmed) value of the first control field in 'r' whose tag is 'tag' or an empty string
+// if there is no matching field.
+func (r *Record) ControlField(tag string) string {
+
+	for _, f := range r.ControlFields {
+
+		if f.Tag == tag {
+			return strings.TrimSpace(f.Value)
+		}
+	}
+
+	return ""
+}
+
+// Fields() returns the list of data fields in 'r' whose tag matches 'match' (a function which is passed the tag of
+// each field).
+func (r *Record) Fields(match func(string) bool) []*DataField {
+
+	fields := make([]*DataField, 0)
+
+	for _, f := range r.DataFields {
+
+		if match(f.Tag) {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// FieldsWithTag() returns the list of data fields in 'r' whose tag is one of 'tags'.
+func (r *Record) FieldsWithTag(tags ...string) []*DataField {
+
+	return r.Fields(func(tag string) bool {
+
+		for _, t := range tags {
+
+			if t == tag {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Status() returns the record status (leader position 05) of 'r', for example "n" (new) or "c" (corrected or
+// revised). If the leader is too short an empty string is returned.
+func (r *Record) Status() string {
+
+	if len(r.Leader) < 6 {
+		return ""
+	}
+
+	return r.Leader[5:6]
+}
+
+// IsAuthority() returns a boolean value indicating whether 'r' is an authority record (leader position 06 is "z").
+func (r *Record) IsAuthority() bool {
+	return len(r.Leader) >= 7 && r.Leader[6] == 'z'
+}
+
+// Subfield() returns the (trimmed) value of the first subfield in 'f' whose code is 'code' or an empty string if
+// there is no matching subfield.
+func (f *DataField) Subfield(code string) string {
+
+	for _, sf := range f.Subfields {
+
+		if sf.Code == code {
+			return strings.TrimSpace(sf.Value)
+		}
+	}
+
+	return ""
+}
+
+// SubfieldValues() returns the list of (trimmed) values for the subfields in 'f' whose code is 'code'.
+func (f *DataField) SubfieldValues(code string) []string {
+
+	values := make([]string, 0)
+
+	for _, sf := range f.Subfields {
+
+		if sf.Code == code {
+			values = append(values, strings.TrimSpace(sf.Value))
+		}
+	}
+
+	return values
+}
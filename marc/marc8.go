@@ -0,0 +1,496 @@
+package marc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MARC-8 control characters.
+const (
+	// marc8Escape is the escape character used to introduce MARC-8 character set designations.
+	marc8Escape byte = 0x1B
+	// replacementCharacter is substituted for characters in MARC-8 character sets that are not supported.
+	replacementCharacter rune = '\uFFFD'
+)
+
+// MARC-8 graphic character sets. These are the final characters of the escape sequences used to designate them.
+const (
+	marc8BasicLatin    byte = 'B'
+	marc8ExtendedLatin byte = 'E'
+	marc8GreekSymbols  byte = 'g'
+	marc8Subscripts    byte = 'b'
+	marc8Superscripts  byte = 'p'
+)
+
+// marc8ExtendedLatinChars maps the spacing characters in the MARC-8 Extended Latin (ANSEL) character set to Unicode.
+var marc8ExtendedLatinChars = map[byte]rune{
+	0x88: '\u0098',
+	0x89: '\u009C',
+	0x8D: '\u200D',
+	0x8E: '\u200C',
+	0xA1: 'Ł',
+	0xA2: 'Ø',
+	0xA3: 'Đ',
+	0xA4: 'Þ',
+	0xA5: 'Æ',
+	0xA6: 'Œ',
+	0xA7: 'ʹ',
+	0xA8: '·',
+	0xA9: '♭',
+	0xAA: '®',
+	0xAB: '±',
+	0xAC: 'Ơ',
+	0xAD: 'Ư',
+	0xAE: 'ʼ',
+	0xB0: 'ʻ',
+	0xB1: 'ł',
+	0xB2: 'ø',
+	0xB3: 'đ',
+	0xB4: 'þ',
+	0xB5: 'æ',
+	0xB6: 'œ',
+	0xB7: 'ʺ',
+	0xB8: 'ı',
+	0xB9: '£',
+	0xBA: 'ð',
+	0xBC: 'ơ',
+	0xBD: 'ư',
+	0xC0: '°',
+	0xC1: 'ℓ',
+	0xC2: '℗',
+	0xC3: '©',
+	0xC4: '♯',
+	0xC5: '¿',
+	0xC6: '¡',
+	0xC7: 'ß',
+	0xC8: '€',
+}
+
+// marc8CombiningChars maps the combining (non-spacing) characters in the MARC-8 Extended Latin (ANSEL) character
+// set to Unicode. In MARC-8 combining characters precede the character they modify; in Unicode they follow it.
+var marc8CombiningChars = map[byte]rune{
+	0xE0: '\u0309',
+	0xE1: '\u0300',
+	0xE2: '\u0301',
+	0xE3: '\u0302',
+	0xE4: '\u0303',
+	0xE5: '\u0304',
+	0xE6: '\u0306',
+	0xE7: '\u0307',
+	0xE8: '\u0308',
+	0xE9: '\u030C',
+	0xEA: '\u030A',
+	0xEB: '\uFE20',
+	0xEC: '\uFE21',
+	0xED: '\u0315',
+	0xEE: '\u030B',
+	0xEF: '\u0310',
+	0xF0: '\u0327',
+	0xF1: '\u0328',
+	0xF2: '\u0323',
+	0xF3: '\u0324',
+	0xF4: '\u0325',
+	0xF5: '\u0333',
+	0xF6: '\u0332',
+	0xF7: '\u0326',
+	0xF8: '\u031C',
+	0xF9: '\u032E',
+	0xFA: '\uFE22',
+	0xFB: '\uFE23',
+	0xFE: '\u0313',
+}
+
+// marc8GreekSymbolChars maps the MARC-8 Greek Symbols character set to Unicode.
+var marc8GreekSymbolChars = map[byte]rune{
+	0x61: 'α',
+	0x62: 'β',
+	0x63: 'γ',
+}
+
+// marc8SubscriptChars maps the MARC-8 Subscripts character set to Unicode.
+var marc8SubscriptChars = map[byte]rune{
+	0x28: '₍',
+	0x29: '₎',
+	0x2B: '₊',
+	0x2D: '₋',
+	0x30: '₀',
+	0x31: '₁',
+	0x32: '₂',
+	0x33: '₃',
+	0x34: '₄',
+	0x35: '₅',
+	0x36: '₆',
+	0x37: '₇',
+	0x38: '₈',
+	0x39: '₉',
+}
+
+// marc8SuperscriptChars maps the MARC-8 Superscripts character set to Unicode.
+var marc8SuperscriptChars = map[byte]rune{
+	0x28: '⁽',
+	0x29: '⁾',
+	0x2B: '⁺',
+	0x2D: '⁻',
+	0x30: '⁰',
+	0x31: '¹',
+	0x32: '²',
+	0x33: '³',
+	0x34: '⁴',
+	0x35: '⁵',
+	0x36: '⁶',
+	0x37: '⁷',
+	0x38: '⁸',
+	0x39: '⁹',
+}
+
+// unicodeExtendedLatinChars is the inverse of `marc8ExtendedLatinChars` and `marc8CombiningChars` used to encode
+// Unicode strings as MARC-8.
+var unicodeExtendedLatinChars map[rune]byte
+
+// unicodeTechnicalChars is the inverse of `marc8GreekSymbolChars`, `marc8SubscriptChars` and `marc8SuperscriptChars`
+// mapping Unicode characters to the final character of the escape sequence used to designate their character set
+// and their encoded value.
+var unicodeTechnicalChars map[rune][2]byte
+
+func init() {
+
+	unicodeExtendedLatinChars = make(map[rune]byte)
+
+	for b, r := range marc8ExtendedLatinChars {
+		unicodeExtendedLatinChars[r] = b
+	}
+
+	for b, r := range marc8CombiningChars {
+		unicodeExtendedLatinChars[r] = b
+	}
+
+	unicodeTechnicalChars = make(map[rune][2]byte)
+
+	technical := map[byte]map[byte]rune{
+		marc8GreekSymbols: marc8GreekSymbolChars,
+		marc8Subscripts:   marc8SubscriptChars,
+		marc8Superscripts: marc8SuperscriptChars,
+	}
+
+	for final, table := range technical {
+
+		for b, r := range table {
+			unicodeTechnicalChars[r] = [2]byte{final, b}
+		}
+	}
+}
+
+// type marc8Set is an internal structure describing a designated MARC-8 character set.
+type marc8Set struct {
+	// final is the final character of the escape sequence used to designate the character set.
+	final byte
+	// width is the number of bytes used to encode each character in the character set.
+	width int
+}
+
+// DecodeMARC8() decodes 'b', a MARC-8 encoded string, in to a (NFC normalized) UTF-8 string. The Basic Latin (ASCII),
+// Extended Latin (ANSEL), Greek Symbols, Subscripts and Superscripts character sets are supported. Characters in other
+// character sets (for example Cyrillic, Hebrew, Arabic or East Asian) are replaced with U+FFFD.
+func DecodeMARC8(b []byte) string {
+
+	g0 := &marc8Set{final: marc8BasicLatin, width: 1}
+	g1 := &marc8Set{final: marc8ExtendedLatin, width: 1}
+
+	var sb strings.Builder
+
+	combining := make([]rune, 0)
+
+	emit := func(r rune) {
+
+		sb.WriteRune(r)
+
+		for _, c := range combining {
+			sb.WriteRune(c)
+		}
+
+		combining = combining[:0]
+	}
+
+	for i := 0; i < len(b); i++ {
+
+		c := b[i]
+
+		if c == marc8Escape {
+
+			n, set, is_g1, ok := parseMARC8Escape(b[i+1:])
+
+			if !ok {
+				emit(replacementCharacter)
+				continue
+			}
+
+			if is_g1 {
+				g1 = set
+			} else {
+				g0 = set
+			}
+
+			i += n
+			continue
+		}
+
+		switch {
+		case c < 0x20 || c == 0x7F:
+			emit(rune(c))
+		case c == 0x20:
+			emit(' ')
+		case c < 0x80:
+			i += decodeMARC8Char(b[i:], g0, &combining, emit) - 1
+		case c >= 0xA1:
+			i += decodeMARC8Char(b[i:], g1, &combining, emit) - 1
+		default:
+
+			r, ok := marc8ExtendedLatinChars[c]
+
+			if !ok {
+				r = replacementCharacter
+			}
+
+			emit(r)
+		}
+	}
+
+	// Combining characters without a base character are attached to a space
+
+	if len(combining) > 0 {
+		emit(' ')
+	}
+
+	return norm.NFC.String(sb.String())
+}
+
+// decodeMARC8Char() decodes the character at the start of 'b' using the character set 'set' and returns the number of
+// bytes consumed. Combining characters are appended to 'combining'; all other characters are passed to 'emit'.
+func decodeMARC8Char(b []byte, set *marc8Set, combining *[]rune, emit func(rune)) int {
+
+	if set.width > 1 {
+		emit(replacementCharacter)
+		return min(set.width, len(b))
+	}
+
+	c := b[0]
+
+	switch set.final {
+	case marc8BasicLatin:
+		emit(rune(c & 0x7F))
+	case marc8ExtendedLatin:
+
+		g1 := c | 0x80
+
+		r, ok := marc8CombiningChars[g1]
+
+		if ok {
+			*combining = append(*combining, r)
+			return 1
+		}
+
+		r, ok = marc8ExtendedLatinChars[g1]
+
+		if !ok {
+			r = replacementCharacter
+		}
+
+		emit(r)
+
+	default:
+
+		var table map[byte]rune
+
+		switch set.final {
+		case marc8GreekSymbols:
+			table = marc8GreekSymbolChars
+		case marc8Subscripts:
+			table = marc8SubscriptChars
+		case marc8Superscripts:
+			table = marc8SuperscriptChars
+		}
+
+		r, ok := table[c&0x7F]
+
+		if !ok {
+			r = replacementCharacter
+		}
+
+		emit(r)
+	}
+
+	return 1
+}
+
+// parseMARC8Escape() parses the escape sequence at the start of 'b' (following the escape character) and returns the
+// number of bytes consumed, the character set it designates, a boolean value indicating whether the character set is
+// designated as G1 (rather than G0) and a boolean value indicating whether the escape sequence is valid.
+func parseMARC8Escape(b []byte) (int, *marc8Set, bool, bool) {
+
+	if len(b) == 0 {
+		return 0, nil, false, false
+	}
+
+	switch b[0] {
+	case 's':
+		return 1, &marc8Set{final: marc8BasicLatin, width: 1}, false, true
+	case marc8GreekSymbols, marc8Subscripts, marc8Superscripts:
+		return 1, &marc8Set{final: b[0], width: 1}, false, true
+	}
+
+	i := 0
+	width := 1
+
+	if b[i] == '$' {
+		width = 3
+		i += 1
+	}
+
+	is_g1 := false
+
+	if i < len(b) {
+
+		switch b[i] {
+		case '(', ',':
+			i += 1
+		case ')', '-':
+			is_g1 = true
+			i += 1
+		default:
+			// Multibyte character sets may be designated as G0 without an intermediate character, for example "ESC $ 1"
+
+			if width == 1 {
+				return 0, nil, false, false
+			}
+		}
+	}
+
+	if i >= len(b) {
+		return 0, nil, false, false
+	}
+
+	final := b[i]
+
+	// The ANSEL character set may also be designated using "ESC ) ! E"
+
+	if final == '!' && i+1 < len(b) {
+		i += 1
+		final = b[i]
+	}
+
+	set := &marc8Set{
+		final: final,
+		width: width,
+	}
+
+	return i + 1, set, is_g1, true
+}
+
+// EncodeMARC8() encodes 's', a UTF-8 string, as MARC-8 using the Basic Latin (ASCII), Extended Latin (ANSEL), Greek
+// Symbols, Subscripts and Superscripts character sets. An error is returned if 's' contains characters which can not be
+// represented in those character sets.
+func EncodeMARC8(s string) ([]byte, error) {
+
+	b := make([]byte, 0, len(s))
+	g0 := marc8BasicLatin
+
+	designate := func(final byte) {
+
+		if final == g0 {
+			return
+		}
+
+		if final == marc8BasicLatin {
+			b = append(b, marc8Escape, 's')
+		} else {
+			b = append(b, marc8Escape, final)
+		}
+
+		g0 = final
+	}
+
+	runes := []rune(norm.NFC.String(s))
+
+	for i := 0; i < len(runes); i++ {
+
+		r := runes[i]
+
+		tc, ok := unicodeTechnicalChars[r]
+
+		if ok {
+			designate(tc[0])
+			b = append(b, tc[1])
+			continue
+		}
+
+		designate(marc8BasicLatin)
+
+		// Gather the combining characters that follow 'r' since, in MARC-8, they precede it. Characters
+		// which are not part of the Extended Latin character set are decomposed in to a base character
+		// followed by combining characters.
+
+		base := r
+		marks := make([]rune, 0)
+
+		_, is_latin := unicodeExtendedLatinChars[r]
+
+		if r >= 0x80 && (!is_latin || isCombining(r)) {
+
+			decomposed := []rune(norm.NFD.String(string(r)))
+
+			base = decomposed[0]
+			marks = append(marks, decomposed[1:]...)
+		}
+
+		j := i + 1
+
+		for j < len(runes) && isCombining(runes[j]) {
+			marks = append(marks, runes[j])
+			j += 1
+		}
+
+		if isCombining(base) {
+			marks = append([]rune{base}, marks...)
+			base = ' '
+		}
+
+		for _, m := range marks {
+
+			enc, ok := unicodeExtendedLatinChars[m]
+
+			if !ok {
+				return nil, fmt.Errorf("Unable to encode %U as MARC-8", m)
+			}
+
+			b = append(b, enc)
+		}
+
+		switch {
+		case base < 0x80:
+			b = append(b, byte(base))
+		default:
+
+			enc, ok := unicodeExtendedLatinChars[base]
+
+			if !ok {
+				return nil, fmt.Errorf("Unable to encode %U as MARC-8", base)
+			}
+
+			b = append(b, enc)
+		}
+
+		i = j - 1
+	}
+
+	designate(marc8BasicLatin)
+
+	return b, nil
+}
+
+// isCombining() returns a boolean value indicating whether 'r' is a combining character.
+func isCombining(r rune) bool {
+	return unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,66 @@
+package marc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeMARC8(t *testing.T) {
+
+	tests := map[string]string{
+		"Broadband amplifiers":                 "Broadband amplifiers",
+		"Dvo\xe9r\xe2ak, Anton\xe2in,":         "Dvořák, Antonín,",
+		"Bront\xe8e, Charlotte,":               "Brontë, Charlotte,",
+		"\xa1\xe2od\xe2z":                      "Łódź",
+		"D\x1bb2\x1bsO":                        "D₂O",
+		"E = mc\x1bp2\x1bs":                    "E = mc²",
+		"\x1bga\x1bs-particles":                "α-particles",
+		"\xc3 1998":                            "© 1998",
+		"Ch\x1b(Nnp\x1b(B, N":                  "Ch��, N",
+		"\x1b$1\x21\x21\x21\x21\x21\x21\x1b(B": "��",
+	}
+
+	for input, expected := range tests {
+
+		output := DecodeMARC8([]byte(input))
+
+		if output != expected {
+			t.Fatalf("Unexpected output for %q: %q (expected %q)", input, output, expected)
+		}
+	}
+}
+
+func TestEncodeMARC8(t *testing.T) {
+
+	tests := map[string]string{
+		"Broadband amplifiers": "Broadband amplifiers",
+		"Dvořák, Antonín,":     "Dvo\xe9r\xe2ak, Anton\xe2in,",
+		"Łódź":                 "\xa1\xe2od\xe2z",
+		"D₂O":                  "D\x1bb2\x1bsO",
+		"E = mc²":              "E = mc\x1bp2\x1bs",
+		"Ơn":                   "\xacn",
+	}
+
+	for input, expected := range tests {
+
+		output, err := EncodeMARC8(input)
+
+		if err != nil {
+			t.Fatalf("Failed to encode %q, %v", input, err)
+		}
+
+		if !bytes.Equal(output, []byte(expected)) {
+			t.Fatalf("Unexpected output for %q: %q (expected %q)", input, output, expected)
+		}
+
+		if DecodeMARC8(output) != input {
+			t.Fatalf("Failed to round-trip %q: %q", input, DecodeMARC8(output))
+		}
+	}
+
+	_, err := EncodeMARC8("Москва")
+
+	if err == nil {
+		t.Fatalf("Expected error encoding Cyrillic characters")
+	}
+}
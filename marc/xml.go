@@ -0,0 +1,59 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// type XMLDecoder reads MARC 21 records from a MARCXML document. Records are decoded one at a time so that very
+// large documents can be processed without reading the entire document in to memory.
+type XMLDecoder struct {
+	decoder *xml.Decoder
+}
+
+// NewXMLDecoder() returns a new `XMLDecoder` instance for reading records from 'r'. 'r' may contain a single
+// `<record>` element or a `<collection>` of records, with or without the MARCXML ("http://www.loc.gov/MARC21/slim")
+// namespace.
+func NewXMLDecoder(r io.Reader) *XMLDecoder {
+
+	d := &XMLDecoder{
+		decoder: xml.NewDecoder(r),
+	}
+
+	return d
+}
+
+// Next() returns the next record in the document. It returns `io.EOF` when there are no more records.
+func (d *XMLDecoder) Next() (*Record, error) {
+
+	for {
+
+		t, err := d.decoder.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		se, ok := t.(xml.StartElement)
+
+		if !ok || se.Name.Local != "record" {
+			continue
+		}
+
+		var r *Record
+
+		err = d.decoder.DecodeElement(&r, &se)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode record, %w", err)
+		}
+
+		return r, nil
+	}
+}
+
+// InputOffset() returns the byte offset of the current position of the decoder in the underlying document.
+func (d *XMLDecoder) InputOffset() int64 {
+	return d.decoder.InputOffset()
+}
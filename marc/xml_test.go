@@ -0,0 +1,92 @@
+package marc
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestXMLDecoder(t *testing.T) {
+
+	fh, err := os.Open("../fixtures/authorities.sample.marcxml")
+
+	if err != nil {
+		t.Fatalf("Failed to open fixture, %v", err)
+	}
+
+	defer fh.Close()
+
+	dec := NewXMLDecoder(fh)
+	records := make([]*Record, 0)
+
+	for {
+
+		r, err := dec.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to decode record, %v", err)
+		}
+
+		records = append(records, r)
+	}
+
+	if len(records) != 5 {
+		t.Fatalf("Unexpected number of records: %d", len(records))
+	}
+
+	r := records[0]
+
+	if !r.IsAuthority() || r.Status() != "c" {
+		t.Fatalf("Unexpected leader: %s", r.Leader)
+	}
+
+	if r.ControlField("001") != "n  79021164" {
+		t.Fatalf("Unexpected control number: '%s'", r.ControlField("001"))
+	}
+
+	headings := r.FieldsWithTag("100")
+
+	if len(headings) != 1 {
+		t.Fatalf("Unexpected number of headings: %d", len(headings))
+	}
+
+	if headings[0].Subfield("a") != "Twain, Mark," || headings[0].Subfield("d") != "1835-1910" {
+		t.Fatalf("Unexpected heading: %v", headings[0])
+	}
+
+	if len(r.FieldsWithTag("400", "880")) != 3 {
+		t.Fatalf("Unexpected number of variants")
+	}
+
+	if records[4].Status() != "d" {
+		t.Fatalf("Unexpected status: %s", records[4].Status())
+	}
+}
+
+func TestXMLDecoderSingleRecord(t *testing.T) {
+
+	doc := `<record><leader>00000nz  a2200000n  4500</leader><datafield tag="150" ind1=" " ind2=" "><subfield code="a">Photographs</subfield></datafield></record>`
+
+	dec := NewXMLDecoder(strings.NewReader(doc))
+
+	r, err := dec.Next()
+
+	if err != nil {
+		t.Fatalf("Failed to decode record, %v", err)
+	}
+
+	if strings.Join(r.FieldsWithTag("150")[0].SubfieldValues("a"), ",") != "Photographs" {
+		t.Fatalf("Unexpected heading")
+	}
+
+	_, err = dec.Next()
+
+	if err != io.EOF {
+		t.Fatalf("Expected EOF, got %v", err)
+	}
+}
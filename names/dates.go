@@ -0,0 +1,264 @@
+package names
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// re_year matches a (possibly qualified) year at the start of a string, for example "1748", "ca. 1700", "1700?"
+// or "383 B.C.".
+var re_year = regexp.MustCompile(`^(?:(ca\.|approximately)\s*)?(\d{1,4})(\?)?(?:\s+(B\.\s?C\.?|BC|BCE))?(?:\s+or\s+\d{1,4}\??)?`)
+
+// re_century matches a century at the start of a string, for example "18th cent." or "18th century".
+var re_century = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)\s+cent(?:ury|\.)?`)
+
+// re_dates matches strings which look like the dates portion of a personal name heading.
+var re_dates = regexp.MustCompile(`^(?:(?:b\.|d\.|fl\.|born|died|active|ca\.|approximately)\s*)?(?:\d{1,4}\??(?:\s|-|$|,|\.)|\d{1,2}(?:st|nd|rd|th)\s+cent|-\s*\d{1,4})`)
+
+// type Date is a (partial) date parsed from the dates portion of a personal name heading.
+type Date struct {
+	// Year is the year of the date. Years before the common era are negative, for example -384 is 384 B.C. (There is
+	// no year 0.)
+	Year int `json:"year"`
+	// Century is a boolean flag indicating that the date is a century (for example "18th cent.") rather
+	// than a year. In this case 'Year' is the first year of the century.
+	Century bool `json:"century,omitempty"`
+	// Approximate is a boolean flag indicating that the date is approximate (for example "ca. 1700").
+	Approximate bool `json:"approximate,omitempty"`
+	// Uncertain is a boolean flag indicating that the date is uncertain (for example "1700?" or "1700 or 1701").
+	Uncertain bool `json:"uncertain,omitempty"`
+}
+
+// type Dates are the birth, death and flourished dates parsed from the dates portion of a personal name heading.
+type Dates struct {
+	// Raw is the unparsed dates string, for example "1748-1798".
+	Raw string `json:"raw"`
+	// Birth is the date of birth, if known.
+	Birth *Date `json:"birth,omitempty"`
+	// Death is the date of death, if known.
+	Death *Date `json:"death,omitempty"`
+	// FlourishedFrom is the start of the period during which the person was active ("fl." or "active"), if known.
+	FlourishedFrom *Date `json:"flourished_from,omitempty"`
+	// FlourishedTo is the end of the period during which the person was active ("fl." or "active"), if known.
+	FlourishedTo *Date `json:"flourished_to,omitempty"`
+}
+
+// EDTF() returns an Extended Date/Time Format (EDTF) representation of 'd', for example "1748", "1700~"
+// (approximate), "1700?" (uncertain), "17XX" (century) or "-0383" (before the common era).
+func (d *Date) EDTF() string {
+
+	var str string
+
+	switch {
+	case d.Century && d.Year >= 0:
+		str = fmt.Sprintf("%02dXX", d.Year/100)
+	case d.Century:
+		str = fmt.Sprintf("-%02dXX", (-d.Year)/100)
+	case d.Year < 0:
+
+		// EDTF uses astronomical year numbering, where 1 B.C. is year 0, so 384 B.C. is "-0383"
+
+		y := -d.Year - 1
+
+		if y == 0 {
+			str = "0000"
+		} else {
+			str = fmt.Sprintf("-%04d", y)
+		}
+
+	default:
+		str = fmt.Sprintf("%04d", d.Year)
+	}
+
+	switch {
+	case d.Approximate && d.Uncertain:
+		str = str + "%"
+	case d.Approximate:
+		str = str + "~"
+	case d.Uncertain:
+		str = str + "?"
+	}
+
+	return str
+}
+
+// Flourished() returns an EDTF representation of the period during which the person was active. If both
+// 'FlourishedFrom' and 'FlourishedTo' are present an interval (for example "1750/1760") is returned. If
+// neither is present an empty string is returned.
+func (d *Dates) Flourished() string {
+
+	switch {
+	case d.FlourishedFrom != nil && d.FlourishedTo != nil:
+		return fmt.Sprintf("%s/%s", d.FlourishedFrom.EDTF(), d.FlourishedTo.EDTF())
+	case d.FlourishedFrom != nil:
+		return d.FlourishedFrom.EDTF()
+	case d.FlourishedTo != nil:
+		return fmt.Sprintf("../%s", d.FlourishedTo.EDTF())
+	default:
+		return ""
+	}
+}
+
+// ParseDates() parses 'str', the dates portion of a personal name heading (for example "1748-1798", "b. 1748",
+// "fl. 1750-1760" or "ca. 1700-1750"), in to a `Dates` instance. An error is returned if 'str' can not be parsed.
+func ParseDates(str string) (*Dates, error) {
+
+	raw := strings.TrimSpace(str)
+	str = strings.TrimRight(raw, ".,")
+	str = strings.TrimSpace(str)
+
+	d := &Dates{
+		Raw: raw,
+	}
+
+	qualifiers := map[string][]string{
+		"birth":      {"b.", "born"},
+		"death":      {"d.", "died"},
+		"flourished": {"fl.", "active"},
+	}
+
+	qualifier := ""
+
+	for _, k := range []string{"birth", "death", "flourished"} {
+
+		for _, prefix := range qualifiers[k] {
+
+			if strings.HasPrefix(str, prefix+" ") {
+				qualifier = k
+				str = strings.TrimSpace(strings.TrimPrefix(str, prefix))
+				break
+			}
+		}
+
+		if qualifier != "" {
+			break
+		}
+	}
+
+	start_str, end_str, is_range := splitRange(str)
+
+	var start *Date
+	var end *Date
+
+	if start_str != "" {
+
+		v, err := parseDate(start_str)
+
+		if err != nil {
+			return nil, err
+		}
+
+		start = v
+	}
+
+	if end_str != "" {
+
+		v, err := parseDate(end_str)
+
+		if err != nil {
+			return nil, err
+		}
+
+		end = v
+	}
+
+	// Propagate "B.C." qualifiers from the end of a range to the start of a range, for example "384-322 B.C."
+
+	if start != nil && end != nil && end.Year < 0 && start.Year > 0 {
+		start.Year = -start.Year
+	}
+
+	switch qualifier {
+	case "birth":
+		d.Birth = start
+	case "death":
+		d.Death = start
+	case "flourished":
+		d.FlourishedFrom = start
+		d.FlourishedTo = end
+	default:
+
+		d.Birth = start
+		d.Death = end
+
+		if !is_range && start != nil && start.Century {
+			d.Birth = nil
+			d.FlourishedFrom = start
+		}
+	}
+
+	if d.Birth == nil && d.Death == nil && d.FlourishedFrom == nil && d.FlourishedTo == nil {
+		return nil, fmt.Errorf("Failed to parse dates '%s'", raw)
+	}
+
+	return d, nil
+}
+
+// splitRange() splits 'str' in to the start and end of a range, separated by a hyphen, and returns a boolean
+// value indicating whether 'str' is a range.
+func splitRange(str string) (string, string, bool) {
+
+	idx := strings.Index(str, "-")
+
+	if idx == -1 {
+		return str, "", false
+	}
+
+	start := strings.TrimSpace(str[0:idx])
+	end := strings.TrimSpace(str[idx+1:])
+
+	return start, end, true
+}
+
+// parseDate() parses 'str', a single (possibly qualified) year or century, in to a `Date` instance.
+func parseDate(str string) (*Date, error) {
+
+	m := re_century.FindStringSubmatch(str)
+
+	if len(m) > 0 {
+
+		c, err := strconv.Atoi(m[1])
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse century '%s', %w", str, err)
+		}
+
+		d := &Date{
+			Year:    (c - 1) * 100,
+			Century: true,
+		}
+
+		return d, nil
+	}
+
+	m = re_year.FindStringSubmatch(str)
+
+	if len(m) == 0 {
+		return nil, fmt.Errorf("Failed to parse date '%s'", str)
+	}
+
+	y, err := strconv.Atoi(m[2])
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse year '%s', %w", str, err)
+	}
+
+	d := &Date{
+		Year:        y,
+		Approximate: m[1] != "",
+		Uncertain:   m[3] != "" || strings.Contains(m[0], " or "),
+	}
+
+	if m[4] != "" {
+		d.Year = -d.Year
+	}
+
+	return d, nil
+}
+
+// isDates() returns a boolean value indicating whether 'str' looks like the dates portion of a personal name heading.
+func isDates(str string) bool {
+	return re_dates.MatchString(strings.TrimSpace(str))
+}
@@ -0,0 +1,73 @@
+package names
+
+import (
+	"testing"
+)
+
+func TestParseDates(t *testing.T) {
+
+	tests := map[string][3]string{
+		"1748-1798":            {"1748", "1798", ""},
+		"1900-":                {"1900", "", ""},
+		"b. 1748":              {"1748", "", ""},
+		"d. 1798":              {"", "1798", ""},
+		"died 1798.":           {"", "1798", ""},
+		"fl. 1750-1760":        {"", "", "1750/1760"},
+		"active 1750":          {"", "", "1750"},
+		"ca. 1700-1750":        {"1700~", "1750", ""},
+		"1700?-1750":           {"1700?", "1750", ""},
+		"1700 or 1701-1750":    {"1700?", "1750", ""},
+		"18th cent.":           {"", "", "17XX"},
+		"384-322 B.C.":         {"-0383", "-0321", ""},
+		"1 B.C.-14 A.D.":       {"0000", "0014", ""},
+		"-1798":                {"", "1798", ""},
+		"approximately 1700-":  {"1700~", "", ""},
+		"fl. ca. 1750":         {"", "", "1750~"},
+		"1748-1798.":           {"1748", "1798", ""},
+		"17th cent.-18th cent": {"16XX", "17XX", ""},
+	}
+
+	for str, expected := range tests {
+
+		d, err := ParseDates(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		birth := ""
+		death := ""
+
+		if d.Birth != nil {
+			birth = d.Birth.EDTF()
+		}
+
+		if d.Death != nil {
+			death = d.Death.EDTF()
+		}
+
+		if birth != expected[0] {
+			t.Fatalf("Unexpected birth date for '%s': '%s' (expected '%s')", str, birth, expected[0])
+		}
+
+		if death != expected[1] {
+			t.Fatalf("Unexpected death date for '%s': '%s' (expected '%s')", str, death, expected[1])
+		}
+
+		if d.Flourished() != expected[2] {
+			t.Fatalf("Unexpected flourished date for '%s': '%s' (expected '%s')", str, d.Flourished(), expected[2])
+		}
+	}
+}
+
+func TestParseDatesInvalid(t *testing.T) {
+
+	for _, str := range []string{"", "Jr.", "King of France"} {
+
+		_, err := ParseDates(str)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", str)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+// Package names provides methods for decomposing Library of Congress personal name (and name/title) headings,
+// for example "Neefe, Christian Gottlob, 1748-1798. Veränderungen über den Priestermarsch aus Mozarts Zauberflöte",
+// in to their constituent parts.
+package names
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+// re_numeration matches Roman numerals, for example "II" or "XIV.".
+var re_numeration = regexp.MustCompile(`^[IVXLCDM]+\.?$`)
+
+// re_fuller_form matches a parenthetical fuller form of name at the end of a string, for example "J. R. (John Robert)".
+var re_fuller_form = regexp.MustCompile(`^(.*?)\s*\(([^)]+)\)$`)
+
+// titleWords is the list of words which, when they appear at the start of a component of a name heading,
+// indicate that the component is a title or term of address rather than a forename.
+var titleWords = []string{
+	"Archbishop",
+	"Baron",
+	"Baroness",
+	"Bishop",
+	"Brother",
+	"Cardinal",
+	"Count",
+	"Countess",
+	"Dame",
+	"Dr.",
+	"Duchess",
+	"Duke",
+	"Earl",
+	"Emperor",
+	"Empress",
+	"Father",
+	"Jr.",
+	"King",
+	"Lady",
+	"Lord",
+	"Marquis",
+	"Mother",
+	"Mrs.",
+	"Pope",
+	"Prince",
+	"Princess",
+	"Queen",
+	"Rev.",
+	"Saint",
+	"Sir",
+	"Sister",
+	"Sr.",
+}
+
+// type Name is the decomposition of a personal name (or name/title) heading in to its constituent parts.
+type Name struct {
+	// Family is the family name (surname) portion of the name, for example "Neefe".
+	Family string `json:"family,omitempty"`
+	// Given is the given name (forename) portion of the name, for example "Christian Gottlob".
+	Given string `json:"given,omitempty"`
+	// FullerForm is the fuller form of the name, if present, for example "John Robert" in "Smith, J. R. (John Robert)".
+	FullerForm string `json:"fuller_form,omitempty"`
+	// Numeration is the Roman numeral associated with the name, if present, for example "XIV" in "Louis XIV, King of France".
+	Numeration string `json:"numeration,omitempty"`
+	// Titles is the list of titles and other words associated with the name, for example "King of France".
+	Titles []string `json:"titles,omitempty"`
+	// Dates are the dates associated with the name, if present.
+	Dates *Dates `json:"dates,omitempty"`
+	// Work is the title of the work for name/title headings, for example "Veränderungen über den Priestermarsch aus Mozarts Zauberflöte".
+	Work string `json:"work,omitempty"`
+}
+
+// Parse() returns a `Name` instance derived from 'a'. If 'a' has a list of MADS/RDF elements (`madsrdf:elementList`)
+// they are used to decompose the name; otherwise the authoritative label for 'a' is parsed using `ParseString`.
+// This method is intended to be used with PersonalName, FamilyName and NameTitle authorities.
+func Parse(a *authority.Authority) (*Name, error) {
+
+	name_title := a.MADSType() == "NameTitle"
+
+	n, ok := parseElements(a.Elements)
+
+	if !ok {
+
+		v, err := parseString(a.Label(), name_title)
+
+		if err != nil {
+			return nil, err
+		}
+
+		n = v
+	}
+
+	// Family names (for example "Śreshṭha family") are neither a family name nor a given name in the
+	// sense of a personal name heading so we always treat them as a family name.
+
+	if a.MADSType() == "FamilyName" && n.Family == "" {
+		n.Family = n.Given
+		n.Given = ""
+	}
+
+	return n, nil
+}
+
+// ParseString() returns a `Name` instance derived from 'str', a personal name heading in the form used by the
+// LoC Name Authority File: "Family, Given, numeration, titles, dates. Work". If 'str' does not contain a dates
+// component it is assumed not to contain a work title. Use `Parse` for name/title headings without dates.
+func ParseString(str string) (*Name, error) {
+	return parseString(str, false)
+}
+
+// parseString() returns a `Name` instance derived from 'str'. If 'name_title' is true and 'str' does not contain
+// a dates component then the work title is assumed to begin after the first full stop following a word which is
+// not an initial.
+func parseString(str string, name_title bool) (*Name, error) {
+
+	str = strings.TrimSpace(str)
+
+	if str == "" {
+		return nil, fmt.Errorf("Empty name")
+	}
+
+	n := &Name{
+		Titles: make([]string, 0),
+	}
+
+	name_part := str
+	dates_part := ""
+	work_part := ""
+
+	parts := strings.Split(str, ", ")
+
+	for i, p := range parts {
+
+		if i == 0 || !isDates(p) {
+			continue
+		}
+
+		name_part = strings.Join(parts[0:i], ", ")
+		dates_part, work_part = splitDates(strings.Join(parts[i:], ", "))
+		break
+	}
+
+	if dates_part == "" && name_title {
+		name_part, work_part = splitWork(str)
+	}
+
+	parseNamePart(n, name_part)
+
+	if dates_part != "" {
+		n.Dates = parseDates(dates_part)
+	}
+
+	n.Work = strings.TrimSpace(work_part)
+
+	return n, nil
+}
+
+// parseElements() returns a `Name` instance derived from a list of MADS/RDF elements and a boolean value
+// indicating whether any name elements were found.
+func parseElements(elements []*authority.Element) (*Name, bool) {
+
+	n := &Name{
+		Titles: make([]string, 0),
+	}
+
+	found := false
+	work := make([]string, 0)
+
+	for _, e := range elements {
+
+		value := strings.TrimSpace(e.Value.Value)
+
+		switch e.Type {
+		case "madsrdf:FullNameElement":
+
+			if strings.HasPrefix(value, "(") {
+				n.FullerForm = strings.Trim(value, "()., ")
+			} else {
+				parseNamePart(n, value)
+			}
+
+			found = true
+
+		case "madsrdf:FamilyNameElement":
+			n.Family = strings.TrimRight(value, ", ")
+			found = true
+		case "madsrdf:GivenNameElement":
+			n.Given = strings.TrimRight(value, ", ")
+			found = true
+		case "madsrdf:DateNameElement":
+			n.Dates = parseDates(value)
+		case "madsrdf:TermsOfAddressNameElement":
+			addTerm(n, strings.TrimRight(value, ", "))
+		case "madsrdf:TitleElement", "madsrdf:MainTitleElement", "madsrdf:PartNameElement", "madsrdf:PartNumberElement", "madsrdf:SubTitleElement":
+			work = append(work, strings.TrimRight(value, "., "))
+		}
+	}
+
+	if len(work) > 0 {
+		n.Work = strings.Join(work, ". ")
+	}
+
+	return n, found
+}
+
+// parseNamePart() decomposes 'str', the name portion (without dates or work title) of a personal name heading,
+// assigning the results to 'n'.
+func parseNamePart(n *Name, str string) {
+
+	str = strings.TrimRight(strings.TrimSpace(str), ",")
+
+	parts := make([]string, 0)
+
+	for _, p := range strings.Split(str, ", ") {
+
+		p = strings.TrimSpace(p)
+
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	if len(parts) == 0 {
+		return
+	}
+
+	first := parts[0]
+	rest := parts[1:]
+
+	if len(rest) > 0 && !isTerm(rest[0]) {
+
+		// Inverted order: "Family, Given, ..."
+
+		n.Family = first
+		n.Given = rest[0]
+		rest = rest[1:]
+
+	} else {
+
+		// Direct order: "Given numeration, titles, ..." for example "Louis XIV, King of France"
+
+		words := strings.Fields(first)
+
+		if len(words) > 1 && re_numeration.MatchString(words[len(words)-1]) {
+			n.Numeration = strings.TrimRight(words[len(words)-1], ".")
+			first = strings.Join(words[0:len(words)-1], " ")
+		}
+
+		n.Given = first
+	}
+
+	m := re_fuller_form.FindStringSubmatch(n.Given)
+
+	if len(m) > 0 {
+		n.Given = m[1]
+		n.FullerForm = m[2]
+	}
+
+	for _, p := range rest {
+		addTerm(n, p)
+	}
+}
+
+// addTerm() assigns 'str' to either the numeration or the titles of 'n'.
+func addTerm(n *Name, str string) {
+
+	if str == "" {
+		return
+	}
+
+	if re_numeration.MatchString(str) {
+		n.Numeration = strings.TrimRight(str, ".")
+		return
+	}
+
+	n.Titles = append(n.Titles, str)
+}
+
+// isTerm() returns a boolean value indicating whether 'str' is a numeration, title or term of address rather than a forename.
+func isTerm(str string) bool {
+
+	if re_numeration.MatchString(str) {
+		return true
+	}
+
+	if strings.Contains(str, " of ") {
+		return true
+	}
+
+	for _, w := range titleWords {
+
+		if str == w || strings.HasPrefix(str, w+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDates() parses 'str' returning a `Dates` instance. If 'str' can not be parsed a `Dates` instance with
+// only its 'Raw' property is returned.
+func parseDates(str string) *Dates {
+
+	d, err := ParseDates(str)
+
+	if err != nil {
+		return &Dates{
+			Raw: strings.TrimSpace(str),
+		}
+	}
+
+	return d
+}
+
+// splitDates() splits 'str', which starts with the dates portion of a personal name heading, in to the dates and
+// (optional) work title portions.
+func splitDates(str string) (string, string) {
+
+	offset := 0
+
+	for {
+
+		idx := strings.Index(str[offset:], ". ")
+
+		if idx == -1 {
+			return str, ""
+		}
+
+		idx = offset + idx
+		prefix := str[0:idx]
+
+		if endsWithDate(prefix) {
+			return prefix, str[idx+2:]
+		}
+
+		offset = idx + 2
+	}
+}
+
+// splitWork() splits 'str', a name/title heading without dates, in to the name and work title portions. The
+// work title is assumed to begin after the first full stop which does not follow an initial or abbreviation.
+func splitWork(str string) (string, string) {
+
+	offset := 0
+
+	for {
+
+		idx := strings.Index(str[offset:], ". ")
+
+		if idx == -1 {
+			return str, ""
+		}
+
+		idx = offset + idx
+		prefix := str[0:idx]
+
+		words := strings.FieldsFunc(prefix, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+
+		if len(words) > 0 && len([]rune(words[len(words)-1])) > 2 {
+			return prefix, str[idx+2:]
+		}
+
+		offset = idx + 2
+	}
+}
+
+// endsWithDate() returns a boolean value indicating whether 'str' ends with a year, century or era qualifier.
+func endsWithDate(str string) bool {
+
+	if str == "" {
+		return false
+	}
+
+	last := str[len(str)-1]
+
+	if (last >= '0' && last <= '9') || last == '?' || last == '-' {
+		return true
+	}
+
+	for _, suffix := range []string{"cent", "century", "B.C", "BC", "BCE"} {
+
+		if strings.HasSuffix(str, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package names
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestParseString(t *testing.T) {
+
+	tests := map[string]*Name{
+		"Neefe, Christian Gottlob, 1748-1798": {
+			Family: "Neefe",
+			Given:  "Christian Gottlob",
+		},
+		"Brockmann, Lester C.": {
+			Family: "Brockmann",
+			Given:  "Lester C.",
+		},
+		"Smith, J. R. (John Robert), 1900-": {
+			Family:     "Smith",
+			Given:      "J. R.",
+			FullerForm: "John Robert",
+		},
+		"Louis XIV, King of France, 1638-1715": {
+			Given:      "Louis",
+			Numeration: "XIV",
+			Titles:     []string{"King of France"},
+		},
+		"John, King of England, 1167-1216": {
+			Given:  "John",
+			Titles: []string{"King of England"},
+		},
+		"King, Martin Luther, Jr., 1929-1968": {
+			Family: "King",
+			Given:  "Martin Luther",
+			Titles: []string{"Jr."},
+		},
+		"Homer": {
+			Given: "Homer",
+		},
+	}
+
+	for str, expected := range tests {
+
+		n, err := ParseString(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		compareNames(t, str, n, expected)
+
+		if n.Work != "" {
+			t.Fatalf("Unexpected work for '%s': %s", str, n.Work)
+		}
+	}
+
+	_, err := ParseString(" ")
+
+	if err == nil {
+		t.Fatalf("Expected empty string to fail")
+	}
+}
+
+func TestParseStringWithWork(t *testing.T) {
+
+	str := "Neefe, Christian Gottlob, 1748-1798. Veränderungen über den Priestermarsch aus Mozarts Zauberflöte"
+
+	n, err := ParseString(str)
+
+	if err != nil {
+		t.Fatalf("Failed to parse '%s', %v", str, err)
+	}
+
+	compareNames(t, str, n, &Name{Family: "Neefe", Given: "Christian Gottlob"})
+
+	if n.Work != "Veränderungen über den Priestermarsch aus Mozarts Zauberflöte" {
+		t.Fatalf("Unexpected work: %s", n.Work)
+	}
+
+	if n.Dates == nil || n.Dates.Birth.EDTF() != "1748" || n.Dates.Death.EDTF() != "1798" {
+		t.Fatalf("Unexpected dates: %v", n.Dates)
+	}
+
+	str = "Aristotle, 384-322 B.C. Nicomachean ethics"
+
+	n, err = ParseString(str)
+
+	if err != nil {
+		t.Fatalf("Failed to parse '%s', %v", str, err)
+	}
+
+	if n.Given != "Aristotle" || n.Work != "Nicomachean ethics" || n.Dates.Birth.EDTF() != "-0383" {
+		t.Fatalf("Unexpected name for '%s': %v", str, n)
+	}
+}
+
+func TestParse(t *testing.T) {
+
+	// Name/title heading with an element list
+
+	body := `{"@context": {"about": "http://id.loc.gov/authorities/names/no2001099999"}, "@graph": [
+{"@id": "http://id.loc.gov/authorities/names/no2001099999", "@type": ["madsrdf:Authority", "madsrdf:NameTitle"], "madsrdf:authoritativeLabel": "Neefe, Christian Gottlob, 1748-1798. Veränderungen über den Priestermarsch aus Mozarts Zauberflöte", "madsrdf:elementList": {"@list": [{"@id": "_:N1"}, {"@id": "_:N2"}, {"@id": "_:N3"}]}},
+{"@id": "_:N1", "@type": "madsrdf:FullNameElement", "madsrdf:elementValue": "Neefe, Christian Gottlob,"},
+{"@id": "_:N2", "@type": "madsrdf:DateNameElement", "madsrdf:elementValue": "1748-1798."},
+{"@id": "_:N3", "@type": "madsrdf:TitleElement", "madsrdf:elementValue": "Veränderungen über den Priestermarsch aus Mozarts Zauberflöte"}
+]}`
+
+	a, err := authority.ParseRecord([]byte(body))
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	n, err := Parse(a)
+
+	if err != nil {
+		t.Fatalf("Failed to parse name, %v", err)
+	}
+
+	compareNames(t, a.Label(), n, &Name{Family: "Neefe", Given: "Christian Gottlob"})
+
+	if n.Work != "Veränderungen über den Priestermarsch aus Mozarts Zauberflöte" {
+		t.Fatalf("Unexpected work: %s", n.Work)
+	}
+
+	if n.Dates == nil || n.Dates.Birth.EDTF() != "1748" || n.Dates.Death.EDTF() != "1798" {
+		t.Fatalf("Unexpected dates: %v", n.Dates)
+	}
+
+	// Name/title heading without an element list or dates
+
+	body = `{"@graph": [{"@id": "http://id.loc.gov/authorities/names/n99999999", "@type": ["madsrdf:Authority", "madsrdf:NameTitle"], "madsrdf:authoritativeLabel": "Smith, John. Collected poems"}]}`
+
+	a, err = authority.ParseRecord([]byte(body))
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	n, err = Parse(a)
+
+	if err != nil {
+		t.Fatalf("Failed to parse name, %v", err)
+	}
+
+	compareNames(t, a.Label(), n, &Name{Family: "Smith", Given: "John"})
+
+	if n.Work != "Collected poems" {
+		t.Fatalf("Unexpected work: %s", n.Work)
+	}
+
+	// Family name heading
+
+	body = `{"@graph": [{"@id": "http://id.loc.gov/authorities/names/n2004004999", "@type": ["madsrdf:Authority", "madsrdf:FamilyName"], "madsrdf:authoritativeLabel": "Śreshṭha family"}]}`
+
+	a, err = authority.ParseRecord([]byte(body))
+
+	if err != nil {
+		t.Fatalf("Failed to parse record, %v", err)
+	}
+
+	n, err = Parse(a)
+
+	if err != nil {
+		t.Fatalf("Failed to parse name, %v", err)
+	}
+
+	compareNames(t, a.Label(), n, &Name{Family: "Śreshṭha family"})
+}
+
+// compareNames() compares the family name, given name, fuller form, numeration and titles of 'n' against 'expected'.
+func compareNames(t *testing.T, str string, n *Name, expected *Name) {
+
+	if n.Family != expected.Family {
+		t.Fatalf("Unexpected family name for '%s': '%s' (expected '%s')", str, n.Family, expected.Family)
+	}
+
+	if n.Given != expected.Given {
+		t.Fatalf("Unexpected given name for '%s': '%s' (expected '%s')", str, n.Given, expected.Given)
+	}
+
+	if n.FullerForm != expected.FullerForm {
+		t.Fatalf("Unexpected fuller form for '%s': '%s' (expected '%s')", str, n.FullerForm, expected.FullerForm)
+	}
+
+	if n.Numeration != expected.Numeration {
+		t.Fatalf("Unexpected numeration for '%s': '%s' (expected '%s')", str, n.Numeration, expected.Numeration)
+	}
+
+	if strings.Join(n.Titles, ";") != strings.Join(expected.Titles, ";") {
+		t.Fatalf("Unexpected titles for '%s': %v (expected %v)", str, n.Titles, expected.Titles)
+	}
+}
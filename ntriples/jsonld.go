@@ -0,0 +1,289 @@
+package ntriples
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RDF vocabulary IRIs.
+const (
+	rdfType  string = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	rdfFirst string = "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"
+	rdfRest  string = "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"
+	rdfNil   string = "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"
+	// xsdString is the datatype of plain literals, which are rendered as simple strings.
+	xsdString string = "http://www.w3.org/2001/XMLSchema#string"
+)
+
+// Prefixes is the map of prefixes, and their namespaces, used to compact predicates, types and datatypes. These are
+// the same prefixes used by the id.loc.gov `*.both.ndjson` files.
+var Prefixes = map[string]string{
+	"bflc":        "http://id.loc.gov/ontologies/bflc/",
+	"cs":          "http://purl.org/vocab/changeset/schema#",
+	"identifiers": "http://id.loc.gov/vocabulary/identifiers/",
+	"lcc":         "http://id.loc.gov/ontologies/lcc#",
+	"madsrdf":     "http://www.loc.gov/mads/rdf/v1#",
+	"owl":         "http://www.w3.org/2002/07/owl#",
+	"rdf":         "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":        "http://www.w3.org/2000/01/rdf-schema#",
+	"ri":          "http://id.loc.gov/ontologies/RecordInfo#",
+	"skos":        "http://www.w3.org/2004/02/skos/core#",
+	"skosxl":      "http://www.w3.org/2008/05/skos-xl#",
+	"xsd":         "http://www.w3.org/2001/XMLSchema#",
+}
+
+// ToJSONLD() returns the JSON-LD representation of 'rec', in the same shape as the records in the id.loc.gov
+// `*.both.ndjson` files: a "@graph" of nodes, in the order their subjects first appear in 'rec', with compact ("prefix:name")
+// predicates, types and datatypes and an "about" property in the "@context" identifying the resource the record describes.
+// Blank nodes forming RDF collections (`rdf:first` / `rdf:rest`) are replaced by "@list" values. Properties with a single
+// value are rendered as a scalar rather than a list.
+func ToJSONLD(rec *Record) ([]byte, error) {
+
+	if len(rec.Triples) == 0 {
+		return nil, fmt.Errorf("Record has no triples")
+	}
+
+	order := make([]string, 0)
+	properties := make(map[string]map[string][]*Term)
+	types := make(map[string][]string)
+	lists := make(map[string]*listNode)
+
+	for _, t := range rec.Triples {
+
+		subject := t.Subject.Value
+
+		_, seen := properties[subject]
+
+		if !seen {
+			order = append(order, subject)
+			properties[subject] = make(map[string][]*Term)
+		}
+
+		if t.Subject.Type == BlankNode {
+
+			switch t.Predicate.Value {
+			case rdfFirst:
+				listNodeFor(lists, subject).first = t.Object
+				continue
+			case rdfRest:
+				listNodeFor(lists, subject).rest = t.Object
+				continue
+			}
+		}
+
+		if t.Predicate.Value == rdfType && t.Object.Type != Literal {
+			types[subject] = appendUnique(types[subject], compact(t.Object.Value))
+			continue
+		}
+
+		predicate := compact(t.Predicate.Value)
+		properties[subject][predicate] = append(properties[subject][predicate], t.Object)
+	}
+
+	graph := make([]map[string]interface{}, 0)
+
+	for _, subject := range order {
+
+		_, is_list := lists[subject]
+
+		if is_list && len(properties[subject]) == 0 && len(types[subject]) == 0 {
+			continue
+		}
+
+		n := map[string]interface{}{
+			"@id": subject,
+		}
+
+		switch len(types[subject]) {
+		case 0:
+			// pass
+		case 1:
+			n["@type"] = types[subject][0]
+		default:
+			n["@type"] = types[subject]
+		}
+
+		for predicate, objects := range properties[subject] {
+
+			values := make([]interface{}, 0)
+			seen := make(map[string]bool)
+
+			for _, o := range objects {
+
+				v, err := value(o, lists)
+
+				if err != nil {
+					return nil, fmt.Errorf("Invalid value for %s of %s, %w", predicate, subject, err)
+				}
+
+				enc, _ := json.Marshal(v)
+
+				if seen[string(enc)] {
+					continue
+				}
+
+				seen[string(enc)] = true
+				values = append(values, v)
+			}
+
+			if len(values) == 1 {
+				n[predicate] = values[0]
+			} else {
+				n[predicate] = values
+			}
+		}
+
+		graph = append(graph, n)
+	}
+
+	ctx := map[string]string{
+		"about": rec.About,
+	}
+
+	for prefix, ns := range Prefixes {
+		ctx[prefix] = ns
+	}
+
+	doc := map[string]interface{}{
+		"@context": ctx,
+		"@graph":   graph,
+	}
+
+	enc, err := json.Marshal(doc)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal record %s, %w", rec.About, err)
+	}
+
+	return enc, nil
+}
+
+// type listNode is an internal structure describing a blank node in an RDF collection.
+type listNode struct {
+	first *Term
+	rest  *Term
+}
+
+// listNodeFor() returns the `listNode` instance for 'subject' in 'lists', creating it if necessary.
+func listNodeFor(lists map[string]*listNode, subject string) *listNode {
+
+	n, ok := lists[subject]
+
+	if !ok {
+		n = &listNode{}
+		lists[subject] = n
+	}
+
+	return n
+}
+
+// value() returns the JSON-LD value for 'o'. Blank nodes which are the head of an RDF collection in 'lists' are
+// returned as a "@list" value.
+func value(o *Term, lists map[string]*listNode) (interface{}, error) {
+
+	switch o.Type {
+	case Literal:
+
+		switch {
+		case o.Language != "":
+			return map[string]string{"@language": o.Language, "@value": o.Value}, nil
+		case o.Datatype != "" && o.Datatype != xsdString:
+			return map[string]string{"@type": compact(o.Datatype), "@value": o.Value}, nil
+		default:
+			return o.Value, nil
+		}
+
+	case IRI:
+
+		if o.Value == rdfNil {
+			return map[string]interface{}{"@list": []interface{}{}}, nil
+		}
+
+		return map[string]string{"@id": o.Value}, nil
+	}
+
+	_, is_list := lists[o.Value]
+
+	if !is_list {
+		return map[string]string{"@id": o.Value}, nil
+	}
+
+	items := make([]interface{}, 0)
+	visited := make(map[string]bool)
+
+	node := o
+
+	for node.Type == BlankNode {
+
+		if visited[node.Value] {
+			return nil, fmt.Errorf("Circular list at %s", node.Value)
+		}
+
+		visited[node.Value] = true
+
+		n, ok := lists[node.Value]
+
+		if !ok || n.first == nil || n.rest == nil {
+			return nil, fmt.Errorf("Incomplete list node %s", node.Value)
+		}
+
+		v, err := value(n.first, lists)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, v)
+		node = n.rest
+	}
+
+	if node.Type != IRI || node.Value != rdfNil {
+		return nil, fmt.Errorf("List is not terminated by rdf:nil")
+	}
+
+	return map[string]interface{}{"@list": items}, nil
+}
+
+// compact() returns 'iri' in compact ("prefix:name") form if its namespace is one of `Prefixes`, otherwise 'iri' is
+// returned unchanged. When more than one namespace matches the longest one is used.
+func compact(iri string) string {
+
+	match := ""
+	prefixes := make([]string, 0, len(Prefixes))
+
+	for prefix := range Prefixes {
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+
+		ns := Prefixes[prefix]
+
+		if strings.HasPrefix(iri, ns) && len(ns) > len(Prefixes[match]) && len(iri) > len(ns) {
+			match = prefix
+		}
+	}
+
+	if match == "" {
+		return iri
+	}
+
+	return match + ":" + strings.TrimPrefix(iri, Prefixes[match])
+}
+
+// appendUnique() appends 'v' to 'values' if it is not already present.
+func appendUnique(values []string, v string) []string {
+
+	for _, existing := range values {
+
+		if existing == v {
+			return values
+		}
+	}
+
+	return append(values, v)
+}
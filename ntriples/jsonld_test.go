@@ -0,0 +1,97 @@
+package ntriples
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestToJSONLD(t *testing.T) {
+
+	doc := `<http://id.loc.gov/authorities/subjects/sh1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.loc.gov/mads/rdf/v1#Topic> .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.loc.gov/mads/rdf/v1#Authority> .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "One"@en .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "One"@en .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#isMemberOfMADSScheme> <http://id.loc.gov/authorities/subjects> .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#elementList> _:l1 .
+<http://id.loc.gov/authorities/subjects/sh1> <http://example.com/custom> "plain"^^<http://www.w3.org/2001/XMLSchema#string> .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> _:e1 .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:l2 .
+_:l2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "literal" .
+_:l2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .
+_:e1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.loc.gov/mads/rdf/v1#TopicElement> .
+_:e1 <http://id.loc.gov/ontologies/RecordInfo#recordChangeDate> "1986-02-11T00:00:00"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+`
+
+	rr, err := NewRecordReader(strings.NewReader(doc), GroupCluster)
+
+	if err != nil {
+		t.Fatalf("Failed to create record reader, %v", err)
+	}
+
+	rec, err := rr.Next()
+
+	if err != nil {
+		t.Fatalf("Failed to read record, %v", err)
+	}
+
+	body, err := ToJSONLD(rec)
+
+	if err != nil {
+		t.Fatalf("Failed to convert record, %v", err)
+	}
+
+	tests := map[string]string{
+		"@context.about":                      "http://id.loc.gov/authorities/subjects/sh1",
+		"@context.madsrdf":                    "http://www.loc.gov/mads/rdf/v1#",
+		"@graph.#":                            "2",
+		"@graph.0.@id":                        "http://id.loc.gov/authorities/subjects/sh1",
+		"@graph.0.@type":                      `["madsrdf:Topic","madsrdf:Authority"]`,
+		"@graph.0.madsrdf:authoritativeLabel": `{"@language":"en","@value":"One"}`,
+		"@graph.0.madsrdf:isMemberOfMADSScheme.@id":             "http://id.loc.gov/authorities/subjects",
+		"@graph.0.madsrdf:elementList.@list.#":                  "2",
+		"@graph.0.madsrdf:elementList.@list.0.@id":              "_:e1",
+		"@graph.0.madsrdf:elementList.@list.1":                  "literal",
+		"@graph.0." + gjson.Escape("http://example.com/custom"): "plain",
+		"@graph.1.@id":                        "_:e1",
+		"@graph.1.@type":                      "madsrdf:TopicElement",
+		"@graph.1.ri:recordChangeDate.@type":  "xsd:dateTime",
+		"@graph.1.ri:recordChangeDate.@value": "1986-02-11T00:00:00",
+	}
+
+	for path, expected := range tests {
+
+		rsp := gjson.GetBytes(body, path)
+
+		if rsp.String() != expected {
+			t.Fatalf("Unexpected value for %s: %s", path, rsp.String())
+		}
+	}
+}
+
+func TestToJSONLDInvalidList(t *testing.T) {
+
+	doc := `<http://example.com/a> <http://www.loc.gov/mads/rdf/v1#elementList> _:l1 .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "one" .
+_:l1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:l1 .
+`
+
+	rr, err := NewRecordReader(strings.NewReader(doc), GroupSubject)
+
+	if err != nil {
+		t.Fatalf("Failed to create record reader, %v", err)
+	}
+
+	rec, err := rr.Next()
+
+	if err != nil {
+		t.Fatalf("Failed to read record, %v", err)
+	}
+
+	_, err = ToJSONLD(rec)
+
+	if err == nil {
+		t.Fatalf("Expected circular list to fail")
+	}
+}
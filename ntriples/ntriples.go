@@ -0,0 +1,401 @@
+// Package ntriples provides methods for reading RDF N-Triples documents, for example the id.loc.gov `*.nt` dumps,
+// grouping their triples in to records and converting those records in to the same JSON-LD representation used by
+// the id.loc.gov `*.both.ndjson` files so that they can be processed by the `authority` package.
+package ntriples
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Term types.
+const (
+	// IRI indicates a term that is an IRI, for example "<http://id.loc.gov/authorities/subjects/sh85016999>".
+	IRI int = iota
+	// BlankNode indicates a term that is a blank node, for example "_:b0".
+	BlankNode
+	// Literal indicates a term that is a (optionally language-tagged or typed) literal, for example `"Broadband amplifiers"@en`.
+	Literal
+)
+
+// type Term is a subject, predicate or object of a triple.
+type Term struct {
+	// Type is the type of term, one of `IRI`, `BlankNode` or `Literal`.
+	Type int
+	// Value is the IRI, blank node label (including the "_:" prefix) or literal value of the term.
+	Value string
+	// Language is the language tag of a literal term, if present.
+	Language string
+	// Datatype is the datatype IRI of a literal term, if present.
+	Datatype string
+}
+
+// type Triple is an RDF triple.
+type Triple struct {
+	// Subject is the subject of the triple, an `IRI` or `BlankNode` term.
+	Subject *Term
+	// Predicate is the predicate of the triple, an `IRI` term.
+	Predicate *Term
+	// Object is the object of the triple.
+	Object *Term
+}
+
+// type Decoder reads triples from an N-Triples document, one line at a time.
+type Decoder struct {
+	scanner *bufio.Scanner
+	line    int
+	// offset is the byte offset of the start of the line that was last read.
+	offset int64
+	// next_offset is the byte offset of the start of the next line.
+	next_offset int64
+}
+
+// maxLineLength is the maximum length of a line in an N-Triples document.
+const maxLineLength int = 16 * 1024 * 1024
+
+// NewDecoder() returns a new `Decoder` instance for reading triples from 'r'.
+func NewDecoder(r io.Reader) *Decoder {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	d := &Decoder{
+		scanner: scanner,
+	}
+
+	// Wrap bufio.ScanLines in order to keep track of the byte offset of each line
+
+	scanner.Split(func(data []byte, at_eof bool) (int, []byte, error) {
+
+		advance, token, err := bufio.ScanLines(data, at_eof)
+
+		if token != nil {
+			d.offset = d.next_offset
+		}
+
+		d.next_offset += int64(advance)
+		return advance, token, err
+	})
+
+	return d
+}
+
+// Next() returns the next triple in the document skipping blank lines and comments. It returns `io.EOF` when there
+// are no more triples.
+func (d *Decoder) Next() (*Triple, error) {
+
+	for d.scanner.Scan() {
+
+		d.line += 1
+
+		line := strings.TrimSpace(d.scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		t, err := ParseTriple(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse line %d, %w", d.line, err)
+		}
+
+		return t, nil
+	}
+
+	err := d.scanner.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read line %d, %w", d.line+1, err)
+	}
+
+	return nil, io.EOF
+}
+
+// Line() returns the (1-based) number of the line that was last read.
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+// InputOffset() returns the byte offset of the start of the line that was last read.
+func (d *Decoder) InputOffset() int64 {
+	return d.offset
+}
+
+// ParseTriple() parses 'line', a single N-Triples statement, in to a `Triple` instance.
+func ParseTriple(line string) (*Triple, error) {
+
+	p := &parser{
+		input: line,
+	}
+
+	subject, err := p.term()
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid subject, %w", err)
+	}
+
+	if subject.Type == Literal {
+		return nil, fmt.Errorf("Invalid subject, literals are not allowed")
+	}
+
+	predicate, err := p.term()
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid predicate, %w", err)
+	}
+
+	if predicate.Type != IRI {
+		return nil, fmt.Errorf("Invalid predicate, must be an IRI")
+	}
+
+	object, err := p.term()
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid object, %w", err)
+	}
+
+	p.skipSpace()
+
+	if !strings.HasPrefix(p.input[p.pos:], ".") {
+		return nil, fmt.Errorf("Missing '.' at end of statement")
+	}
+
+	p.pos += 1
+	p.skipSpace()
+
+	rest := p.input[p.pos:]
+
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return nil, fmt.Errorf("Unexpected characters after end of statement")
+	}
+
+	t := &Triple{
+		Subject:   subject,
+		Predicate: predicate,
+		Object:    object,
+	}
+
+	return t, nil
+}
+
+// type parser is an internal structure used to parse the terms in an N-Triples statement.
+type parser struct {
+	input string
+	pos   int
+}
+
+// skipSpace() advances 'p' past any spaces or tabs.
+func (p *parser) skipSpace() {
+
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos += 1
+	}
+}
+
+// term() parses the next term.
+func (p *parser) term() (*Term, error) {
+
+	p.skipSpace()
+
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("Unexpected end of statement")
+	}
+
+	switch {
+	case p.input[p.pos] == '<':
+
+		iri, err := p.iri()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Term{Type: IRI, Value: iri}, nil
+
+	case strings.HasPrefix(p.input[p.pos:], "_:"):
+
+		start := p.pos
+
+		for p.pos < len(p.input) && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			p.pos += 1
+		}
+
+		label := strings.TrimSuffix(p.input[start:p.pos], ".")
+		p.pos = start + len(label)
+
+		if len(label) <= 2 {
+			return nil, fmt.Errorf("Empty blank node label")
+		}
+
+		return &Term{Type: BlankNode, Value: label}, nil
+
+	case p.input[p.pos] == '"':
+		return p.literal()
+	}
+
+	return nil, fmt.Errorf("Unexpected character '%c' at position %d", p.input[p.pos], p.pos)
+}
+
+// iri() parses an IRI reference, enclosed in angle brackets, and returns its (unescaped) value.
+func (p *parser) iri() (string, error) {
+
+	end := strings.IndexByte(p.input[p.pos:], '>')
+
+	if end == -1 {
+		return "", fmt.Errorf("Unterminated IRI")
+	}
+
+	raw := p.input[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	if !strings.Contains(raw, `\`) {
+		return raw, nil
+	}
+
+	return unescape(raw)
+}
+
+// literal() parses a literal, including any language tag or datatype.
+func (p *parser) literal() (*Term, error) {
+
+	p.pos += 1
+	start := p.pos
+	escaped := false
+
+	for p.pos < len(p.input) {
+
+		c := p.input[p.pos]
+
+		if c == '\\' {
+			escaped = true
+			p.pos += 2
+			continue
+		}
+
+		if c == '"' {
+			break
+		}
+
+		p.pos += 1
+	}
+
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("Unterminated literal")
+	}
+
+	value := p.input[start:p.pos]
+	p.pos += 1
+
+	if escaped {
+
+		v, err := unescape(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value = v
+	}
+
+	t := &Term{
+		Type:  Literal,
+		Value: value,
+	}
+
+	switch {
+	case strings.HasPrefix(p.input[p.pos:], "@"):
+
+		start := p.pos + 1
+
+		for p.pos < len(p.input) && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			p.pos += 1
+		}
+
+		t.Language = strings.TrimSuffix(p.input[start:p.pos], ".")
+		p.pos = start + len(t.Language)
+
+	case strings.HasPrefix(p.input[p.pos:], "^^"):
+
+		p.pos += 2
+
+		if p.pos >= len(p.input) || p.input[p.pos] != '<' {
+			return nil, fmt.Errorf("Invalid datatype")
+		}
+
+		dt, err := p.iri()
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid datatype, %w", err)
+		}
+
+		t.Datatype = dt
+	}
+
+	return t, nil
+}
+
+// unescape() returns 's' with N-Triples escape sequences ("\t", "\n", "\"", "\uXXXX", "\UXXXXXXXX", etc.) replaced.
+func unescape(s string) (string, error) {
+
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+
+		c := s[i]
+
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("Invalid escape sequence at end of string")
+		}
+
+		i += 1
+
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case '"', '\'', '\\':
+			sb.WriteByte(s[i])
+		case 'u', 'U':
+
+			size := 4
+
+			if s[i] == 'U' {
+				size = 8
+			}
+
+			if i+1+size > len(s) {
+				return "", fmt.Errorf("Invalid escape sequence '\\%s'", s[i:])
+			}
+
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+
+			if err != nil {
+				return "", fmt.Errorf("Invalid escape sequence '\\%s', %w", s[i:i+1+size], err)
+			}
+
+			sb.WriteRune(rune(code))
+			i += size
+
+		default:
+			return "", fmt.Errorf("Invalid escape sequence '\\%c'", s[i])
+		}
+	}
+
+	return sb.String(), nil
+}
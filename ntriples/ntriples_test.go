@@ -0,0 +1,113 @@
+package ntriples
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseTriple(t *testing.T) {
+
+	tests := map[string]*Triple{
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Broadband amplifiers"@en .`: {
+			Subject:   &Term{Type: IRI, Value: "http://id.loc.gov/authorities/subjects/sh85016999"},
+			Predicate: &Term{Type: IRI, Value: "http://www.loc.gov/mads/rdf/v1#authoritativeLabel"},
+			Object:    &Term{Type: Literal, Value: "Broadband amplifiers", Language: "en"},
+		},
+		`_:b0 <http://id.loc.gov/ontologies/RecordInfo#recordChangeDate> "1986-02-11T00:00:00"^^<http://www.w3.org/2001/XMLSchema#dateTime>.`: {
+			Subject:   &Term{Type: BlankNode, Value: "_:b0"},
+			Predicate: &Term{Type: IRI, Value: "http://id.loc.gov/ontologies/RecordInfo#recordChangeDate"},
+			Object:    &Term{Type: Literal, Value: "1986-02-11T00:00:00", Datatype: "http://www.w3.org/2001/XMLSchema#dateTime"},
+		},
+		`_:b0 <http://www.w3.org/2004/02/skos/core#note> "Dvořák said \"hello\"\n\\" . # comment`: {
+			Subject:   &Term{Type: BlankNode, Value: "_:b0"},
+			Predicate: &Term{Type: IRI, Value: "http://www.w3.org/2004/02/skos/core#note"},
+			Object:    &Term{Type: Literal, Value: "Dvořák said \"hello\"\n\\"},
+		},
+		`<http://example.com/a>	<http://example.com/b>	_:b1	.`: {
+			Subject:   &Term{Type: IRI, Value: "http://example.com/a"},
+			Predicate: &Term{Type: IRI, Value: "http://example.com/b"},
+			Object:    &Term{Type: BlankNode, Value: "_:b1"},
+		},
+	}
+
+	for line, expected := range tests {
+
+		tr, err := ParseTriple(line)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", line, err)
+		}
+
+		for label, pair := range map[string][2]*Term{
+			"subject":   {tr.Subject, expected.Subject},
+			"predicate": {tr.Predicate, expected.Predicate},
+			"object":    {tr.Object, expected.Object},
+		} {
+
+			if *pair[0] != *pair[1] {
+				t.Fatalf("Unexpected %s for '%s': %v", label, line, pair[0])
+			}
+		}
+	}
+}
+
+func TestParseTripleInvalid(t *testing.T) {
+
+	tests := []string{
+		`"literal" <http://example.com/b> <http://example.com/c> .`,
+		`<http://example.com/a> _:b0 <http://example.com/c> .`,
+		`<http://example.com/a> <http://example.com/b> <http://example.com/c>`,
+		`<http://example.com/a> <http://example.com/b> "unterminated .`,
+		`<http://example.com/a> <http://example.com/b> "bad \q escape" .`,
+		`<http://example.com/a> <http://example.com/b> "short \u00" .`,
+		`<http://example.com/a> <http://example.com/b> <http://example.com/c> . extra`,
+		`<http://example.com/a> <http://example.com/b`,
+	}
+
+	for _, line := range tests {
+
+		_, err := ParseTriple(line)
+
+		if err == nil {
+			t.Fatalf("Expected '%s' to fail", line)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+
+	doc := `# A comment
+
+<http://example.com/a> <http://example.com/b> "one" .
+<http://example.com/a> <http://example.com/b> "two" .
+<http://example.com/a> <http://example.com/b> broken .
+`
+
+	dec := NewDecoder(strings.NewReader(doc))
+
+	for _, expected := range []string{"one", "two"} {
+
+		tr, err := dec.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to read triple, %v", err)
+		}
+
+		if tr.Object.Value != expected {
+			t.Fatalf("Unexpected object '%s'", tr.Object.Value)
+		}
+	}
+
+	_, err := dec.Next()
+
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("Expected error for line 5, got %v", err)
+	}
+
+	_, err = dec.Next()
+
+	if err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
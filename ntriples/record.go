@@ -0,0 +1,365 @@
+package ntriples
+
+import (
+	"fmt"
+	"io"
+)
+
+// Grouping modes used to determine which triples belong to the same record.
+const (
+	// GroupSubject indicates that each named (non-blank) subject, and the blank nodes that follow it, is a record.
+	GroupSubject string = "subject"
+	// GroupCluster indicates that each authority, and the resources and blank nodes serialized alongside it, is a record.
+	GroupCluster string = "cluster"
+)
+
+// primaryPredicates is the list of predicates that identify the primary resource (the authority or concept itself,
+// rather than a resource it references) in a cluster of triples.
+var primaryPredicates = map[string]bool{
+	"http://www.loc.gov/mads/rdf/v1#isMemberOfMADSScheme": true,
+	"http://www.w3.org/2004/02/skos/core#inScheme":        true,
+}
+
+// type Record is a group of triples describing a single resource.
+type Record struct {
+	// About is the IRI of the resource the record describes.
+	About string
+	// Triples is the list of triples, in document order, in the record.
+	Triples []*Triple
+	// Line is the (1-based) line number of the first triple in the record.
+	Line int
+	// Offset is the byte offset of the start of the first triple in the record.
+	Offset int64
+}
+
+// type run is an internal structure describing a named subject, its triples and the triples of the blank nodes that
+// immediately follow it.
+type run struct {
+	// subject is the IRI of the named subject. Runs of blank nodes at the start of a document do not have a subject.
+	subject string
+	// triples is the list of triples in the run.
+	triples []*Triple
+	// primary is a boolean flag indicating that the subject is the primary resource of a record.
+	primary bool
+	// references is the set of IRIs referenced (as objects) by the triples in the run.
+	references map[string]bool
+	// line is the (1-based) line number of the first triple in the run.
+	line int
+	// offset is the byte offset of the start of the first triple in the run.
+	offset int64
+}
+
+// type RecordReader groups the triples read from an N-Triples (or Turtle) document in to records.
+type RecordReader struct {
+	decoder TripleDecoder
+	group   string
+	// next is the first triple of the next run, if it has already been read.
+	next *Triple
+	// next_line is the line number of 'next'.
+	next_line int
+	// next_offset is the byte offset of 'next'.
+	next_offset int64
+	// current is the list of runs in the record currently being assembled.
+	current []*run
+	// pending is the list of runs following the primary resource of 'current' which have not yet been assigned to a record.
+	pending []*run
+	// eof is a boolean flag indicating that the end of the document has been reached.
+	eof bool
+}
+
+// NewRecordReader() returns a new `RecordReader` instance for reading records from 'r' using the 'group' grouping mode
+// (`GroupSubject` or `GroupCluster`).
+//
+// In `GroupSubject` mode each named subject, along with any blank node subjects that immediately follow it, is a record.
+// This is suitable for documents where the triples for each subject are contiguous and blank nodes are serialized after
+// the subject that references them.
+//
+// In `GroupCluster` mode each record is the cluster of triples serialized for a single authority, as is the case for the
+// id.loc.gov dumps: the authority itself (identified by a `madsrdf:isMemberOfMADSScheme` or `skos:inScheme` triple), its
+// blank nodes and the other resources (for example components, broader headings or external authorities) whose labels
+// are included alongside it. Resources which appear between two authorities are assigned to the second authority if
+// they reference, or are referenced by, it; otherwise they are assigned to the first.
+func NewRecordReader(r io.Reader, group string) (*RecordReader, error) {
+	return NewRecordReaderWithDecoder(NewDecoder(r), group)
+}
+
+// NewRecordReaderWithDecoder() returns a new `RecordReader` instance for reading records from the triples returned by
+// 'dec', for example a `TurtleDecoder` instance, using the 'group' grouping mode as described in `NewRecordReader`.
+func NewRecordReaderWithDecoder(dec TripleDecoder, group string) (*RecordReader, error) {
+
+	switch group {
+	case GroupSubject, GroupCluster:
+		// pass
+	default:
+		return nil, fmt.Errorf("Invalid grouping mode '%s'", group)
+	}
+
+	rr := &RecordReader{
+		decoder: dec,
+		group:   group,
+		current: make([]*run, 0),
+		pending: make([]*run, 0),
+	}
+
+	return rr, nil
+}
+
+// Next() returns the next record in the document. It returns `io.EOF` when there are no more records.
+func (rr *RecordReader) Next() (*Record, error) {
+
+	for {
+
+		ru, err := rr.readRun()
+
+		if err == io.EOF {
+
+			if len(rr.current) == 0 && len(rr.pending) == 0 {
+				return nil, io.EOF
+			}
+
+			runs := append(rr.current, rr.pending...)
+
+			rr.current = make([]*run, 0)
+			rr.pending = make([]*run, 0)
+
+			return newRecord(runs), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rr.group == GroupSubject {
+
+			if ru.subject == "" {
+				rr.current = append(rr.current, ru)
+				continue
+			}
+
+			if len(rr.current) == 0 {
+				rr.current = append(rr.current, ru)
+				continue
+			}
+
+			runs := rr.current
+			rr.current = []*run{ru}
+
+			return newRecord(runs), nil
+		}
+
+		if !hasPrimary(rr.current) {
+			rr.current = append(rr.current, ru)
+			continue
+		}
+
+		if !ru.primary {
+			rr.pending = append(rr.pending, ru)
+			continue
+		}
+
+		// Split the pending runs between the current record and the record for the new primary resource
+
+		next := []*run{ru}
+		remaining := rr.pending
+
+		for {
+
+			moved := false
+			keep := make([]*run, 0)
+
+			for _, p := range remaining {
+
+				if isConnected(p, next) {
+					next = append(next, p)
+					moved = true
+				} else {
+					keep = append(keep, p)
+				}
+			}
+
+			remaining = keep
+
+			if !moved {
+				break
+			}
+		}
+
+		runs := append(rr.current, remaining...)
+
+		rr.current = orderRuns(next, rr.pending, ru)
+		rr.pending = make([]*run, 0)
+
+		return newRecord(runs), nil
+	}
+}
+
+// Line() returns the (1-based) number of the line that was last read.
+func (rr *RecordReader) Line() int {
+	return rr.decoder.Line()
+}
+
+// InputOffset() returns the byte offset of the start of the line that was last read.
+func (rr *RecordReader) InputOffset() int64 {
+	return rr.decoder.InputOffset()
+}
+
+// readRun() reads the triples for the next named subject and the blank nodes that follow it.
+func (rr *RecordReader) readRun() (*run, error) {
+
+	if rr.eof {
+		return nil, io.EOF
+	}
+
+	ru := &run{
+		triples:    make([]*Triple, 0),
+		references: make(map[string]bool),
+	}
+
+	for {
+
+		t := rr.next
+		line := rr.next_line
+		offset := rr.next_offset
+
+		rr.next = nil
+
+		if t == nil {
+
+			v, err := rr.decoder.Next()
+
+			if err == io.EOF {
+
+				rr.eof = true
+
+				if len(ru.triples) == 0 {
+					return nil, io.EOF
+				}
+
+				return ru, nil
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			t = v
+			line = rr.decoder.Line()
+			offset = rr.decoder.InputOffset()
+		}
+
+		if t.Subject.Type == IRI {
+
+			switch {
+			case len(ru.triples) == 0:
+				ru.subject = t.Subject.Value
+			case t.Subject.Value != ru.subject:
+				rr.next = t
+				rr.next_line = line
+				rr.next_offset = offset
+				return ru, nil
+			}
+		}
+
+		if len(ru.triples) == 0 {
+			ru.line = line
+			ru.offset = offset
+		}
+
+		ru.triples = append(ru.triples, t)
+
+		if t.Object.Type == IRI {
+			ru.references[t.Object.Value] = true
+		}
+
+		if t.Subject.Type == IRI && primaryPredicates[t.Predicate.Value] {
+			ru.primary = true
+		}
+	}
+}
+
+// hasPrimary() returns a boolean value indicating whether any of 'runs' describe a primary resource.
+func hasPrimary(runs []*run) bool {
+
+	for _, ru := range runs {
+
+		if ru.primary {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isConnected() returns a boolean value indicating whether 'ru' references, or is referenced by, any of 'runs'.
+func isConnected(ru *run, runs []*run) bool {
+
+	for _, other := range runs {
+
+		if ru.subject != "" && other.references[ru.subject] {
+			return true
+		}
+
+		if other.subject != "" && ru.references[other.subject] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orderRuns() returns the runs in 'selected' in the order they appear in 'pending' followed by 'primary'.
+func orderRuns(selected []*run, pending []*run, primary *run) []*run {
+
+	in_selected := make(map[*run]bool)
+
+	for _, ru := range selected {
+		in_selected[ru] = true
+	}
+
+	ordered := make([]*run, 0)
+
+	for _, ru := range pending {
+
+		if in_selected[ru] {
+			ordered = append(ordered, ru)
+		}
+	}
+
+	return append(ordered, primary)
+}
+
+// newRecord() returns a new `Record` instance derived from 'runs'. The record is about the first primary resource in
+// 'runs' or, if there is no primary resource, the first named subject.
+func newRecord(runs []*run) *Record {
+
+	rec := &Record{
+		Triples: make([]*Triple, 0),
+	}
+
+	if len(runs) > 0 {
+		rec.Line = runs[0].line
+		rec.Offset = runs[0].offset
+	}
+
+	for _, ru := range runs {
+
+		if rec.About == "" && ru.primary {
+			rec.About = ru.subject
+		}
+
+		rec.Triples = append(rec.Triples, ru.triples...)
+	}
+
+	if rec.About == "" {
+
+		for _, ru := range runs {
+
+			if ru.subject != "" {
+				rec.About = ru.subject
+				break
+			}
+		}
+	}
+
+	return rec
+}
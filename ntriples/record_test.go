@@ -0,0 +1,107 @@
+package ntriples
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// clusterDoc is a document with two authorities (sh1, sh2), in the layout used by the id.loc.gov dumps: resources
+// referenced by an authority may be serialized before or after it.
+const clusterDoc = `<http://id.loc.gov/authorities/subjects/shB> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Broader" .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "One" .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#isMemberOfMADSScheme> <http://id.loc.gov/authorities/subjects> .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#hasBroaderAuthority> <http://id.loc.gov/authorities/subjects/shB> .
+<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#adminMetadata> _:a1 .
+_:a1 <http://id.loc.gov/ontologies/RecordInfo#recordStatus> "new" .
+<http://www.wikidata.org/entity/Q1> <http://www.loc.gov/mads/rdf/v1#hasExactExternalAuthority> <http://id.loc.gov/authorities/subjects/sh1> .
+<http://id.loc.gov/authorities/subjects/shR> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Related" .
+<http://id.loc.gov/authorities/subjects/sh2> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Two" .
+<http://id.loc.gov/authorities/subjects/sh2> <http://www.loc.gov/mads/rdf/v1#isMemberOfMADSScheme> <http://id.loc.gov/authorities/subjects> .
+<http://id.loc.gov/authorities/subjects/sh2> <http://www.loc.gov/mads/rdf/v1#hasReciprocalAuthority> <http://id.loc.gov/authorities/subjects/shR> .
+`
+
+func TestRecordReaderCluster(t *testing.T) {
+
+	rr, err := NewRecordReader(strings.NewReader(clusterDoc), GroupCluster)
+
+	if err != nil {
+		t.Fatalf("Failed to create record reader, %v", err)
+	}
+
+	tests := []struct {
+		about   string
+		triples int
+	}{
+		{"http://id.loc.gov/authorities/subjects/sh1", 7},
+		{"http://id.loc.gov/authorities/subjects/sh2", 4},
+	}
+
+	for _, expected := range tests {
+
+		rec, err := rr.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to read record, %v", err)
+		}
+
+		if rec.About != expected.about {
+			t.Fatalf("Unexpected record '%s', expected '%s'", rec.About, expected.about)
+		}
+
+		if len(rec.Triples) != expected.triples {
+			t.Fatalf("Unexpected number of triples (%d) for %s", len(rec.Triples), rec.About)
+		}
+	}
+
+	_, err = rr.Next()
+
+	if err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestRecordReaderSubject(t *testing.T) {
+
+	rr, err := NewRecordReader(strings.NewReader(clusterDoc), GroupSubject)
+
+	if err != nil {
+		t.Fatalf("Failed to create record reader, %v", err)
+	}
+
+	expected := []string{
+		"http://id.loc.gov/authorities/subjects/shB",
+		"http://id.loc.gov/authorities/subjects/sh1",
+		"http://www.wikidata.org/entity/Q1",
+		"http://id.loc.gov/authorities/subjects/shR",
+		"http://id.loc.gov/authorities/subjects/sh2",
+	}
+
+	for _, about := range expected {
+
+		rec, err := rr.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to read record, %v", err)
+		}
+
+		if rec.About != about {
+			t.Fatalf("Unexpected record '%s', expected '%s'", rec.About, about)
+		}
+	}
+
+	_, err = rr.Next()
+
+	if err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestRecordReaderInvalidGroup(t *testing.T) {
+
+	_, err := NewRecordReader(strings.NewReader(clusterDoc), "graph")
+
+	if err == nil {
+		t.Fatalf("Expected invalid grouping mode to fail")
+	}
+}
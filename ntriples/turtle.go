@@ -0,0 +1,1118 @@
+package ntriples
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// XML Schema datatypes for Turtle's numeric and boolean literals.
+const (
+	xsdInteger string = "http://www.w3.org/2001/XMLSchema#integer"
+	xsdDecimal string = "http://www.w3.org/2001/XMLSchema#decimal"
+	xsdDouble  string = "http://www.w3.org/2001/XMLSchema#double"
+	xsdBoolean string = "http://www.w3.org/2001/XMLSchema#boolean"
+)
+
+// generatedBlankNodePrefix is the prefix of the labels generated for anonymous ("[]") blank nodes and collections.
+const generatedBlankNodePrefix string = "genid"
+
+// type TripleDecoder is the interface for reading the triples in an RDF document, one triple at a time.
+type TripleDecoder interface {
+	// Next returns the next triple or `io.EOF` when there are no more triples.
+	Next() (*Triple, error)
+	// Line returns the (1-based) line number of the statement that was last read.
+	Line() int
+	// InputOffset returns the byte offset of the start of the statement that was last read.
+	InputOffset() int64
+}
+
+// type TurtleDecoder reads triples from a Turtle document, for example the id.loc.gov `*.ttl` dumps, one statement at
+// a time. Prefixed names, relative IRIs, predicate (";") and object (",") lists, blank node property lists ("[]"),
+// collections ("()") and numeric and boolean literals are expanded in to the triples they abbreviate.
+type TurtleDecoder struct {
+	reader *bufio.Reader
+	// line is the (1-based) line number of the current position of 'reader'.
+	line int
+	// offset is the byte offset of the current position of 'reader'.
+	offset int64
+	// statement_line is the line number of the start of the statement that was last read.
+	statement_line int
+	// statement_offset is the byte offset of the start of the statement that was last read.
+	statement_offset int64
+	// prefixes is the map of prefixes, and their namespaces, declared by the document.
+	prefixes map[string]string
+	// base is the base IRI, declared by the document, used to resolve relative IRIs.
+	base *url.URL
+	// queue is the list of triples in the statement that was last read which have not been returned yet.
+	queue []*Triple
+	// blank_nodes is the number of blank node labels that have been generated.
+	blank_nodes int
+	// err is the error that stopped the decoder, if any. The decoder can not continue past an error.
+	err error
+}
+
+// NewTurtleDecoder() returns a new `TurtleDecoder` instance for reading triples from 'r'.
+func NewTurtleDecoder(r io.Reader) *TurtleDecoder {
+
+	d := &TurtleDecoder{
+		reader:   bufio.NewReader(r),
+		line:     1,
+		prefixes: make(map[string]string),
+		queue:    make([]*Triple, 0),
+	}
+
+	return d
+}
+
+// Next() returns the next triple in the document. The triples abbreviated by a statement are returned in the order
+// they are written, with the triples for a blank node property list or collection following the triple that references
+// it. It returns `io.EOF` when there are no more triples.
+func (d *TurtleDecoder) Next() (*Triple, error) {
+
+	for len(d.queue) == 0 {
+
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		err := d.statement()
+
+		if err != nil {
+
+			if err != io.EOF {
+
+				if errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+
+				err = fmt.Errorf("Failed to parse line %d, %w", d.line, err)
+			}
+
+			d.err = err
+			d.queue = d.queue[:0]
+
+			return nil, err
+		}
+	}
+
+	t := d.queue[0]
+	d.queue = d.queue[1:]
+
+	return t, nil
+}
+
+// Line() returns the (1-based) line number of the start of the statement that was last read.
+func (d *TurtleDecoder) Line() int {
+	return d.statement_line
+}
+
+// InputOffset() returns the byte offset of the start of the statement that was last read.
+func (d *TurtleDecoder) InputOffset() int64 {
+	return d.statement_offset
+}
+
+// statement() reads the next directive or statement, adding the triples it abbreviates to the queue of 'd'. It returns
+// `io.EOF` if there are no more statements.
+func (d *TurtleDecoder) statement() error {
+
+	err := d.skipSpace()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = d.peek()
+
+	if err != nil {
+		return err
+	}
+
+	d.statement_line = d.line
+	d.statement_offset = d.offset
+
+	switch {
+	case d.hasPrefix("@prefix"):
+		return d.prefixDirective(len("@prefix"), true)
+	case d.hasPrefix("@base"):
+		return d.baseDirective(len("@base"), true)
+	case d.hasKeyword("PREFIX"):
+		return d.prefixDirective(len("PREFIX"), false)
+	case d.hasKeyword("BASE"):
+		return d.baseDirective(len("BASE"), false)
+	}
+
+	subject, property_list, err := d.subject()
+
+	if err != nil {
+		return fmt.Errorf("Invalid subject, %w", err)
+	}
+
+	c, err := d.peekAfterSpace()
+
+	if err != nil {
+		return err
+	}
+
+	// A blank node property list may be a statement on its own, for example "[ a skos:Concept ] ."
+
+	if !property_list || c != '.' {
+
+		err := d.predicateObjectList(subject)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.expect('.')
+}
+
+// prefixDirective() reads a "@prefix" (or SPARQL-style "PREFIX") directive whose keyword is 'length' bytes long.
+func (d *TurtleDecoder) prefixDirective(length int, terminated bool) error {
+
+	d.discard(length)
+
+	err := d.skipSpace()
+
+	if err != nil {
+		return err
+	}
+
+	prefix, err := d.name()
+
+	if err != nil {
+		return fmt.Errorf("Invalid prefix, %w", err)
+	}
+
+	err = d.expect(':')
+
+	if err != nil {
+		return fmt.Errorf("Invalid prefix, %w", err)
+	}
+
+	err = d.skipSpace()
+
+	if err != nil {
+		return err
+	}
+
+	iri, err := d.iri()
+
+	if err != nil {
+		return fmt.Errorf("Invalid namespace for prefix '%s', %w", prefix, err)
+	}
+
+	d.prefixes[prefix] = iri
+
+	if !terminated {
+		return nil
+	}
+
+	return d.expect('.')
+}
+
+// baseDirective() reads a "@base" (or SPARQL-style "BASE") directive whose keyword is 'length' bytes long.
+func (d *TurtleDecoder) baseDirective(length int, terminated bool) error {
+
+	d.discard(length)
+
+	err := d.skipSpace()
+
+	if err != nil {
+		return err
+	}
+
+	iri, err := d.iri()
+
+	if err != nil {
+		return fmt.Errorf("Invalid base, %w", err)
+	}
+
+	base, err := url.Parse(iri)
+
+	if err != nil {
+		return fmt.Errorf("Invalid base, %w", err)
+	}
+
+	d.base = base
+
+	if !terminated {
+		return nil
+	}
+
+	return d.expect('.')
+}
+
+// subject() reads the subject of a statement. It returns the subject and a boolean value indicating whether it is a
+// blank node property list.
+func (d *TurtleDecoder) subject() (*Term, bool, error) {
+
+	c, err := d.peek()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch c {
+	case '[':
+
+		t, err := d.blankNodePropertyList()
+		return t, true, err
+
+	case '(':
+
+		t, err := d.collection()
+		return t, false, err
+
+	case '_':
+
+		t, err := d.blankNode()
+		return t, false, err
+	}
+
+	t, err := d.iriTerm()
+	return t, false, err
+}
+
+// predicateObjectList() reads a list of predicates, separated by semicolons, and their objects for 'subject'.
+func (d *TurtleDecoder) predicateObjectList(subject *Term) error {
+
+	for {
+
+		predicate, err := d.verb()
+
+		if err != nil {
+			return fmt.Errorf("Invalid predicate, %w", err)
+		}
+
+		err = d.objectList(subject, predicate)
+
+		if err != nil {
+			return err
+		}
+
+		c, err := d.peekAfterSpace()
+
+		if err != nil {
+			return err
+		}
+
+		if c != ';' {
+			return nil
+		}
+
+		// Predicate lists may contain repeated, or end with, semicolons
+
+		for c == ';' {
+
+			d.discard(1)
+
+			c, err = d.peekAfterSpace()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if c == '.' || c == ']' {
+			return nil
+		}
+	}
+}
+
+// objectList() reads a list of objects, separated by commas, for 'subject' and 'predicate'.
+func (d *TurtleDecoder) objectList(subject *Term, predicate *Term) error {
+
+	for {
+
+		// The triple is added to the queue before any triples its object abbreviates so that the triples for
+		// blank nodes follow the resource that references them, as they do in N-Triples documents
+
+		idx := len(d.queue)
+		d.queue = append(d.queue, nil)
+
+		object, err := d.object()
+
+		if err != nil {
+			return fmt.Errorf("Invalid object, %w", err)
+		}
+
+		d.queue[idx] = &Triple{
+			Subject:   subject,
+			Predicate: predicate,
+			Object:    object,
+		}
+
+		c, err := d.peekAfterSpace()
+
+		if err != nil {
+			return err
+		}
+
+		if c != ',' {
+			return nil
+		}
+
+		d.discard(1)
+	}
+}
+
+// verb() reads a predicate, either an IRI, a prefixed name or "a" (`rdf:type`).
+func (d *TurtleDecoder) verb() (*Term, error) {
+
+	err := d.skipSpace()
+
+	if err != nil {
+		return nil, err
+	}
+
+	next := d.peekString(2)
+
+	if next == "a" || (len(next) == 2 && next[0] == 'a' && !isNameChar(next[1]) && next[1] != ':') {
+		d.discard(1)
+		return &Term{Type: IRI, Value: rdfType}, nil
+	}
+
+	return d.iriTerm()
+}
+
+// object() reads the object of a triple.
+func (d *TurtleDecoder) object() (*Term, error) {
+
+	c, err := d.peekAfterSpace()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c == '[':
+		return d.blankNodePropertyList()
+	case c == '(':
+		return d.collection()
+	case c == '_':
+		return d.blankNode()
+	case c == '"' || c == '\'':
+		return d.literal()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return d.numeric()
+	case c == '<':
+		return d.iriTerm()
+	}
+
+	// Prefixed names and the "true" and "false" keywords
+
+	prefix, err := d.name()
+
+	if err != nil {
+		return nil, err
+	}
+
+	next, _ := d.peek()
+
+	if next != ':' && (prefix == "true" || prefix == "false") {
+		return &Term{Type: Literal, Value: prefix, Datatype: xsdBoolean}, nil
+	}
+
+	return d.prefixedName(prefix)
+}
+
+// blankNodePropertyList() reads a blank node property list ("[ ... ]"), adding its triples to the queue, and returns
+// the blank node it describes.
+func (d *TurtleDecoder) blankNodePropertyList() (*Term, error) {
+
+	d.discard(1)
+
+	node := d.newBlankNode()
+
+	c, err := d.peekAfterSpace()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c != ']' {
+
+		err := d.predicateObjectList(node)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = d.expect(']')
+
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// collection() reads a collection ("( ... )"), adding the `rdf:first` and `rdf:rest` triples for its items to the queue,
+// and returns the blank node for its first item or `rdf:nil` if it is empty.
+func (d *TurtleDecoder) collection() (*Term, error) {
+
+	d.discard(1)
+
+	var head *Term
+	var previous *Term
+
+	for {
+
+		c, err := d.peekAfterSpace()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c == ')' {
+			d.discard(1)
+			break
+		}
+
+		node := d.newBlankNode()
+
+		if previous == nil {
+			head = node
+		} else {
+			d.queue = append(d.queue, &Triple{Subject: previous, Predicate: &Term{Type: IRI, Value: rdfRest}, Object: node})
+		}
+
+		idx := len(d.queue)
+		d.queue = append(d.queue, nil)
+
+		item, err := d.object()
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid collection item, %w", err)
+		}
+
+		d.queue[idx] = &Triple{Subject: node, Predicate: &Term{Type: IRI, Value: rdfFirst}, Object: item}
+		previous = node
+	}
+
+	nil_term := &Term{Type: IRI, Value: rdfNil}
+
+	if head == nil {
+		return nil_term, nil
+	}
+
+	d.queue = append(d.queue, &Triple{Subject: previous, Predicate: &Term{Type: IRI, Value: rdfRest}, Object: nil_term})
+	return head, nil
+}
+
+// blankNode() reads a labeled blank node, for example "_:b0". Labels which start with the prefix used for generated
+// labels are prefixed again so that they can not be confused with them.
+func (d *TurtleDecoder) blankNode() (*Term, error) {
+
+	if d.peekString(2) != "_:" {
+		return nil, fmt.Errorf("Invalid blank node")
+	}
+
+	d.discard(2)
+
+	label, err := d.name()
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid blank node label, %w", err)
+	}
+
+	if label == "" {
+		return nil, fmt.Errorf("Empty blank node label")
+	}
+
+	if strings.HasPrefix(label, generatedBlankNodePrefix) {
+		label = generatedBlankNodePrefix + "-" + label
+	}
+
+	return &Term{Type: BlankNode, Value: "_:" + label}, nil
+}
+
+// newBlankNode() returns a new blank node with a generated label.
+func (d *TurtleDecoder) newBlankNode() *Term {
+
+	d.blank_nodes += 1
+	return &Term{Type: BlankNode, Value: fmt.Sprintf("_:%s%d", generatedBlankNodePrefix, d.blank_nodes)}
+}
+
+// iriTerm() reads an IRI, either enclosed in angle brackets or as a prefixed name.
+func (d *TurtleDecoder) iriTerm() (*Term, error) {
+
+	c, err := d.peek()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c == '<' {
+
+		iri, err := d.iri()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Term{Type: IRI, Value: iri}, nil
+	}
+
+	prefix, err := d.name()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.prefixedName(prefix)
+}
+
+// iri() reads an IRI enclosed in angle brackets and returns its (unescaped) value, resolved against the base IRI.
+func (d *TurtleDecoder) iri() (string, error) {
+
+	err := d.expect('<')
+
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	for {
+
+		c, err := d.read()
+
+		if err != nil {
+			return "", fmt.Errorf("Unterminated IRI, %w", err)
+		}
+
+		if c == '>' {
+			break
+		}
+
+		if c == ' ' || c == '\n' || c == '<' {
+			return "", fmt.Errorf("Invalid character '%c' in IRI", c)
+		}
+
+		sb.WriteByte(c)
+	}
+
+	iri := sb.String()
+
+	if strings.Contains(iri, `\`) {
+
+		v, err := unescape(iri)
+
+		if err != nil {
+			return "", err
+		}
+
+		iri = v
+	}
+
+	return d.resolve(iri), nil
+}
+
+// prefixedName() reads the local part of a prefixed name, whose prefix has already been read, and returns the IRI it
+// abbreviates.
+func (d *TurtleDecoder) prefixedName(prefix string) (*Term, error) {
+
+	err := d.expect(':')
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid prefixed name '%s', %w", prefix, err)
+	}
+
+	namespace, ok := d.prefixes[prefix]
+
+	if !ok {
+		return nil, fmt.Errorf("Undefined prefix '%s'", prefix)
+	}
+
+	var sb strings.Builder
+
+	for {
+
+		next := d.peekString(3)
+
+		if next == "" {
+			break
+		}
+
+		c := next[0]
+
+		switch {
+		case c == '\\' && len(next) > 1:
+
+			// Reserved characters, for example "\/", are escaped in local names
+
+			sb.WriteByte(next[1])
+			d.discard(2)
+			continue
+
+		case c == '%' && len(next) == 3:
+
+			// Percent-encoded characters are preserved as-is
+
+			sb.WriteString(next)
+			d.discard(3)
+			continue
+
+		case c == '.':
+
+			// Local names can not end with a "." since it terminates the statement
+
+			if len(next) < 2 || !(isNameChar(next[1]) || next[1] == ':' || next[1] == '%' || next[1] == '\\') {
+				return &Term{Type: IRI, Value: namespace + sb.String()}, nil
+			}
+
+		case !isNameChar(c) && c != ':':
+			return &Term{Type: IRI, Value: namespace + sb.String()}, nil
+		}
+
+		sb.WriteByte(c)
+		d.discard(1)
+	}
+
+	return &Term{Type: IRI, Value: namespace + sb.String()}, nil
+}
+
+// literal() reads a quoted (or long, triple-quoted) string literal, including any language tag or datatype.
+func (d *TurtleDecoder) literal() (*Term, error) {
+
+	quote, err := d.read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	long := false
+	quotes := string([]byte{quote, quote})
+
+	if d.peekString(2) == quotes {
+		d.discard(2)
+		long = true
+	}
+
+	var sb strings.Builder
+	escaped := false
+
+	for {
+
+		if !long {
+
+			next, _ := d.peek()
+
+			if next == '\n' || next == '\r' {
+				return nil, fmt.Errorf("Unterminated literal")
+			}
+		}
+
+		c, err := d.read()
+
+		if err != nil {
+			return nil, fmt.Errorf("Unterminated literal, %w", err)
+		}
+
+		if c == '\\' {
+
+			next, err := d.read()
+
+			if err != nil {
+				return nil, fmt.Errorf("Unterminated literal, %w", err)
+			}
+
+			sb.WriteByte(c)
+			sb.WriteByte(next)
+			escaped = true
+			continue
+		}
+
+		if c == quote {
+
+			if !long {
+				break
+			}
+
+			// A long string ends at the last of a run of (at least three) quotes
+
+			next := d.peekString(3)
+
+			if next == quotes || (strings.HasPrefix(next, quotes) && next[2] != quote) {
+				d.discard(2)
+				break
+			}
+		}
+
+		sb.WriteByte(c)
+	}
+
+	value := sb.String()
+
+	if escaped {
+
+		v, err := unescape(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value = v
+	}
+
+	t := &Term{
+		Type:  Literal,
+		Value: value,
+	}
+
+	switch {
+	case d.peekString(1) == "@":
+
+		d.discard(1)
+
+		var lang strings.Builder
+
+		for {
+
+			c, err := d.peek()
+
+			if err != nil || !(isAlphaNumeric(c) || c == '-') {
+				break
+			}
+
+			lang.WriteByte(c)
+			d.discard(1)
+		}
+
+		if lang.Len() == 0 {
+			return nil, fmt.Errorf("Empty language tag")
+		}
+
+		t.Language = lang.String()
+
+	case d.peekString(2) == "^^":
+
+		d.discard(2)
+
+		dt, err := d.iriTerm()
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid datatype, %w", err)
+		}
+
+		t.Datatype = dt.Value
+	}
+
+	return t, nil
+}
+
+// numeric() reads an integer, decimal or double literal.
+func (d *TurtleDecoder) numeric() (*Term, error) {
+
+	var sb strings.Builder
+
+	digits := func() int {
+
+		count := 0
+
+		for {
+
+			c, err := d.peek()
+
+			if err != nil || c < '0' || c > '9' {
+				return count
+			}
+
+			sb.WriteByte(c)
+			d.discard(1)
+			count += 1
+		}
+	}
+
+	datatype := xsdInteger
+
+	c, _ := d.peek()
+
+	if c == '+' || c == '-' {
+		sb.WriteByte(c)
+		d.discard(1)
+	}
+
+	count := digits()
+
+	next := d.peekString(2)
+
+	if len(next) == 2 && next[0] == '.' && next[1] >= '0' && next[1] <= '9' {
+		sb.WriteByte('.')
+		d.discard(1)
+		count += digits()
+		datatype = xsdDecimal
+	}
+
+	if count == 0 {
+		return nil, fmt.Errorf("Invalid numeric literal '%s'", sb.String())
+	}
+
+	c, _ = d.peek()
+
+	if c == 'e' || c == 'E' {
+
+		sb.WriteByte(c)
+		d.discard(1)
+
+		c, _ = d.peek()
+
+		if c == '+' || c == '-' {
+			sb.WriteByte(c)
+			d.discard(1)
+		}
+
+		if digits() == 0 {
+			return nil, fmt.Errorf("Invalid exponent in numeric literal '%s'", sb.String())
+		}
+
+		datatype = xsdDouble
+	}
+
+	return &Term{Type: Literal, Value: sb.String(), Datatype: datatype}, nil
+}
+
+// name() reads a prefix, or blank node label, made up of letters, digits, underscores, hyphens and non-ASCII characters.
+// Names may contain, but not end with, periods.
+func (d *TurtleDecoder) name() (string, error) {
+
+	var sb strings.Builder
+
+	for {
+
+		next := d.peekString(2)
+
+		if next == "" {
+			break
+		}
+
+		c := next[0]
+
+		if c == '.' {
+
+			if len(next) < 2 || !isNameChar(next[1]) {
+				break
+			}
+
+		} else if !isNameChar(c) {
+			break
+		}
+
+		sb.WriteByte(c)
+		d.discard(1)
+	}
+
+	name := sb.String()
+
+	if name == "" {
+
+		c, err := d.peek()
+
+		if err != nil {
+			return "", err
+		}
+
+		if c != ':' {
+			return "", fmt.Errorf("Unexpected character '%c'", c)
+		}
+	}
+
+	return name, nil
+}
+
+// resolve() returns 'iri' resolved against the base IRI of 'd', if there is one.
+func (d *TurtleDecoder) resolve(iri string) string {
+
+	if d.base == nil {
+		return iri
+	}
+
+	u, err := url.Parse(iri)
+
+	if err != nil || u.IsAbs() {
+		return iri
+	}
+
+	return d.base.ResolveReference(u).String()
+}
+
+// skipSpace() advances 'd' past any whitespace and comments.
+func (d *TurtleDecoder) skipSpace() error {
+
+	for {
+
+		c, err := d.peek()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			d.discard(1)
+		case '#':
+
+			for {
+
+				c, err := d.read()
+
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				if c == '\n' {
+					break
+				}
+			}
+
+		default:
+			return nil
+		}
+	}
+}
+
+// peekAfterSpace() advances 'd' past any whitespace and comments and returns the next byte without consuming it. It
+// returns `io.ErrUnexpectedEOF` at the end of the document.
+func (d *TurtleDecoder) peekAfterSpace() (byte, error) {
+
+	err := d.skipSpace()
+
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := d.peek()
+
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+
+	return c, nil
+}
+
+// expect() advances 'd' past any whitespace and comments and then past 'c', which must be the next byte.
+func (d *TurtleDecoder) expect(c byte) error {
+
+	next, err := d.peekAfterSpace()
+
+	if err != nil {
+		return err
+	}
+
+	if next != c {
+		return fmt.Errorf("Expected '%c' but found '%c'", c, next)
+	}
+
+	d.discard(1)
+	return nil
+}
+
+// hasPrefix() returns a boolean value indicating whether the next bytes are 'prefix' followed by whitespace.
+func (d *TurtleDecoder) hasPrefix(prefix string) bool {
+
+	next := d.peekString(len(prefix) + 1)
+
+	if len(next) != len(prefix)+1 || next[0:len(prefix)] != prefix {
+		return false
+	}
+
+	return isSpace(next[len(prefix)])
+}
+
+// hasKeyword() returns a boolean value indicating whether the next bytes are 'keyword', in any case, followed by whitespace.
+func (d *TurtleDecoder) hasKeyword(keyword string) bool {
+
+	next := d.peekString(len(keyword) + 1)
+
+	if len(next) != len(keyword)+1 || !strings.EqualFold(next[0:len(keyword)], keyword) {
+		return false
+	}
+
+	return isSpace(next[len(keyword)])
+}
+
+// peek() returns the next byte without consuming it.
+func (d *TurtleDecoder) peek() (byte, error) {
+
+	b, err := d.reader.Peek(1)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+// peekString() returns up to the next 'n' bytes, as a string, without consuming them.
+func (d *TurtleDecoder) peekString(n int) string {
+
+	b, _ := d.reader.Peek(n)
+	return string(b)
+}
+
+// read() consumes and returns the next byte.
+func (d *TurtleDecoder) read() (byte, error) {
+
+	c, err := d.reader.ReadByte()
+
+	if err != nil {
+		return 0, err
+	}
+
+	d.offset += 1
+
+	if c == '\n' {
+		d.line += 1
+	}
+
+	return c, nil
+}
+
+// discard() consumes the next 'n' bytes, which have already been peeked.
+func (d *TurtleDecoder) discard(n int) {
+
+	for i := 0; i < n; i++ {
+		d.read()
+	}
+}
+
+// isNameChar() returns a boolean value indicating whether 'c' may be part of a prefix, local name or blank node label.
+// Bytes belonging to multi-byte (non-ASCII) UTF-8 characters are always allowed.
+func isNameChar(c byte) bool {
+	return isAlphaNumeric(c) || c == '_' || c == '-' || c >= 0x80
+}
+
+// isAlphaNumeric() returns a boolean value indicating whether 'c' is an ASCII letter or digit.
+func isAlphaNumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isSpace() returns a boolean value indicating whether 'c' is whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// unexpectedEOF() returns `io.ErrUnexpectedEOF` if 'err' is `io.EOF`, otherwise 'err' is returned.
+func unexpectedEOF(err error) error {
+
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,141 @@
+package ntriples
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTurtleDecoder(t *testing.T) {
+
+	doc := `# A comment
+@prefix madsrdf: <http://www.loc.gov/mads/rdf/v1#> .
+@prefix skos: <http://www.w3.org/2004/02/skos/core#> .
+PREFIX ri: <http://id.loc.gov/ontologies/RecordInfo#>
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix : <http://example.com/> .
+@base <http://id.loc.gov/authorities/subjects/> .
+
+<sh85016999> a madsrdf:Authority, skos:Concept ;
+    madsrdf:authoritativeLabel "Broadband amplifiers"@en ;
+    madsrdf:adminMetadata [
+        a ri:RecordInfo ;
+        ri:recordChangeDate "1986-02-11T00:00:00"^^xsd:dateTime ;
+    ] ;
+    madsrdf:elementList ( [ madsrdf:elementValue "Broadband amplifiers"@en ] ) ;;
+    skos:broader <sh85004652> .
+
+_:b0 :note """Dvořák said "hello""""", 'it\'s', "tab\t" ; # trailing comment
+    :count 3, -1.5, 2e10, true ;
+    :empty (), [] ;
+    :escaped :a\/b.c, _:genid1 .
+
+[ :p :o ] .
+`
+
+	expected := []string{
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.loc.gov/mads/rdf/v1#Authority> .`,
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.w3.org/2004/02/skos/core#Concept> .`,
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Broadband amplifiers"@en .`,
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.loc.gov/mads/rdf/v1#adminMetadata> _:genid1 .`,
+		`_:genid1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://id.loc.gov/ontologies/RecordInfo#RecordInfo> .`,
+		`_:genid1 <http://id.loc.gov/ontologies/RecordInfo#recordChangeDate> "1986-02-11T00:00:00"^^<http://www.w3.org/2001/XMLSchema#dateTime> .`,
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.loc.gov/mads/rdf/v1#elementList> _:genid2 .`,
+		`_:genid2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> _:genid3 .`,
+		`_:genid3 <http://www.loc.gov/mads/rdf/v1#elementValue> "Broadband amplifiers"@en .`,
+		`_:genid2 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .`,
+		`<http://id.loc.gov/authorities/subjects/sh85016999> <http://www.w3.org/2004/02/skos/core#broader> <http://id.loc.gov/authorities/subjects/sh85004652> .`,
+		`_:b0 <http://example.com/note> "Dvořák said \"hello\"\"" .`,
+		`_:b0 <http://example.com/note> "it's" .`,
+		`_:b0 <http://example.com/note> "tab\t" .`,
+		`_:b0 <http://example.com/count> "3"^^<http://www.w3.org/2001/XMLSchema#integer> .`,
+		`_:b0 <http://example.com/count> "-1.5"^^<http://www.w3.org/2001/XMLSchema#decimal> .`,
+		`_:b0 <http://example.com/count> "2e10"^^<http://www.w3.org/2001/XMLSchema#double> .`,
+		`_:b0 <http://example.com/count> "true"^^<http://www.w3.org/2001/XMLSchema#boolean> .`,
+		`_:b0 <http://example.com/empty> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .`,
+		`_:b0 <http://example.com/empty> _:genid4 .`,
+		`_:b0 <http://example.com/escaped> <http://example.com/a/b.c> .`,
+		`_:b0 <http://example.com/escaped> _:genid-genid1 .`,
+		`_:genid5 <http://example.com/p> <http://example.com/o> .`,
+	}
+
+	dec := NewTurtleDecoder(strings.NewReader(doc))
+
+	for idx, line := range expected {
+
+		tr, err := dec.Next()
+
+		if err != nil {
+			t.Fatalf("Failed to read triple %d, %v", idx, err)
+		}
+
+		expected_tr, err := ParseTriple(line)
+
+		if err != nil {
+			t.Fatalf("Failed to parse expected triple '%s', %v", line, err)
+		}
+
+		for label, pair := range map[string][2]*Term{
+			"subject":   {tr.Subject, expected_tr.Subject},
+			"predicate": {tr.Predicate, expected_tr.Predicate},
+			"object":    {tr.Object, expected_tr.Object},
+		} {
+
+			if *pair[0] != *pair[1] {
+				t.Fatalf("Unexpected %s for triple %d (expected '%s'): %v", label, idx, line, pair[0])
+			}
+		}
+	}
+
+	_, err := dec.Next()
+
+	if err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	if dec.Line() != 23 {
+		t.Fatalf("Unexpected line for last statement: %d", dec.Line())
+	}
+}
+
+func TestTurtleDecoderInvalid(t *testing.T) {
+
+	tests := map[string]int{
+		"<http://example.com/a> <http://example.com/b> <http://example.com/c> .\n<http://example.com/a> <http://example.com/b> <http://example.com/c>\n": 3,
+		"<http://example.com/a> <http://example.com/b> undefined:c .":                                                                                    1,
+		"<http://example.com/a> <http://example.com/b> \"unterminated\n\" .":                                                                             1,
+		"<http://example.com/a> <http://example.com/b> \"bad \\q escape\" .":                                                                             1,
+		"\n\n<http://example.com/a> <http://example.com/b> [ <http://example.com/c> 1 .":                                                                 3,
+		"<http://example.com/a> <http://example.com/b> ( 1 2 .":                                                                                          1,
+		"\"literal\" <http://example.com/b> <http://example.com/c> .":                                                                                    1,
+		"<http://example.com/a> \"literal\" <http://example.com/c> .":                                                                                    1,
+		"@prefix ex: <http://example.com/>\n<http://example.com/a> ex:b ex:c .":                                                                          2,
+		"<http://example.com/a> <http://example.com/b> <http://example.com/c> ; ; , ex:d .":                                                              1,
+	}
+
+	for doc, line := range tests {
+
+		dec := NewTurtleDecoder(strings.NewReader(doc))
+
+		var err error
+
+		for err == nil {
+			_, err = dec.Next()
+		}
+
+		if err == io.EOF {
+			t.Fatalf("Expected '%s' to fail", doc)
+		}
+
+		if !strings.Contains(err.Error(), fmt.Sprintf("line %d,", line)) {
+			t.Fatalf("Expected error for line %d of '%s', got %v", line, doc, err)
+		}
+
+		_, next_err := dec.Next()
+
+		if next_err != err {
+			t.Fatalf("Expected decoder to stop after error, got %v", next_err)
+		}
+	}
+}
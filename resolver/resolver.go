@@ -0,0 +1,452 @@
+// Package resolver provides methods for resolving Library of Congress subject heading strings, for example
+// "Anarchism--Italy--History--20th century", to the identifiers of the authority records they are composed of.
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+	"github.com/sfomuseum/go-libraryofcongress/walk"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The separator used to delimit the subdivisions of a pre-coordinated heading.
+const SubdivisionSeparator string = "--"
+
+// Match types.
+const (
+	// MatchAuthorized indicates a match against the authoritative label of a heading.
+	MatchAuthorized string = "authorized"
+	// MatchComponent indicates a match against the label of a component of a pre-coordinated heading.
+	MatchComponent string = "component"
+	// MatchVariant indicates a match against a variant ("used for") label of a heading.
+	MatchVariant string = "variant"
+	// MatchDeprecated indicates a match against the label of a deprecated heading which has been replaced by another heading.
+	MatchDeprecated string = "deprecated"
+)
+
+// matchConfidence is the base confidence score for each match type.
+var matchConfidence = map[string]float64{
+	MatchAuthorized: 1.0,
+	MatchComponent:  0.9,
+	MatchVariant:    0.8,
+	MatchDeprecated: 0.8,
+}
+
+// matchTiers is the order in which match types are considered.
+var matchTiers = []string{
+	MatchAuthorized,
+	MatchComponent,
+	MatchVariant,
+	MatchDeprecated,
+}
+
+// mainHeadingPenalty is the factor applied to the confidence of a subdivision that was matched against a
+// (non-geographic) main heading rather than a free-floating subdivision.
+const mainHeadingPenalty float64 = 0.8
+
+// type candidate is a heading that has been indexed by its (normalized) label.
+type candidate struct {
+	// ID is the identifier of the heading, for example "sh85004812".
+	ID string
+	// Match is the type of label the heading was indexed by.
+	Match string
+	// Subdivision is a boolean flag indicating the heading is a free-floating subdivision.
+	Subdivision bool
+}
+
+// type heading is the label and MADS/RDF type of an indexed heading.
+type heading struct {
+	Label       string
+	Type        string
+	Subdivision bool
+}
+
+// type Resolver resolves heading strings to the identifiers of the authority records they are composed of.
+type Resolver struct {
+	mu       *sync.RWMutex
+	index    map[string][]*candidate
+	headings map[string]*heading
+}
+
+// type Component is a (possibly unmatched) component of a resolved heading string.
+type Component struct {
+	// Value is the portion of the heading string this component was derived from, for example "Italy--History".
+	Value string `json:"value"`
+	// ID is the identifier of the matching heading, for example "sh85004812". Unmatched components do not have an ID.
+	ID string `json:"id,omitempty"`
+	// Label is the authoritative label of the matching heading.
+	Label string `json:"label,omitempty"`
+	// Type is the MADS/RDF type of the matching heading, for example "Topic" or "Geographic".
+	Type string `json:"type,omitempty"`
+	// Match is the type of label that was matched, for example `MatchAuthorized` or `MatchVariant`.
+	Match string `json:"match,omitempty"`
+	// Confidence is a score between 0.0 (unmatched) and 1.0 (an unambiguous authorized match).
+	Confidence float64 `json:"confidence"`
+}
+
+// type Resolution is the result of resolving a heading string.
+type Resolution struct {
+	// Heading is the heading string that was resolved.
+	Heading string `json:"heading"`
+	// Components is the list of components, in order, that make up the heading string.
+	Components []*Component `json:"components"`
+	// Unmatched is the list of subdivisions, in order, that could not be matched to a heading.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// NewResolver() returns a new (empty) `Resolver` instance. Use the `Add` or `IndexURIs` methods to populate it.
+func NewResolver() *Resolver {
+
+	r := &Resolver{
+		mu:       new(sync.RWMutex),
+		index:    make(map[string][]*candidate),
+		headings: make(map[string]*heading),
+	}
+
+	return r
+}
+
+// IndexURIs() walks 'uris', which are expected to be `lcsh.both.ndjson` (or `lcsh.both.ndjson.zip`) files, adding
+// each record to 'r'.
+func (r *Resolver) IndexURIs(ctx context.Context, uris ...string) error {
+
+	w, err := walk.NewWalker(ctx, "ndjson://")
+
+	if err != nil {
+		return fmt.Errorf("Failed to create walker, %w", err)
+	}
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		a, err := authority.ParseRecord(body)
+
+		if err != nil {
+			return fmt.Errorf("Failed to parse record, %w", err)
+		}
+
+		r.Add(a)
+		return nil
+	}
+
+	err = w.WalkURIs(ctx, cb, uris...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to index URIs, %w", err)
+	}
+
+	return nil
+}
+
+// Add() indexes the authoritative label, variant labels and the labels of any (non-blank) components of 'a'.
+// The labels of deprecated headings are indexed as pointers to the headings that replace them.
+func (r *Resolver) Add(a *authority.Authority) {
+
+	id := a.Identifier()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if a.IsDeprecated() {
+
+		for _, uri := range a.Replacements() {
+
+			c := &candidate{
+				ID:    path.Base(uri),
+				Match: MatchDeprecated,
+			}
+
+			for _, l := range a.Labels {
+				r.addCandidate(l.Value, c)
+			}
+		}
+
+		return
+	}
+
+	label := a.Label()
+
+	if label == "" {
+		return
+	}
+
+	r.headings[id] = &heading{
+		Label:       label,
+		Type:        a.MADSType(),
+		Subdivision: a.IsSubdivision(),
+	}
+
+	for _, l := range a.Labels {
+
+		r.addCandidate(l.Value, &candidate{
+			ID:          id,
+			Match:       MatchAuthorized,
+			Subdivision: a.IsSubdivision(),
+		})
+	}
+
+	for _, l := range a.VariantLabels() {
+
+		r.addCandidate(l.Value, &candidate{
+			ID:          id,
+			Match:       MatchVariant,
+			Subdivision: a.IsSubdivision(),
+		})
+	}
+
+	for _, c := range a.Components {
+
+		c_id := c.Identifier()
+
+		if c_id == "" || c.Label() == "" {
+			continue
+		}
+
+		_, exists := r.headings[c_id]
+
+		if !exists {
+
+			r.headings[c_id] = &heading{
+				Label: c.Label(),
+				Type:  c.Type,
+			}
+		}
+
+		r.addCandidate(c.Label(), &candidate{
+			ID:    c_id,
+			Match: MatchComponent,
+		})
+	}
+}
+
+// Resolve() resolves 'str', a heading string, to the headings it is composed of. 'str' is first matched, in its
+// entirety, against authorized labels and then variant labels. If there is no match 'str' is split on "--" and
+// the longest possible sequence of subdivisions, starting from the left, is matched in the same way. Subdivisions
+// which can not be matched are recorded as unmatched components and the process continues with the next subdivision.
+// Subdivisions after the first one are matched against free-floating subdivisions in preference to main headings.
+func (r *Resolver) Resolve(str string) *Resolution {
+
+	rsp := &Resolution{
+		Heading:    str,
+		Components: make([]*Component, 0),
+		Unmatched:  make([]string, 0),
+	}
+
+	parts := splitHeading(str)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := 0
+
+	for i < len(parts) {
+
+		matched := false
+
+		for j := len(parts); j > i; j-- {
+
+			value := strings.Join(parts[i:j], SubdivisionSeparator)
+			c, ok := r.lookup(value, i > 0)
+
+			if !ok {
+				continue
+			}
+
+			rsp.Components = append(rsp.Components, c)
+			matched = true
+			i = j
+			break
+		}
+
+		if !matched {
+
+			rsp.Components = append(rsp.Components, &Component{
+				Value: parts[i],
+			})
+
+			rsp.Unmatched = append(rsp.Unmatched, parts[i])
+			i++
+		}
+	}
+
+	return rsp
+}
+
+// IDs() returns the list of identifiers for the matched components of 'rsp'.
+func (rsp *Resolution) IDs() []string {
+
+	ids := make([]string, 0)
+
+	for _, c := range rsp.Components {
+
+		if c.ID != "" {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	return ids
+}
+
+// Remainder() returns the unmatched subdivisions of 'rsp' joined by "--".
+func (rsp *Resolution) Remainder() string {
+	return strings.Join(rsp.Unmatched, SubdivisionSeparator)
+}
+
+// IsResolved() returns a boolean value indicating whether every subdivision of 'rsp' was matched.
+func (rsp *Resolution) IsResolved() bool {
+	return len(rsp.Components) > 0 && len(rsp.Unmatched) == 0
+}
+
+// Confidence() returns the mean confidence of the components of 'rsp'. Unmatched components have a confidence of 0.0.
+func (rsp *Resolution) Confidence() float64 {
+
+	if len(rsp.Components) == 0 {
+		return 0.0
+	}
+
+	total := 0.0
+
+	for _, c := range rsp.Components {
+		total += c.Confidence
+	}
+
+	return total / float64(len(rsp.Components))
+}
+
+// addCandidate() indexes 'c' by 'label' ignoring duplicate entries. This method assumes the caller holds a write lock.
+func (r *Resolver) addCandidate(label string, c *candidate) {
+
+	k := normalize(label)
+
+	if k == "" {
+		return
+	}
+
+	for _, existing := range r.index[k] {
+
+		if existing.ID == c.ID && existing.Match == c.Match {
+			return
+		}
+	}
+
+	r.index[k] = append(r.index[k], c)
+}
+
+// lookup() returns the best match for 'value'. If 'subdivision' is true free-floating subdivisions are preferred
+// over main headings; otherwise main headings are preferred. This method assumes the caller holds a read lock.
+func (r *Resolver) lookup(value string, subdivision bool) (*Component, bool) {
+
+	candidates, ok := r.index[normalize(value)]
+
+	if !ok {
+		return nil, false
+	}
+
+	for _, tier := range matchTiers {
+
+		tier_candidates := make([]*candidate, 0)
+
+		for _, c := range candidates {
+
+			if c.Match == tier {
+				tier_candidates = append(tier_candidates, c)
+			}
+		}
+
+		if len(tier_candidates) == 0 {
+			continue
+		}
+
+		preferred := make([]*candidate, 0)
+
+		for _, c := range tier_candidates {
+
+			if c.Subdivision == subdivision {
+				preferred = append(preferred, c)
+			}
+		}
+
+		if len(preferred) == 0 {
+			preferred = tier_candidates
+		}
+
+		sort.Slice(preferred, func(i, j int) bool {
+			return preferred[i].ID < preferred[j].ID
+		})
+
+		best := preferred[0]
+
+		// Ambiguous labels share the confidence of the match between each candidate.
+
+		confidence := matchConfidence[tier] / float64(len(preferred))
+
+		c := &Component{
+			Value: value,
+			ID:    best.ID,
+			Match: tier,
+		}
+
+		h, exists := r.headings[best.ID]
+
+		if exists {
+			c.Label = h.Label
+			c.Type = h.Type
+		}
+
+		if subdivision && !best.Subdivision {
+
+			switch c.Type {
+			case "Geographic", "HierarchicalGeographic":
+				// Geographic subdivisions are always main headings
+			default:
+				confidence = confidence * mainHeadingPenalty
+			}
+		}
+
+		c.Confidence = confidence
+		return c, true
+	}
+
+	return nil, false
+}
+
+// splitHeading() splits 'str' on "--" returning the list of non-empty, trimmed subdivisions.
+func splitHeading(str string) []string {
+
+	parts := make([]string, 0)
+
+	for _, p := range strings.Split(str, SubdivisionSeparator) {
+
+		p = strings.TrimSpace(p)
+		p = strings.TrimRight(p, ".")
+		p = strings.TrimSpace(p)
+
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return parts
+}
+
+// normalize() returns a normalized version of 'str', suitable for use as an index key. Strings are converted to
+// Unicode NFC form and lower-cased, runs of whitespace are collapsed, whitespace around "--" is removed and trailing
+// full stops are removed.
+func normalize(str string) string {
+
+	str = norm.NFC.String(str)
+	str = strings.ToLower(str)
+
+	parts := splitHeading(str)
+
+	for i, p := range parts {
+		parts[i] = strings.Join(strings.Fields(p), " ")
+	}
+
+	return strings.Join(parts, SubdivisionSeparator)
+}
@@ -0,0 +1,136 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+// testRecords is a list of (abbreviated) LCSH records used to populate a `Resolver` instance for testing.
+var testRecords = []string{
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85004812", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Anarchism"}, "madsrdf:isMemberOfMADSCollection": {"@id": "http://id.loc.gov/authorities/subjects/collection_LCSH_General"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/names/n79021206", "@type": ["madsrdf:Authority", "madsrdf:Geographic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Italy"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85061212", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "History"}, "madsrdf:isMemberOfMADSCollection": {"@id": "http://id.loc.gov/authorities/subjects/collection_LCSH_General"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh99005024", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "History"}, "madsrdf:isMemberOfMADSCollection": [{"@id": "http://id.loc.gov/authorities/subjects/collection_Subdivisions"}, {"@id": "http://id.loc.gov/authorities/subjects/collection_GeneralSubdivision"}]}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh2002012476", "@type": ["madsrdf:Authority", "madsrdf:Temporal"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "20th century"}, "skos:altLabel": {"@language": "en", "@value": "Twentieth century"}, "madsrdf:isMemberOfMADSCollection": {"@id": "http://id.loc.gov/authorities/subjects/collection_Subdivisions"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh2008100002", "@type": ["madsrdf:Authority", "madsrdf:ComplexSubject"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Italy--Politics and government"}, "madsrdf:componentList": {"@list": [{"@id": "http://id.loc.gov/authorities/names/n79021206"}, {"@id": "_:N1"}]}}, {"@id": "_:N1", "@type": ["madsrdf:Authority", "madsrdf:Topic"], "madsrdf:authoritativeLabel": {"@language": "en", "@value": "Politics and government"}}]}`,
+	`{"@graph": [{"@id": "http://id.loc.gov/authorities/subjects/sh85000001", "@type": ["madsrdf:Authority", "madsrdf:DeprecatedAuthority", "madsrdf:Topic"], "madsrdf:variantLabel": {"@language": "en", "@value": "Anarchy"}, "madsrdf:useInstead": {"@id": "http://id.loc.gov/authorities/subjects/sh85004812"}}]}`,
+}
+
+// newTestResolver() returns a new `Resolver` instance populated with the records in 'testRecords'.
+func newTestResolver(t *testing.T) *Resolver {
+
+	r := NewResolver()
+
+	for i, body := range testRecords {
+
+		a, err := authority.ParseRecord([]byte(body))
+
+		if err != nil {
+			t.Fatalf("Failed to parse test record %d, %v", i, err)
+		}
+
+		r.Add(a)
+	}
+
+	return r
+}
+
+// describe() returns a compact "id:match:confidence" representation of the components of 'rsp'.
+func describe(rsp *Resolution) string {
+
+	parts := make([]string, len(rsp.Components))
+
+	for i, c := range rsp.Components {
+		parts[i] = fmt.Sprintf("%s:%s:%.2f", c.ID, c.Match, c.Confidence)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestResolve(t *testing.T) {
+
+	r := newTestResolver(t)
+
+	tests := map[string]string{
+		"Anarchism":  "sh85004812:authorized:1.00",
+		"anarchism.": "sh85004812:authorized:1.00",
+		"Anarchy":    "sh85004812:deprecated:0.80",
+		"Anarchism--Italy--History--20th century":   "sh85004812:authorized:1.00 n79021206:authorized:1.00 sh99005024:authorized:1.00 sh2002012476:authorized:1.00",
+		"Anarchism -- Italy -- Twentieth century":   "sh85004812:authorized:1.00 n79021206:authorized:1.00 sh2002012476:variant:0.80",
+		"Italy--Politics and government--History":   "sh2008100002:authorized:1.00 sh99005024:authorized:1.00",
+		"Anarchism--Anarchism":                      "sh85004812:authorized:1.00 sh85004812:authorized:0.80",
+		"History":                                   "sh85061212:authorized:1.00",
+		"Anarchism--Politics and government--Italy": "sh85004812:authorized:1.00 ::0.00 n79021206:authorized:1.00",
+	}
+
+	for str, expected := range tests {
+
+		rsp := r.Resolve(str)
+
+		if describe(rsp) != expected {
+			t.Fatalf("Unexpected resolution for '%s': %s (expected %s)", str, describe(rsp), expected)
+		}
+	}
+}
+
+func TestResolveUnmatched(t *testing.T) {
+
+	r := newTestResolver(t)
+
+	rsp := r.Resolve("Anarchism--Foo--Italy--Bar Baz")
+
+	if rsp.IsResolved() {
+		t.Fatalf("Did not expect heading to be resolved")
+	}
+
+	if rsp.Remainder() != "Foo--Bar Baz" {
+		t.Fatalf("Unexpected remainder: %s", rsp.Remainder())
+	}
+
+	if strings.Join(rsp.IDs(), ",") != "sh85004812,n79021206" {
+		t.Fatalf("Unexpected IDs: %v", rsp.IDs())
+	}
+
+	if rsp.Confidence() != 0.5 {
+		t.Fatalf("Unexpected confidence: %f", rsp.Confidence())
+	}
+
+	rsp = r.Resolve("Anarchism--Italy")
+
+	if !rsp.IsResolved() || rsp.Remainder() != "" {
+		t.Fatalf("Expected heading to be resolved")
+	}
+
+	rsp = r.Resolve("")
+
+	if rsp.IsResolved() || len(rsp.Components) != 0 {
+		t.Fatalf("Did not expect empty heading to be resolved")
+	}
+}
+
+func TestIndexURIs(t *testing.T) {
+
+	ctx := context.Background()
+
+	r := NewResolver()
+
+	err := r.IndexURIs(ctx, "../fixtures/lcsh.sample.ndjson")
+
+	if err != nil {
+		t.Fatalf("Failed to index fixtures, %v", err)
+	}
+
+	rsp := r.Resolve("Wide-band amplifiers")
+
+	if describe(rsp) != "sh85016999:variant:0.80" {
+		t.Fatalf("Unexpected resolution: %s", describe(rsp))
+	}
+
+	if rsp.Components[0].Label != "Broadband amplifiers" || rsp.Components[0].Type != "Topic" {
+		t.Fatalf("Unexpected component: %v", rsp.Components[0])
+	}
+}
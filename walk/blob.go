@@ -0,0 +1,154 @@
+package walk
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/memblob"
+)
+
+// type bucketsKey is the type of the key used to store a map of `blob.Bucket` instances in a `context.Context`.
+type bucketsKey struct{}
+
+// IsBlobURI() returns a boolean value indicating whether 'uri' is a `gocloud.dev/blob` URI, for example "s3://bucket/key",
+// "gs://bucket/key", "azblob://container/key", "file:///path/to/key" or "mem:///key". Drivers for the "file" and "mem"
+// schemes are always available; drivers for other schemes need to be imported (for example `gocloud.dev/blob/s3blob`)
+// by the application.
+func IsBlobURI(uri string) bool {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return false
+	}
+
+	if len(u.Scheme) <= 1 {
+		return false
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return false
+	}
+
+	for _, scheme := range blob.DefaultURLMux().BucketSchemes() {
+
+		if scheme == u.Scheme {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitBlobURI() splits 'uri' in to the URI of the bucket that contains it and its key in that bucket. For example
+// "s3://bucket/path/to/lcsh.both.ndjson.zip?region=us-east-1" is split in to "s3://bucket?region=us-east-1" and
+// "path/to/lcsh.both.ndjson.zip". For "file://" URIs the bucket is the directory containing the file.
+func SplitBlobURI(uri string) (string, string, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	bucket_path := ""
+
+	if u.Scheme == "file" {
+		key = path.Base(u.Path)
+		bucket_path = path.Dir(u.Path)
+	}
+
+	if key == "" || key == "." || key == "/" {
+		return "", "", fmt.Errorf("URI is missing a key")
+	}
+
+	bucket_uri := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, bucket_path)
+
+	if u.RawQuery != "" {
+		bucket_uri = fmt.Sprintf("%s?%s", bucket_uri, u.RawQuery)
+	}
+
+	return bucket_uri, key, nil
+}
+
+// ContextWithBucket() returns a copy of 'ctx' containing 'b' which will be used, rather than opening a new bucket, to read
+// files whose bucket URI (as returned by `SplitBlobURI`) is 'bucket_uri'. The caller is responsible for closing 'b' once
+// the walk has completed. This means, for example, that data written to a "mem://" bucket can be read using `OpenURI`
+// and the `Walker` implementations.
+func ContextWithBucket(ctx context.Context, bucket_uri string, b *blob.Bucket) context.Context {
+
+	buckets := make(map[string]*blob.Bucket)
+
+	for k, v := range bucketsFromContext(ctx) {
+		buckets[k] = v
+	}
+
+	buckets[bucket_uri] = b
+	return context.WithValue(ctx, bucketsKey{}, buckets)
+}
+
+// bucketsFromContext() returns the map of `blob.Bucket` instances, keyed by bucket URI, stored in 'ctx' or nil.
+func bucketsFromContext(ctx context.Context) map[string]*blob.Bucket {
+
+	v := ctx.Value(bucketsKey{})
+
+	if v == nil {
+		return nil
+	}
+
+	return v.(map[string]*blob.Bucket)
+}
+
+// openBlobURI() opens 'uri', a `gocloud.dev/blob` URI, and returns a `WalkReader` instance and the size of the file. If
+// the bucket containing 'uri' has not been added to 'ctx' using `ContextWithBucket` it is opened and then closed when the
+// `WalkReader` instance is closed.
+func openBlobURI(ctx context.Context, uri string) (WalkReader, int64, error) {
+
+	bucket_uri, key, err := SplitBlobURI(uri)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed to parse blob URI for '%s', %w", uri, err)
+	}
+
+	b, ok := bucketsFromContext(ctx)[bucket_uri]
+	owned := false
+
+	if !ok {
+
+		b, err = blob.OpenBucket(ctx, bucket_uri)
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to open bucket %s, %w", bucket_uri, err)
+		}
+
+		owned = true
+	}
+
+	br, err := b.NewReader(ctx, key, nil)
+
+	if err != nil {
+
+		if owned {
+			b.Close()
+		}
+
+		return nil, 0, fmt.Errorf("Failed to open %s, %w", uri, err)
+	}
+
+	r := &BlobWalkReader{
+		reader: br,
+	}
+
+	if owned {
+		r.bucket = b
+	}
+
+	return r, br.Size(), nil
+}
@@ -0,0 +1,216 @@
+package walk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"gocloud.dev/blob"
+)
+
+func TestSplitBlobURI(t *testing.T) {
+
+	tests := map[string][2]string{
+		"s3://bucket/path/to/lcsh.both.ndjson.zip?region=us-east-1": {"s3://bucket?region=us-east-1", "path/to/lcsh.both.ndjson.zip"},
+		"gs://bucket/lcsh.both.ndjson.gz":                           {"gs://bucket", "lcsh.both.ndjson.gz"},
+		"file:///usr/local/data/lcsh.both.ndjson":                   {"file:///usr/local/data", "lcsh.both.ndjson"},
+		"mem:///lcsh.both.ndjson":                                   {"mem://", "lcsh.both.ndjson"},
+	}
+
+	for uri, expected := range tests {
+
+		bucket_uri, key, err := SplitBlobURI(uri)
+
+		if err != nil {
+			t.Fatalf("Failed to split %s, %v", uri, err)
+		}
+
+		if bucket_uri != expected[0] || key != expected[1] {
+			t.Fatalf("Unexpected bucket URI (%s) or key (%s) for %s", bucket_uri, key, uri)
+		}
+	}
+
+	_, _, err := SplitBlobURI("s3://bucket")
+
+	if err == nil {
+		t.Fatalf("Expected URI without a key to fail")
+	}
+}
+
+func TestIsBlobURI(t *testing.T) {
+
+	tests := map[string]bool{
+		"file:///usr/local/data/lcsh.both.ndjson":          true,
+		"mem:///lcsh.both.ndjson":                          true,
+		"/usr/local/data/lcsh.both.ndjson":                 false,
+		"https://id.loc.gov/download/lcsh.both.ndjson.zip": false,
+	}
+
+	for uri, expected := range tests {
+
+		if IsBlobURI(uri) != expected {
+			t.Fatalf("Unexpected result for %s (expected %t)", uri, expected)
+		}
+	}
+}
+
+func TestBlobWalkers(t *testing.T) {
+
+	ctx := context.Background()
+
+	b, err := blob.OpenBucket(ctx, "mem://")
+
+	if err != nil {
+		t.Fatalf("Failed to open bucket, %v", err)
+	}
+
+	defer b.Close()
+
+	ctx = ContextWithBucket(ctx, "mem://", b)
+
+	fixtures := []string{
+		"lcsh.sample.ndjson",
+		"lcsh.sample.ndjson.zip",
+		"lcsh.sample.ndjson.zst",
+	}
+
+	uris := make([]string, 0)
+
+	for _, fname := range fixtures {
+
+		rel_path := filepath.Join("../fixtures", fname)
+
+		body, err := os.ReadFile(rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", rel_path, err)
+		}
+
+		err = b.WriteAll(ctx, fname, body, nil)
+
+		if err != nil {
+			t.Fatalf("Failed to write %s, %v", fname, err)
+		}
+
+		uris = append(uris, "mem:///"+fname)
+
+		abs_path, err := filepath.Abs(rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+		}
+
+		uris = append(uris, "file://"+filepath.ToSlash(abs_path))
+	}
+
+	for _, uri := range uris {
+
+		r, sz, err := OpenURI(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to open %s, %v", uri, err)
+		}
+
+		r.Close()
+
+		if sz == 0 {
+			t.Fatalf("Unexpected size for %s", uri)
+		}
+
+		w, err := NewWalker(ctx, "ndjson://")
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker, %v", err)
+		}
+
+		count := int32(0)
+
+		cb := func(ctx context.Context, body []byte) error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, cb, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s, %v", uri, err)
+		}
+
+		if count != 3 {
+			t.Fatalf("Unexpected count for %s: %d", uri, count)
+		}
+	}
+
+	_, _, err = OpenURI(ctx, "mem:///missing.ndjson")
+
+	if err == nil {
+		t.Fatalf("Expected missing key to fail")
+	}
+}
+
+func TestBlobWalkReader(t *testing.T) {
+
+	ctx := context.Background()
+
+	rel_path := "../fixtures/lcsh.sample.ndjson"
+
+	body, err := os.ReadFile(rel_path)
+
+	if err != nil {
+		t.Fatalf("Failed to read %s, %v", rel_path, err)
+	}
+
+	abs_path, err := filepath.Abs(rel_path)
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+	}
+
+	r, sz, err := OpenURI(ctx, "file://"+filepath.ToSlash(abs_path))
+
+	if err != nil {
+		t.Fatalf("Failed to open %s, %v", abs_path, err)
+	}
+
+	defer r.Close()
+
+	if sz != int64(len(body)) {
+		t.Fatalf("Unexpected size: %d (expected %d)", sz, len(body))
+	}
+
+	// Calls to ReadAt should not change the offset used by Read
+
+	head := make([]byte, 10)
+
+	_, err = io.ReadFull(r, head)
+
+	if err != nil {
+		t.Fatalf("Failed to read, %v", err)
+	}
+
+	tail := make([]byte, 10)
+
+	_, err = r.ReadAt(tail, sz-10)
+
+	if err != nil {
+		t.Fatalf("Failed to read at %d, %v", sz-10, err)
+	}
+
+	if !bytes.Equal(tail, body[sz-10:]) {
+		t.Fatalf("Unexpected bytes read at %d: '%s'", sz-10, tail)
+	}
+
+	rest, err := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Failed to read remaining bytes, %v", err)
+	}
+
+	if !bytes.Equal(append(head, rest...), body) {
+		t.Fatalf("Unexpected bytes read")
+	}
+}
@@ -0,0 +1,170 @@
+package walk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// type checkpointCallbackKey is the type of the key used to store a `CheckpointCallbackFunction` in a `context.Context`.
+type checkpointCallbackKey struct{}
+
+// type resumeKey is the type of the key used to store the `Checkpoint` instance a walk is being resumed from in a `context.Context`.
+type resumeKey struct{}
+
+// type Checkpoint describes the position, in a list of files being walked, before which every record has been
+// dispatched to, and processed by, a callback function. Its `LineNumber` and `Offset` properties are those of the
+// next record to be processed.
+type Checkpoint struct {
+	Provenance
+	// Created is the time the checkpoint was created.
+	Created time.Time `json:"created"`
+	// Rejects is the number of records rejected, by walkers created with the `?tolerant=true` parameter, before the checkpoint.
+	Rejects int64 `json:"rejects,omitempty"`
+	// RejectsOffset is the number of bytes written to the `io.Writer` instance of the `Rejects` instance used to record rejected
+	// records (see `ContextWithRejects`) before the checkpoint.
+	RejectsOffset int64 `json:"rejects_offset,omitempty"`
+	// State is an optional dictionary of application-specific state (for example, the number of rows written to an
+	// output file) that should be restored when a walk is resumed. It is populated by `CheckpointCallbackFunction` functions.
+	State map[string]string `json:"state,omitempty"`
+}
+
+// type CheckpointCallbackFunction defines a user-specified callback function which is invoked before a checkpoint is
+// written. No other callback functions are running while it is invoked so it is safe to flush output and record
+// application state in the checkpoint's `State` property. If it returns an error the walk is stopped.
+type CheckpointCallbackFunction func(context.Context, *Checkpoint) error
+
+// ContextWithCheckpointCallback() returns a copy of 'ctx' containing 'cb' which will be invoked by `Walker` implementations
+// that support checkpoints before each checkpoint is written.
+func ContextWithCheckpointCallback(ctx context.Context, cb CheckpointCallbackFunction) context.Context {
+	return context.WithValue(ctx, checkpointCallbackKey{}, cb)
+}
+
+// checkpointCallbackFromContext() returns the `CheckpointCallbackFunction` stored in 'ctx' or nil.
+func checkpointCallbackFromContext(ctx context.Context) CheckpointCallbackFunction {
+
+	v := ctx.Value(checkpointCallbackKey{})
+
+	if v == nil {
+		return nil
+	}
+
+	return v.(CheckpointCallbackFunction)
+}
+
+// contextWithResume() returns a copy of 'ctx' containing the checkpoint 'cp' that a walk is being resumed from.
+func contextWithResume(ctx context.Context, cp *Checkpoint) context.Context {
+	return context.WithValue(ctx, resumeKey{}, cp)
+}
+
+// resumeFromContext() returns the checkpoint stored in 'ctx' and a boolean value indicating whether it was present.
+func resumeFromContext(ctx context.Context) (*Checkpoint, bool) {
+
+	v := ctx.Value(resumeKey{})
+
+	if v == nil {
+		return nil, false
+	}
+
+	return v.(*Checkpoint), true
+}
+
+// ReadCheckpoint() reads the checkpoint stored in 'path'. If 'path' does not exist the error returned will match `fs.ErrNotExist`.
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+
+	r, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open %s, %w", path, err)
+	}
+
+	defer r.Close()
+
+	var cp *Checkpoint
+
+	dec := json.NewDecoder(r)
+	err = dec.Decode(&cp)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode %s, %w", path, err)
+	}
+
+	return cp, nil
+}
+
+// WriteCheckpoint() writes 'cp' to 'path'. The checkpoint is written to a temporary file, which is then renamed, so
+// that 'path' always contains a complete checkpoint even if the application is interrupted.
+func WriteCheckpoint(path string, cp *Checkpoint) error {
+
+	fh, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary file for %s, %w", path, err)
+	}
+
+	defer os.Remove(fh.Name())
+
+	enc := json.NewEncoder(fh)
+	err = enc.Encode(cp)
+
+	if err != nil {
+		fh.Close()
+		return fmt.Errorf("Failed to encode checkpoint, %w", err)
+	}
+
+	err = fh.Sync()
+
+	if err != nil {
+		fh.Close()
+		return fmt.Errorf("Failed to sync %s, %w", fh.Name(), err)
+	}
+
+	err = fh.Close()
+
+	if err != nil {
+		return fmt.Errorf("Failed to close %s, %w", fh.Name(), err)
+	}
+
+	err = os.Rename(fh.Name(), path)
+
+	if err != nil {
+		return fmt.Errorf("Failed to rename %s, %w", fh.Name(), err)
+	}
+
+	return nil
+}
+
+// loadResumeCheckpoint() returns the checkpoint stored in 'path', or nil if 'path' does not exist.
+func loadResumeCheckpoint(path string) (*Checkpoint, error) {
+
+	cp, err := ReadCheckpoint(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// skipBytes() reads and discards 'n' bytes from 'r'. It is used to resume walking compressed files which can not be read at
+// an arbitrary offset.
+func skipBytes(r io.Reader, n int64) error {
+
+	_, err := io.CopyN(io.Discard, r, n)
+
+	if err != nil {
+		return fmt.Errorf("Failed to skip %d bytes, %w", n, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package walk
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestCheckpointResume(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir := t.TempDir()
+
+	// Two sets of 1000 records. Files contain both sets; zip archives contain one set per entry.
+
+	var first bytes.Buffer
+	var second bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&first, "{\"n\":%d}\n", i)
+		fmt.Fprintf(&second, "{\"n\":%d}\n", i+1000)
+	}
+
+	all := append(append([]byte{}, first.Bytes()...), second.Bytes()...)
+
+	// The offset of record 1500 in 'all' and of record 500 in 'second'
+
+	offset_all := int64(bytes.Index(all, []byte(`{"n":1500}`)))
+	offset_second := int64(bytes.Index(second.Bytes(), []byte(`{"n":1500}`)))
+
+	writeFile := func(name string, body []byte) string {
+
+		path := filepath.Join(dir, name)
+
+		err := os.WriteFile(path, body, 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to write %s, %v", path, err)
+		}
+
+		return path
+	}
+
+	writeZip := func(name string, method uint16) string {
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+
+		for _, e := range []struct {
+			name string
+			body []byte
+		}{
+			{"first.ndjson", first.Bytes()},
+			{"second.ndjson", second.Bytes()},
+		} {
+
+			wr, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: method})
+
+			if err != nil {
+				t.Fatalf("Failed to create %s, %v", e.name, err)
+			}
+
+			wr.Write(e.body)
+		}
+
+		zw.Close()
+		return writeFile(name, buf.Bytes())
+	}
+
+	var gz_buf bytes.Buffer
+	gz_wr := gzip.NewWriter(&gz_buf)
+	gz_wr.Write(all)
+	gz_wr.Close()
+
+	tests := map[string]struct {
+		URI    string
+		Entry  string
+		Line   int
+		Offset int64
+		End    int64
+	}{
+		"plain": {
+			URI:    writeFile("test.ndjson", all),
+			Line:   1501,
+			Offset: offset_all,
+			End:    int64(len(all)),
+		},
+		"gzip": {
+			URI:    writeFile("test.ndjson.gz", gz_buf.Bytes()),
+			Line:   1501,
+			Offset: offset_all,
+			End:    int64(len(all)),
+		},
+		"stored": {
+			URI:    writeZip("stored.zip", zip.Store),
+			Entry:  "second.ndjson",
+			Line:   501,
+			Offset: offset_second,
+			End:    int64(second.Len()),
+		},
+		"deflated": {
+			URI:    writeZip("deflated.zip", zip.Deflate),
+			Entry:  "second.ndjson",
+			Line:   501,
+			Offset: offset_second,
+			End:    int64(second.Len()),
+		},
+	}
+
+	// A file which is walked before each test file to make sure it is skipped when resuming
+
+	skipped := writeFile("skipped.ndjson", first.Bytes())
+
+	for name, test := range tests {
+
+		checkpoint := filepath.Join(dir, name+".checkpoint")
+
+		uris := []string{
+			skipped,
+			test.URI,
+		}
+
+		w, err := NewWalker(ctx, fmt.Sprintf("ndjson://?checkpoint=%s&checkpoint_interval=100&callback_workers=4", checkpoint))
+
+		if err != nil {
+			t.Fatalf("Failed to create walker for %s, %v", name, err)
+		}
+
+		walked := 0
+
+		fail_cb := func(ctx context.Context, body []byte) error {
+
+			p, _ := ProvenanceFromContext(ctx)
+
+			if p.URI == test.URI && gjson.GetBytes(body, "n").Int() == 1550 {
+				return fmt.Errorf("Failed")
+			}
+
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, fail_cb, uris...)
+
+		if err == nil {
+			t.Fatalf("Expected %s walk to fail", name)
+		}
+
+		cp, err := ReadCheckpoint(checkpoint)
+
+		if err != nil {
+			t.Fatalf("Failed to read checkpoint for %s, %v", name, err)
+		}
+
+		if cp.URI != test.URI || cp.Entry != test.Entry || cp.LineNumber != test.Line || cp.Offset != test.Offset {
+			t.Fatalf("Unexpected checkpoint for %s: %s", name, &cp.Provenance)
+		}
+
+		w, err = NewWalker(ctx, fmt.Sprintf("ndjson://?checkpoint=%s&checkpoint_interval=100&resume=true", checkpoint))
+
+		if err != nil {
+			t.Fatalf("Failed to create resume walker for %s, %v", name, err)
+		}
+
+		mu := new(sync.Mutex)
+		next := int64(1500)
+
+		resume_cb := func(ctx context.Context, body []byte) error {
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			n := gjson.GetBytes(body, "n").Int()
+
+			if n != next {
+				return fmt.Errorf("Unexpected record %d (expected %d)", n, next)
+			}
+
+			if n == 1500 {
+
+				p, _ := ProvenanceFromContext(ctx)
+
+				if p.URI != test.URI || p.Entry != test.Entry || p.LineNumber != test.Line || p.Offset != test.Offset {
+					return fmt.Errorf("Unexpected provenance for first record: %s", p)
+				}
+			}
+
+			next += 1
+			walked += 1
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, resume_cb, uris...)
+
+		if err != nil {
+			t.Fatalf("Failed to resume %s, %v", name, err)
+		}
+
+		if walked != 500 {
+			t.Fatalf("Unexpected number of records resuming %s: %d", name, walked)
+		}
+
+		cp, err = ReadCheckpoint(checkpoint)
+
+		if err != nil {
+			t.Fatalf("Failed to read final checkpoint for %s, %v", name, err)
+		}
+
+		if cp.URI != test.URI || cp.Entry != test.Entry || cp.Offset != test.End {
+			t.Fatalf("Unexpected final checkpoint for %s: %s", name, &cp.Provenance)
+		}
+
+		// Resuming a completed walk should not dispatch any records
+
+		walked = 0
+
+		err = w.WalkURIs(ctx, resume_cb, uris...)
+
+		if err != nil {
+			t.Fatalf("Failed to resume completed %s, %v", name, err)
+		}
+
+		if walked != 0 {
+			t.Fatalf("Unexpected number of records resuming completed %s: %d", name, walked)
+		}
+	}
+}
+
+func TestCheckpointCallback(t *testing.T) {
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	checkpoint := filepath.Join(t.TempDir(), "test.checkpoint")
+
+	w, err := NewWalker(ctx, fmt.Sprintf("ndjson://?checkpoint=%s&checkpoint_interval=300&callback_workers=8", checkpoint))
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	mu := new(sync.Mutex)
+	count := 0
+	checkpoints := make([]string, 0)
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		count += 1
+		return nil
+	}
+
+	// No callback functions are running when checkpoints are prepared so the number of records processed
+	// should always be the same as the number of lines before the checkpoint
+
+	checkpoint_cb := func(ctx context.Context, cp *Checkpoint) error {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if count != cp.LineNumber-1 {
+			return fmt.Errorf("Unexpected count %d for checkpoint at %s", count, &cp.Provenance)
+		}
+
+		cp.State["count"] = fmt.Sprintf("%d", count)
+		checkpoints = append(checkpoints, cp.State["count"])
+		return nil
+	}
+
+	ctx = ContextWithCheckpointCallback(ctx, checkpoint_cb)
+
+	err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("Failed to walk records, %v", err)
+	}
+
+	expected := "[300 600 900 1000]"
+
+	if fmt.Sprintf("%v", checkpoints) != expected {
+		t.Fatalf("Unexpected checkpoints: %v (expected %s)", checkpoints, expected)
+	}
+
+	cp, err := ReadCheckpoint(checkpoint)
+
+	if err != nil {
+		t.Fatalf("Failed to read checkpoint, %v", err)
+	}
+
+	if cp.State["count"] != "1000" {
+		t.Fatalf("Unexpected checkpoint state: %v", cp.State)
+	}
+}
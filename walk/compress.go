@@ -0,0 +1,195 @@
+package walk
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats.
+const (
+	// CompressionNone indicates that a file is not compressed.
+	CompressionNone string = ""
+	// CompressionGzip indicates that a file is gzip-compressed ('.gz').
+	CompressionGzip string = "gzip"
+	// CompressionBzip2 indicates that a file is bzip2-compressed ('.bz2').
+	CompressionBzip2 string = "bzip2"
+	// CompressionZstd indicates that a file is Zstandard-compressed ('.zst').
+	CompressionZstd string = "zstd"
+	// CompressionZip indicates that a file is a zip archive ('.zip').
+	CompressionZip string = "zip"
+)
+
+// magicNumbers maps the leading bytes of compressed files to their compression format.
+var magicNumbers = []struct {
+	magic       []byte
+	compression string
+}{
+	{[]byte{0x1f, 0x8b}, CompressionGzip},
+	{[]byte("BZh"), CompressionBzip2},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, CompressionZstd},
+	{[]byte("PK\x03\x04"), CompressionZip},
+	{[]byte("PK\x05\x06"), CompressionZip},
+}
+
+// CompressionFromExtension() returns the compression format derived from the extension of 'uri' or `CompressionNone`
+// if the extension is not a known compression format. Query strings and fragments are ignored.
+func CompressionFromExtension(uri string) string {
+
+	path := uri
+
+	u, err := url.Parse(uri)
+
+	if err == nil && u.Path != "" {
+		path = u.Path
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz", ".gzip":
+		return CompressionGzip
+	case ".bz2":
+		return CompressionBzip2
+	case ".zst", ".zstd":
+		return CompressionZstd
+	case ".zip":
+		return CompressionZip
+	default:
+		return CompressionNone
+	}
+}
+
+// CompressionFromMagic() returns the compression format derived from 'b', the leading bytes of a file, or
+// `CompressionNone` if they do not match a known compression format.
+func CompressionFromMagic(b []byte) string {
+
+	for _, m := range magicNumbers {
+
+		if bytes.HasPrefix(b, m.magic) {
+			return m.compression
+		}
+	}
+
+	return CompressionNone
+}
+
+// DetectCompression() returns the compression format of 'r' derived from the extension of 'uri' or, failing that,
+// from the leading bytes of 'r' (which are peeked rather than consumed).
+func DetectCompression(r *bufio.Reader, uri string) (string, error) {
+
+	compression := CompressionFromExtension(uri)
+
+	if compression != CompressionNone {
+		return compression, nil
+	}
+
+	b, err := r.Peek(4)
+
+	if err != nil && err != io.EOF {
+		return CompressionNone, fmt.Errorf("Failed to read leading bytes, %w", err)
+	}
+
+	return CompressionFromMagic(b), nil
+}
+
+// NewDecompressReader() returns an `io.ReadCloser` instance that decompresses 'r' using 'compression'. If 'compression'
+// is `CompressionNone` the data in 'r' is returned as-is. Zip archives can not be decompressed as a stream and return
+// an error.
+func NewDecompressReader(r io.Reader, compression string) (io.ReadCloser, error) {
+
+	switch compression {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+
+		gr, err := gzip.NewReader(r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create gzip reader, %w", err)
+		}
+
+		return gr, nil
+
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	case CompressionZstd:
+
+		zr, err := zstd.NewReader(r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create zstd reader, %w", err)
+		}
+
+		return zr.IOReadCloser(), nil
+
+	case CompressionZip:
+		return nil, fmt.Errorf("Zip archives can not be decompressed as a stream")
+	default:
+		return nil, fmt.Errorf("Unsupported compression format '%s'", compression)
+	}
+}
+
+// type decompressReader implements the `io.ReadCloser` interface for the decompressed contents of a `WalkReader`.
+type decompressReader struct {
+	io.Reader
+	// decompressor is the `io.ReadCloser` instance used to decompress data.
+	decompressor io.ReadCloser
+	// source is the underlying `WalkReader` instance.
+	source WalkReader
+}
+
+// Close closes the decompressor and the underlying `WalkReader` instance for 'r'.
+func (r *decompressReader) Close() error {
+
+	r.decompressor.Close()
+	return r.source.Close()
+}
+
+// OpenDecompressedURI() opens 'uri', using `OpenURI`, and returns an `io.ReadCloser` instance for its decompressed
+// contents along with the compression format of the file. The compression format is derived from the extension of 'uri'
+// or, failing that, its leading bytes. Zip archives are not decompressed: if the compression format is `CompressionZip`
+// the returned reader is for the raw archive and callers are expected to use `OpenURI` and `archive/zip` instead.
+func OpenDecompressedURI(ctx context.Context, uri string) (io.ReadCloser, string, error) {
+
+	r, _, err := OpenURI(ctx, uri)
+
+	if err != nil {
+		return nil, CompressionNone, err
+	}
+
+	br := bufio.NewReader(r)
+
+	compression, err := DetectCompression(br, uri)
+
+	if err != nil {
+		r.Close()
+		return nil, CompressionNone, fmt.Errorf("Failed to detect compression for %s, %w", uri, err)
+	}
+
+	if compression == CompressionZip {
+		return r, compression, nil
+	}
+
+	dr, err := NewDecompressReader(br, compression)
+
+	if err != nil {
+		r.Close()
+		return nil, compression, fmt.Errorf("Failed to decompress %s, %w", uri, err)
+	}
+
+	rsp := &decompressReader{
+		Reader:       dr,
+		decompressor: dr,
+		source:       r,
+	}
+
+	return rsp, compression, nil
+}
@@ -0,0 +1,124 @@
+package walk
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCompressionFromExtension(t *testing.T) {
+
+	tests := map[string]string{
+		"lcsh.both.ndjson":                                   CompressionNone,
+		"lcsh.both.ndjson.zip":                               CompressionZip,
+		"lcsh.both.ndjson.gz":                                CompressionGzip,
+		"lcsh.both.ndjson.GZ":                                CompressionGzip,
+		"lcsh.both.ndjson.bz2":                               CompressionBzip2,
+		"lcsh.both.ndjson.zst":                               CompressionZstd,
+		"https://example.com/lcsh.both.ndjson.zst?version=1": CompressionZstd,
+	}
+
+	for uri, expected := range tests {
+
+		compression := CompressionFromExtension(uri)
+
+		if compression != expected {
+			t.Fatalf("Unexpected compression for %s: '%s' (expected '%s')", uri, compression, expected)
+		}
+	}
+}
+
+func TestCompressionFromMagic(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Copy each compressed fixture to a file without an extension so that compression is derived from its
+	// leading bytes
+
+	tmp_dir := t.TempDir()
+
+	tests := map[string]string{
+		"../fixtures/lcsh.sample.ndjson":     CompressionNone,
+		"../fixtures/lcsh.sample.ndjson.zip": CompressionZip,
+		"../fixtures/lcsh.sample.ndjson.gz":  CompressionGzip,
+		"../fixtures/lcsh.sample.ndjson.bz2": CompressionBzip2,
+		"../fixtures/lcsh.sample.ndjson.zst": CompressionZstd,
+	}
+
+	for rel_path, expected := range tests {
+
+		body, err := os.ReadFile(rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", rel_path, err)
+		}
+
+		compression := CompressionFromMagic(body)
+
+		if compression != expected {
+			t.Fatalf("Unexpected compression for %s: '%s' (expected '%s')", rel_path, compression, expected)
+		}
+
+		tmp_path := filepath.Join(tmp_dir, expected+"-data")
+
+		err = os.WriteFile(tmp_path, body, 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to write %s, %v", tmp_path, err)
+		}
+
+		r, compression, err := OpenDecompressedURI(ctx, tmp_path)
+
+		if err != nil {
+			t.Fatalf("Failed to open %s, %v", tmp_path, err)
+		}
+
+		if compression != expected {
+			r.Close()
+			t.Fatalf("Unexpected compression for %s: '%s' (expected '%s')", tmp_path, compression, expected)
+		}
+
+		if compression != CompressionZip {
+
+			data, err := io.ReadAll(r)
+
+			if err != nil {
+				r.Close()
+				t.Fatalf("Failed to read %s, %v", tmp_path, err)
+			}
+
+			if len(data) != 16318 {
+				r.Close()
+				t.Fatalf("Unexpected length for decompressed %s: %d", tmp_path, len(data))
+			}
+		}
+
+		r.Close()
+
+		w, err := NewWalker(ctx, "ndjson://")
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker, %v", err)
+		}
+
+		count := int32(0)
+
+		cb := func(ctx context.Context, body []byte) error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, cb, tmp_path)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s, %v", tmp_path, err)
+		}
+
+		if count != 3 {
+			t.Fatalf("Unexpected count for %s: %d", tmp_path, count)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package walk
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+)
+
+// type walkReaderFunc is the signature of a `Walker` implementation's `WalkReader` method. It is used by the `walkURIs`,
+// `walkFile` and `walkZipFile` functions which open, decompress and unpack files for walkers whose records are read
+// sequentially from a single `io.Reader` instance.
+type walkReaderFunc func(context.Context, WalkCallbackFunction, io.Reader) error
+
+// walkURIs() processes 'uris' dispatching each record to 'cb' using 'walk_reader'. 'uris' is expected to be a list of
+// compressed ('.zip', '.gz', '.bz2' or '.zst') or uncompressed files on disk or remote (HTTP) files.
+func walkURIs(ctx context.Context, cb WalkCallbackFunction, walk_reader walkReaderFunc, uris ...string) error {
+
+	for _, uri := range uris {
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			// pass
+		}
+
+		var err error
+
+		switch CompressionFromExtension(uri) {
+		case CompressionZip:
+			err = walkZipFile(ctx, cb, walk_reader, uri)
+		default:
+			err = walkFile(ctx, cb, walk_reader, uri)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to walk %s, %w", uri, err)
+		}
+	}
+
+	return nil
+}
+
+// walkFile() processes 'uri' dispatching each record to 'cb' using 'walk_reader'. Files that are gzip, bzip2 or Zstandard
+// compressed are decompressed and zip archives are processed using `walkZipFile`, regardless of their extension.
+func walkFile(ctx context.Context, cb WalkCallbackFunction, walk_reader walkReaderFunc, uri string) error {
+
+	r, compression, err := OpenDecompressedURI(ctx, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %v", uri, err)
+	}
+
+	defer r.Close()
+
+	if compression == CompressionZip {
+		return walkZipFile(ctx, cb, walk_reader, uri)
+	}
+
+	ctx = ContextWithProvenance(ctx, &Provenance{URI: uri})
+
+	err = walk_reader(ctx, cb, r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to walk %s, %w", uri, err)
+	}
+
+	return nil
+}
+
+// walkZipFile() decompresses 'uri' and processes each file (contained in the zip archive) dispatching each record to 'cb'
+// using 'walk_reader'.
+func walkZipFile(ctx context.Context, cb WalkCallbackFunction, walk_reader walkReaderFunc, uri string) error {
+
+	r, sz, err := OpenURI(ctx, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %v", uri, err)
+	}
+
+	defer r.Close()
+
+	zr, err := zip.NewReader(r, sz)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create zip reader for %s, %v", uri, err)
+	}
+
+	for _, f := range zr.File {
+
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		zip_fh, err := f.Open()
+
+		if err != nil {
+			return fmt.Errorf("Failed to open %s, %v", f.Name, err)
+		}
+
+		entry_ctx := ContextWithProvenance(ctx, &Provenance{URI: uri, Entry: f.Name})
+
+		err = walk_reader(entry_ctx, cb, zip_fh)
+
+		zip_fh.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to walk %s, %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
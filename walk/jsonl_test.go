@@ -0,0 +1,228 @@
+package walk
+
+// These tests compare the NDJSON walker with the `aaronland/go-jsonl` walker it replaced. The go-jsonl walker reported
+// line numbers but not byte offsets, so it could not be used to record provenance, write checkpoints or resume a walk. It
+// also dropped the last line of files which do not end in a newline and stopped responding (rather than returning an error)
+// if a callback function failed. The go-jsonl package is only imported by these tests.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	jsonl_walk "github.com/aaronland/go-jsonl/walk"
+)
+
+// type parityRecord is a record, and the line it was read from, dispatched by either walker.
+type parityRecord struct {
+	// LineNumber is the (1-based) line number of the record.
+	LineNumber int
+	// Body is the body of the record with leading and trailing whitespace removed.
+	Body string
+}
+
+// walkJSONL() returns the non-empty records in 'body' read using the go-jsonl walker.
+func walkJSONL(ctx context.Context, body []byte) ([]parityRecord, error) {
+
+	record_ch := make(chan *jsonl_walk.WalkRecord)
+	error_ch := make(chan *jsonl_walk.WalkError)
+	done_ch := make(chan bool)
+
+	walk_opts := &jsonl_walk.WalkOptions{
+		RecordChannel: record_ch,
+		ErrorChannel:  error_ch,
+		DoneChannel:   done_ch,
+		Workers:       100,
+	}
+
+	go jsonl_walk.WalkReader(ctx, walk_opts, bytes.NewReader(body))
+
+	records := make([]parityRecord, 0)
+
+	for {
+		select {
+		case <-done_ch:
+			return records, nil
+		case err := <-error_ch:
+			return nil, err
+		case r := <-record_ch:
+
+			rec_body := bytes.TrimSpace(r.Body)
+
+			if len(rec_body) == 0 {
+				continue
+			}
+
+			records = append(records, parityRecord{LineNumber: r.LineNumber, Body: string(rec_body)})
+		}
+	}
+}
+
+// walkNDJSON() returns the records in 'body' read using an `NDJSONWalker` instance created from 'walker_uri'.
+func walkNDJSON(ctx context.Context, walker_uri string, body []byte) ([]parityRecord, error) {
+
+	w, err := NewWalker(ctx, walker_uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create walker, %w", err)
+	}
+
+	records := make([]parityRecord, 0)
+	mu := new(sync.Mutex)
+
+	cb := func(ctx context.Context, rec_body []byte) error {
+
+		p, _ := ProvenanceFromContext(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		records = append(records, parityRecord{LineNumber: p.LineNumber, Body: string(rec_body)})
+		return nil
+	}
+
+	err = w.WalkReader(ctx, cb, bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LineNumber < records[j].LineNumber
+	})
+
+	return records, nil
+}
+
+func TestNDJSONWalkerParity(t *testing.T) {
+
+	ctx := context.Background()
+
+	inputs := map[string][]byte{
+		"blank lines": []byte("{\"a\":1}\n\n  \n{\"b\":2}\n"),
+		"padding":     []byte("  {\"a\":1}  \r\n\t{\"b\":2}\r\n"),
+	}
+
+	for _, fname := range []string{"lcsh.sample.ndjson", "lcgft.sample.ndjson", "tgm.sample.ndjson"} {
+
+		body, err := os.ReadFile(filepath.Join("../fixtures", fname))
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", fname, err)
+		}
+
+		inputs[fname] = body
+	}
+
+	walker_uris := []string{
+		"ndjson://",
+		"ndjson://?ordered=true",
+		"ndjson://?workers=1",
+		"ndjson://?callback_workers=4",
+	}
+
+	for name, body := range inputs {
+
+		expected, err := walkJSONL(ctx, body)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s with go-jsonl, %v", name, err)
+		}
+
+		if len(expected) == 0 {
+			t.Fatalf("Expected records for %s", name)
+		}
+
+		for _, walker_uri := range walker_uris {
+
+			records, err := walkNDJSON(ctx, walker_uri, body)
+
+			if err != nil {
+				t.Fatalf("Failed to walk %s with %s, %v", name, walker_uri, err)
+			}
+
+			if len(records) != len(expected) {
+				t.Fatalf("Unexpected record count for %s with %s: %d (expected %d)", name, walker_uri, len(records), len(expected))
+			}
+
+			for i, r := range records {
+
+				if r != expected[i] {
+					t.Fatalf("Unexpected record %d for %s with %s: %v (expected %v)", i, name, walker_uri, r, expected[i])
+				}
+			}
+		}
+	}
+}
+
+func TestNDJSONWalkerDifferences(t *testing.T) {
+
+	ctx := context.Background()
+
+	// The last line is dispatched even if it does not end in a newline
+
+	body := []byte("{\"a\":1}\n{\"b\":2}")
+
+	expected, err := walkJSONL(ctx, body)
+
+	if err != nil {
+		t.Fatalf("Failed to walk with go-jsonl, %v", err)
+	}
+
+	if len(expected) != 1 {
+		t.Fatalf("Unexpected record count for go-jsonl: %d", len(expected))
+	}
+
+	records, err := walkNDJSON(ctx, "ndjson://", body)
+
+	if err != nil {
+		t.Fatalf("Failed to walk, %v", err)
+	}
+
+	if len(records) != 2 || records[1] != (parityRecord{LineNumber: 2, Body: `{"b":2}`}) {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+
+	// Blank lines are not dispatched and records do not include the trailing newline
+
+	w, err := NewWalker(ctx, "ndjson://")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	bodies := make([]string, 0)
+
+	cb := func(ctx context.Context, rec_body []byte) error {
+		bodies = append(bodies, string(rec_body))
+		return nil
+	}
+
+	err = w.WalkReader(ctx, cb, strings.NewReader("{\"a\":1}\n\n"))
+
+	if err != nil {
+		t.Fatalf("Failed to walk, %v", err)
+	}
+
+	if len(bodies) != 1 || bodies[0] != `{"a":1}` {
+		t.Fatalf("Unexpected records: %v", bodies)
+	}
+
+	// Callback errors are returned rather than blocking the walk
+
+	cb = func(ctx context.Context, rec_body []byte) error {
+		return fmt.Errorf("Invalid record")
+	}
+
+	err = w.WalkReader(ctx, cb, strings.NewReader("{\"a\":1}\n{\"b\":2}\n"))
+
+	if err == nil {
+		t.Fatalf("Expected callback error to be returned")
+	}
+}
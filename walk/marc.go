@@ -0,0 +1,193 @@
+package walk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/sfomuseum/go-libraryofcongress/marc"
+)
+
+// type MARCWalker implements the `Walker` interface for binary (ISO 2709) MARC 21 files, for example `.mrc` files.
+// Records may be encoded as MARC-8 or UTF-8. Each MARC 21 authority record is converted
+// in to the same JSON-LD (MADS/RDF) representation used by the id.loc.gov `*.both.ndjson` files before being
+// dispatched to callback functions.
+type MARCWalker struct {
+	Walker
+	// since is an optional date used to exclude records that have not been created, revised or deprecated after it.
+	since time.Time
+	// mads_opts are the options used to convert MARC 21 records in to MADS/RDF.
+	mads_opts *marc.MADSOptions
+	// tolerance describes how records which can not be read or processed are handled.
+	tolerance *tolerance
+}
+
+func init() {
+	ctx := context.Background()
+	RegisterWalker(ctx, "marc", NewMARCWalker)
+}
+
+// NewMARCWalker creates a new instance that implements the `Walker` interface for binary (ISO 2709) MARC 21 files configured
+// by 'uri' which is expected to take the form of:
+//
+//	marc://?{PARAMETERS}
+//
+// Where {PARAMETERS} may be:
+// * `?since=` An optional date ("2006-01-02", "2006-01-02T15:04:05" or RFC3339). If present only records with a
+// record info date after this date will be dispatched to callback functions.
+// * `?base=` An optional URI prefix used to derive the URI of records which do not have a Library of Congress Control
+// Number (010) belonging to a known vocabulary from their control number (001).
+// * `?tolerant=` An optional boolean value. If true records which can not be read, converted or processed are rejected rather
+// than stopping the walk, as described in `NewNDJSONWalker`. Since a file can not be read past an error the remainder of a
+// file (or zip entry) which can not be read is skipped. Default is false.
+// * `?max_errors=` The maximum number of records that can be rejected before the walk is stopped, with an error wrapping
+// `ErrTooManyRejects`. Requires `?tolerant=true`. Default is 0, in which case there is no maximum.
+func NewMARCWalker(ctx context.Context, uri string) (Walker, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	q := u.Query()
+
+	w := &MARCWalker{
+		mads_opts: &marc.MADSOptions{
+			BaseURI: q.Get("base"),
+		},
+	}
+
+	str_since := q.Get("since")
+
+	if str_since != "" {
+
+		since, err := ParseSince(str_since)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'since' parameter, %w", err)
+		}
+
+		w.since = since
+	}
+
+	tol, err := newTolerance(q)
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.tolerance = tol
+
+	return w, nil
+}
+
+// WalkURIs() processes 'uris' dispatching each record to 'cb'. 'uris' is expected to be a list of compressed ('.zip',
+// '.gz', '.bz2' or '.zst') or uncompressed files on disk or remote (HTTP) files.
+func (w *MARCWalker) WalkURIs(ctx context.Context, cb WalkCallbackFunction, uris ...string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkURIs(ctx, cb, w.WalkReader, uris...)
+}
+
+// WalkFile() processes 'uri' dispatch each record to 'cb'. Files that are gzip, bzip2 or Zstandard compressed are
+// decompressed and zip archives are processed using `WalkZipFile`, regardless of their extension.
+func (w *MARCWalker) WalkFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkFile(ctx, cb, w.WalkReader, uri)
+}
+
+// WalkZipFile() decompresses 'uri' and processes each file (contained in the zip archive) dispatching each record to 'cb'.
+func (w *MARCWalker) WalkZipFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkZipFile(ctx, cb, w.WalkReader, uri)
+}
+
+// WalkReader() processes each record in 'r' (which is expected to be a binary MARC 21 file) and dispatches the
+// MADS/RDF representation of each authority record to 'cb'. Records are processed sequentially, in document order.
+func (w *MARCWalker) WalkReader(ctx context.Context, cb WalkCallbackFunction, r io.Reader) error {
+
+	ctx = w.tolerance.context(ctx)
+
+	dec := marc.NewISO2709Decoder(r)
+	return walkMARCDecoder(ctx, cb, dec, w.since, w.mads_opts, w.tolerance)
+}
+
+// type marcDecoder is an internal interface for reading MARC 21 records, regardless of how they are encoded.
+type marcDecoder interface {
+	// Next returns the next record or `io.EOF` when there are no more records.
+	Next() (*marc.Record, error)
+	// InputOffset returns the byte offset of the current position of the decoder.
+	InputOffset() int64
+}
+
+// walkMARCDecoder() reads each record from 'dec' and dispatches the MADS/RDF representation, derived using 'mads_opts', of
+// each authority record to 'cb'. If 'since' is not a zero value records which have not been changed after it are skipped.
+// Records are processed sequentially, in the order they are read. The provenance of each record is added to the context passed
+// to 'cb' and errors are returned as `RecordError` instances unless they are rejected according to 'tol'.
+func walkMARCDecoder(ctx context.Context, cb WalkCallbackFunction, dec marcDecoder, since time.Time, mads_opts *marc.MADSOptions, tol *tolerance) error {
+
+	base, _ := ProvenanceFromContext(ctx)
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			// pass
+		}
+
+		p := base.At(0, dec.InputOffset())
+
+		rec, err := dec.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		// The decoder can not continue past a record which can not be read so the remainder of 'dec' is skipped
+
+		if err != nil {
+			return tol.reject(ctx, &RecordError{Provenance: p, Err: fmt.Errorf("Failed to read record, %w", err)}, nil)
+		}
+
+		if !rec.IsAuthority() {
+			continue
+		}
+
+		body, err := marc.ToMADS(ctx, rec, mads_opts)
+
+		if err != nil {
+
+			err := tol.reject(ctx, &RecordError{Provenance: p, Err: fmt.Errorf("Failed to convert record, %w", err)}, nil)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !since.IsZero() && !ChangedSince(body, since) {
+			continue
+		}
+
+		err = cb(ContextWithProvenance(ctx, p), body)
+
+		if err != nil {
+
+			err := tol.reject(ctx, &RecordError{Provenance: p, Err: fmt.Errorf("Failed to process record, %w", err)}, body)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package walk
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestMARCWalker(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]string{
+		"../fixtures/authorities.sample.mrc":       "Twain, Mark, 1835-1910|Broadband amplifiers|Anarchism--Italy--History--20th century|History|Amplifiers, Electronic",
+		"../fixtures/authorities.marc8.sample.mrc": "Dvořák, Antonín, 1841-1904|Brontë, Charlotte, 1816-1855|Heavy water",
+	}
+
+	for rel_path, expected := range tests {
+
+		abs_path, err := filepath.Abs(rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+		}
+
+		w, err := NewWalker(ctx, "marc://")
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker, %v", err)
+		}
+
+		labels := make([]string, 0)
+
+		cb := func(ctx context.Context, body []byte) error {
+
+			a, err := authority.ParseRecord(body)
+
+			if err != nil {
+				return err
+			}
+
+			labels = append(labels, a.Label())
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, cb, abs_path)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s, %v", abs_path, err)
+		}
+
+		if strings.Join(labels, "|") != expected {
+			t.Fatalf("Unexpected labels for %s: %v", abs_path, labels)
+		}
+	}
+}
+
+func TestMARCWalkerInvalidDirectory(t *testing.T) {
+
+	ctx := context.Background()
+
+	data, err := os.ReadFile("../fixtures/authorities.sample.mrc")
+
+	if err != nil {
+		t.Fatalf("Failed to read fixture, %v", err)
+	}
+
+	// Give the first field of the first record a negative length
+
+	copy(data[24:36], "001-99900000")
+
+	w, err := NewWalker(ctx, "marc://?tolerant=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create new walker, %v", err)
+	}
+
+	cb := func(ctx context.Context, body []byte) error {
+		return nil
+	}
+
+	var rejects_buf bytes.Buffer
+
+	rejects := NewRejects(&rejects_buf)
+
+	err = w.WalkReader(ContextWithRejects(ctx, rejects), cb, bytes.NewReader(data))
+
+	if err != nil {
+		t.Fatalf("Failed to walk, %v", err)
+	}
+
+	if rejects.Count() != 1 || !strings.Contains(rejects_buf.String(), "Invalid length for field 001") {
+		t.Fatalf("Unexpected rejects: %s", rejects_buf.String())
+	}
+}
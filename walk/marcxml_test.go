@@ -0,0 +1,71 @@
+package walk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestMARCXMLWalker(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]int{
+		"marcxml://":                  5,
+		"marcxml://?since=2010-01-01": 3,
+		"marcxml://?since=2020-01-01": 1,
+	}
+
+	paths := []string{
+		"../fixtures/authorities.sample.marcxml",
+		"../fixtures/authorities.sample.marcxml.zip",
+	}
+
+	for _, rel_path := range paths {
+
+		abs_path, err := filepath.Abs(rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+		}
+
+		for uri, expected_count := range tests {
+
+			w, err := NewWalker(ctx, uri)
+
+			if err != nil {
+				t.Fatalf("Failed to create new walker for %s, %v", uri, err)
+			}
+
+			ids := make([]string, 0)
+
+			cb := func(ctx context.Context, body []byte) error {
+
+				a, err := authority.ParseRecord(body)
+
+				if err != nil {
+					return err
+				}
+
+				ids = append(ids, a.Identifier())
+				return nil
+			}
+
+			err = w.WalkURIs(ctx, cb, abs_path)
+
+			if err != nil {
+				t.Fatalf("Failed to walk %s with %s, %v", abs_path, uri, err)
+			}
+
+			if len(ids) != expected_count {
+				t.Fatalf("Unexpected count for %s with %s: %d (expected: %d)", abs_path, uri, len(ids), expected_count)
+			}
+
+			if ids[0] != "n79021164" {
+				t.Fatalf("Unexpected first record for %s with %s: %s", abs_path, uri, ids[0])
+			}
+		}
+	}
+}
@@ -2,14 +2,17 @@ package walk
 
 import (
 	"archive/zip"
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/url"
-	"path/filepath"
 	"strconv"
+	"sync"
+	"time"
 
-	jsonl_walk "github.com/aaronland/go-jsonl/walk"
+	"github.com/tidwall/gjson"
 )
 
 // type NDJSONWalker implements the `Walker` interface for NDJSON files.
@@ -17,6 +20,25 @@ type NDJSONWalker struct {
 	Walker
 	// workers is the maximum number of simultaneous workers for processing NDJSON files
 	workers int
+	// since is an optional date used to exclude records that have not been created, revised or deprecated after it.
+	since time.Time
+	// ordered is a boolean flag indicating that records should be dispatched to callback functions in the order they
+	// appear in the source file.
+	ordered bool
+	// buffer is the maximum number of records which have been read but whose callback functions have not yet completed.
+	buffer int
+	// callback_workers is the maximum number of callback functions which may be invoked simultaneously.
+	callback_workers int
+	// checkpoint is the optional path of a file where checkpoints are written.
+	checkpoint string
+	// checkpoint_interval is the number of records dispatched to callback functions between checkpoints.
+	checkpoint_interval int
+	// resume is a boolean flag indicating that walks should resume from the checkpoint stored in 'checkpoint'.
+	resume bool
+	// shards is the number of byte ranges uncompressed files are split in to and walked concurrently.
+	shards int
+	// tolerance describes how records which can not be read or processed are handled.
+	tolerance *tolerance
 }
 
 func init() {
@@ -31,6 +53,39 @@ func init() {
 //
 // Where {PARAMETERS} may be:
 // * `?workers=` The number of maximum simultaneous workers for processing NDJSON records. Default is 100.
+// * `?since=` An optional date ("2006-01-02", "2006-01-02T15:04:05" or RFC3339). If present only records with a
+// change set or record info date after this date will be dispatched to callback functions.
+// * `?ordered=` An optional boolean value. If true records are dispatched to callback functions in the order they appear in
+// each file, while still being processed in parallel. Default is false, in which case records are dispatched in the order
+// their processing completes which may differ from run to run.
+// * `?buffer=` The maximum number of records which have been read but whose callback functions have not yet completed. This
+// bounds the memory used to reorder records when `?ordered=true`. Default is four times the number of workers or callback
+// workers, whichever is greater.
+// * `?callback_workers=` The maximum number of callback functions which may be invoked simultaneously. Default is 1, in which
+// case callback functions are invoked sequentially. If greater than 1 callback functions are invoked concurrently and must be
+// safe for concurrent use. When `?ordered=true` records are still dispatched to callback workers in order but, with more than
+// one callback worker, their callback functions may run (and complete) in any order.
+// * `?checkpoint=` The optional path of a file where checkpoints (see `Checkpoint`) are written as records are processed and
+// once each file, or zip entry, has been processed. Enabling checkpoints implies `?ordered=true`.
+// * `?checkpoint_interval=` The number of records to process between checkpoints. Default is 100000.
+// * `?resume=` An optional boolean value. If true, and `?checkpoint=` is set, walks will resume from the record after the
+// checkpoint stored in that file (if it exists). Files listed before the checkpoint's file, and zip entries before its
+// entry, are skipped. Uncompressed files and uncompressed zip entries are read from the checkpoint's offset; compressed data
+// before the checkpoint is decompressed and discarded without being parsed.
+// * `?shards=` The number of byte ranges, aligned to the start of a line, that uncompressed files are split in to. Each range
+// (shard) is read separately, using `WalkReader.ReadAt` (range requests for remote files), and walked concurrently with its
+// own 'workers' and 'callback_workers' goroutines. Default is 1. If greater than 1 callback functions are invoked concurrently
+// and must be safe for concurrent use, `?ordered=true` only orders records within each shard and the provenance of records
+// in sharded files does not include a line number. Compressed files and zip archives are always read sequentially. Shards
+// can not be combined with checkpoints.
+// * `?tolerant=` An optional boolean value. If true records which can not be read (for example because of a decompression error),
+// which are not valid JSON or whose callback function returns an error are rejected rather than stopping the walk. Rejected
+// records are recorded in the `Rejects` instance stored in the context passed to `WalkURIs` (see `ContextWithRejects`), if
+// present. Since a file can not be read past an error the remainder of a file (or zip entry) which can not be read is skipped.
+// The number of rejected records, and the number of bytes written to the rejects output, are stored in checkpoints and restored
+// when a walk is resumed. Default is false.
+// * `?max_errors=` The maximum number of records that can be rejected before the walk is stopped, with an error wrapping
+// `ErrTooManyRejects`. Requires `?tolerant=true`. Default is 0, in which case there is no maximum.
 func NewNDJSONWalker(ctx context.Context, uri string) (Walker, error) {
 
 	max_workers := 100
@@ -56,17 +111,198 @@ func NewNDJSONWalker(ctx context.Context, uri string) (Walker, error) {
 		max_workers = w
 	}
 
+	if max_workers < 1 {
+		return nil, fmt.Errorf("Invalid 'workers' parameter, must be greater than zero")
+	}
+
 	w := &NDJSONWalker{
-		workers: max_workers,
+		workers:          max_workers,
+		callback_workers: 1,
+	}
+
+	str_callback_workers := q.Get("callback_workers")
+
+	if str_callback_workers != "" {
+
+		callback_workers, err := strconv.Atoi(str_callback_workers)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'callback_workers' parameter, %w", err)
+		}
+
+		if callback_workers < 1 {
+			return nil, fmt.Errorf("Invalid 'callback_workers' parameter, must be greater than zero")
+		}
+
+		w.callback_workers = callback_workers
+	}
+
+	w.buffer = max(w.workers, w.callback_workers) * 4
+
+	str_ordered := q.Get("ordered")
+
+	if str_ordered != "" {
+
+		ordered, err := strconv.ParseBool(str_ordered)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'ordered' parameter, %w", err)
+		}
+
+		w.ordered = ordered
+	}
+
+	str_buffer := q.Get("buffer")
+
+	if str_buffer != "" {
+
+		buffer, err := strconv.Atoi(str_buffer)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'buffer' parameter, %w", err)
+		}
+
+		if buffer < 1 {
+			return nil, fmt.Errorf("Invalid 'buffer' parameter, must be greater than zero")
+		}
+
+		w.buffer = buffer
+	}
+
+	str_since := q.Get("since")
+
+	if str_since != "" {
+
+		since, err := ParseSince(str_since)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'since' parameter, %w", err)
+		}
+
+		w.since = since
+	}
+
+	w.checkpoint = q.Get("checkpoint")
+	w.checkpoint_interval = 100000
+
+	// Checkpoints are only meaningful if every record before them has been processed
+
+	if w.checkpoint != "" {
+		w.ordered = true
+	}
+
+	str_interval := q.Get("checkpoint_interval")
+
+	if str_interval != "" {
+
+		interval, err := strconv.Atoi(str_interval)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'checkpoint_interval' parameter, %w", err)
+		}
+
+		if interval < 1 {
+			return nil, fmt.Errorf("Invalid 'checkpoint_interval' parameter, must be greater than zero")
+		}
+
+		w.checkpoint_interval = interval
 	}
 
+	str_resume := q.Get("resume")
+
+	if str_resume != "" {
+
+		resume, err := strconv.ParseBool(str_resume)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'resume' parameter, %w", err)
+		}
+
+		if resume && w.checkpoint == "" {
+			return nil, fmt.Errorf("The 'resume' parameter requires a 'checkpoint' parameter")
+		}
+
+		w.resume = resume
+	}
+
+	w.shards = 1
+
+	str_shards := q.Get("shards")
+
+	if str_shards != "" {
+
+		shards, err := strconv.Atoi(str_shards)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'shards' parameter, %w", err)
+		}
+
+		if shards < 1 {
+			return nil, fmt.Errorf("Invalid 'shards' parameter, must be greater than zero")
+		}
+
+		if shards > 1 && w.checkpoint != "" {
+			return nil, fmt.Errorf("The 'shards' and 'checkpoint' parameters are mutually exclusive")
+		}
+
+		w.shards = shards
+	}
+
+	tol, err := newTolerance(q)
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.tolerance = tol
+
 	return w, nil
 }
 
-// WalkURIs() processes 'uris' dispatching each record to 'cb'. 'uris' is expected to be a list of compressed ('.zip')
-// or uncompressed files on disk.
+// WalkURIs() processes 'uris' dispatching each record to 'cb'. 'uris' is expected to be a list of compressed ('.zip',
+// '.gz', '.bz2' or '.zst') or uncompressed files on disk or remote (HTTP) files.
 func (w *NDJSONWalker) WalkURIs(ctx context.Context, cb WalkCallbackFunction, uris ...string) error {
 
+	ctx = w.tolerance.context(ctx)
+
+	if w.resume {
+
+		cp, err := w.resumeCheckpoint(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if cp != nil {
+
+			idx := -1
+
+			for i, uri := range uris {
+
+				if uri == cp.URI {
+					idx = i
+					break
+				}
+			}
+
+			if idx == -1 {
+				return fmt.Errorf("Checkpoint URI %s is not one of the URIs being walked", cp.URI)
+			}
+
+			uris = uris[idx:]
+
+			rejects, ok := RejectsFromContext(ctx)
+
+			if ok && w.tolerance.enabled {
+				rejects.restore(cp)
+			}
+		}
+
+		// Store the checkpoint (even if it is nil) so it isn't reloaded, after it has been overwritten, for each URI
+
+		ctx = contextWithResume(ctx, cp)
+	}
+
 	for _, uri := range uris {
 
 		select {
@@ -76,12 +312,10 @@ func (w *NDJSONWalker) WalkURIs(ctx context.Context, cb WalkCallbackFunction, ur
 			// pass
 		}
 
-		ext := filepath.Ext(uri)
-
 		var err error
 
-		switch ext {
-		case ".zip":
+		switch CompressionFromExtension(uri) {
+		case CompressionZip:
 			err = w.WalkZipFile(ctx, cb, uri)
 		default:
 			err = w.WalkFile(ctx, cb, uri)
@@ -95,10 +329,23 @@ func (w *NDJSONWalker) WalkURIs(ctx context.Context, cb WalkCallbackFunction, ur
 	return nil
 }
 
-// WalkFile() processes 'uri' dispatch each record to 'cb'.
+// WalkFile() processes 'uri' dispatch each record to 'cb'. Files that are gzip, bzip2 or Zstandard compressed are
+// decompressed and zip archives are processed using `WalkZipFile`, regardless of their extension.
 func (w *NDJSONWalker) WalkFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
 
-	r, _, err := OpenURI(ctx, uri)
+	ctx = w.tolerance.context(ctx)
+
+	cp, err := w.resumeCheckpoint(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if cp != nil && cp.URI == uri && cp.Entry == "" {
+		return w.resumeFile(ctx, cb, cp)
+	}
+
+	r, compression, err := OpenDecompressedURI(ctx, uri)
 
 	if err != nil {
 		return fmt.Errorf("Failed to open %s, %v", uri, err)
@@ -106,10 +353,20 @@ func (w *NDJSONWalker) WalkFile(ctx context.Context, cb WalkCallbackFunction, ur
 
 	defer r.Close()
 
+	if compression == CompressionZip {
+		return w.WalkZipFile(ctx, cb, uri)
+	}
+
+	if compression == CompressionNone && w.shards > 1 {
+		return w.walkShards(ctx, cb, uri)
+	}
+
+	ctx = ContextWithProvenance(ctx, &Provenance{URI: uri})
+
 	err = w.WalkReader(ctx, cb, r)
 
 	if err != nil {
-		return fmt.Errorf("Failed to walk %s, %v", uri, err)
+		return fmt.Errorf("Failed to walk %s, %w", uri, err)
 	}
 
 	return nil
@@ -118,6 +375,8 @@ func (w *NDJSONWalker) WalkFile(ctx context.Context, cb WalkCallbackFunction, ur
 // WalkZipFile() decompresses 'uri' and processes each file (contained in the zip archive) dispatching each record to 'cb'.
 func (w *NDJSONWalker) WalkZipFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
 
+	ctx = w.tolerance.context(ctx)
+
 	r, sz, err := OpenURI(ctx, uri)
 
 	if err != nil {
@@ -132,8 +391,35 @@ func (w *NDJSONWalker) WalkZipFile(ctx context.Context, cb WalkCallbackFunction,
 		return fmt.Errorf("Failed to create zip reader for %s, %v", uri, err)
 	}
 
+	cp, err := w.resumeCheckpoint(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// resuming is true until the zip entry containing the checkpoint has been found
+
+	resuming := cp != nil && cp.URI == uri
+
 	for _, f := range zr.File {
 
+		if resuming {
+
+			if f.Name != cp.Entry {
+				continue
+			}
+
+			resuming = false
+
+			err := w.resumeZipEntry(ctx, cb, r, f, cp)
+
+			if err != nil {
+				return fmt.Errorf("Failed to walk %s, %w", uri, err)
+			}
+
+			continue
+		}
+
 		zip_fh, err := f.Open()
 
 		if err != nil {
@@ -142,67 +428,598 @@ func (w *NDJSONWalker) WalkZipFile(ctx context.Context, cb WalkCallbackFunction,
 
 		defer zip_fh.Close()
 
-		err = w.WalkReader(ctx, cb, zip_fh)
+		entry_ctx := ContextWithProvenance(ctx, &Provenance{URI: uri, Entry: f.Name})
+
+		err = w.WalkReader(entry_ctx, cb, zip_fh)
 
 		if err != nil {
-			return fmt.Errorf("Failed to walk %s, %v", uri, err)
+			return fmt.Errorf("Failed to walk %s, %w", uri, err)
 		}
 	}
 
+	if resuming {
+		return fmt.Errorf("Checkpoint entry %s not found in %s", cp.Entry, uri)
+	}
+
 	return nil
 }
 
+// walkShards() splits 'uri', an uncompressed file, in to 'shards' byte ranges and walks each range concurrently, dispatching
+// each record to 'cb'. The first error encountered stops the walk.
+func (w *NDJSONWalker) walkShards(ctx context.Context, cb WalkCallbackFunction, uri string) error {
+
+	r, sz, err := OpenURI(ctx, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %v", uri, err)
+	}
+
+	boundaries, err := ShardBoundaries(r, sz, w.shards)
+
+	r.Close()
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive shards for %s, %w", uri, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var walk_err error
+	mu := new(sync.Mutex)
+
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < len(boundaries)-1; i++ {
+
+		wg.Add(1)
+
+		go func(start int64, end int64) {
+
+			defer wg.Done()
+
+			err := w.walkShard(ctx, cb, uri, start, end)
+
+			if err != nil {
+
+				mu.Lock()
+
+				if walk_err == nil {
+					walk_err = err
+				}
+
+				mu.Unlock()
+				cancel()
+			}
+		}(boundaries[i], boundaries[i+1])
+	}
+
+	wg.Wait()
+
+	if walk_err != nil {
+		return fmt.Errorf("Failed to walk %s, %w", uri, walk_err)
+	}
+
+	return nil
+}
+
+// walkShard() walks the records in 'uri' between the byte offsets 'start' and 'end' dispatching each record to 'cb'. Each shard
+// opens its own `WalkReader` instance since implementations which cache data (for example `RemoteWalkReader`) are not safe for
+// concurrent use.
+func (w *NDJSONWalker) walkShard(ctx context.Context, cb WalkCallbackFunction, uri string, start int64, end int64) error {
+
+	r, _, err := OpenURI(ctx, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %v", uri, err)
+	}
+
+	defer r.Close()
+
+	br := bufio.NewReaderSize(io.NewSectionReader(r, start, end-start), sectionBufferSize)
+
+	ctx = ContextWithProvenance(ctx, &Provenance{URI: uri, Offset: start})
+
+	return w.walkReader(ctx, cb, br, false)
+}
+
+// resumeCheckpoint() returns the checkpoint that walks should be resumed from, or nil if walks are not being resumed or
+// there is no checkpoint to resume from.
+func (w *NDJSONWalker) resumeCheckpoint(ctx context.Context) (*Checkpoint, error) {
+
+	if !w.resume {
+		return nil, nil
+	}
+
+	cp, ok := resumeFromContext(ctx)
+
+	if ok {
+		return cp, nil
+	}
+
+	cp, err := loadResumeCheckpoint(w.checkpoint)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load checkpoint, %w", err)
+	}
+
+	return cp, nil
+}
+
+// resumeFile() processes the records in the file described by 'cp' which follow the checkpoint, dispatching each record to 'cb'.
+// Uncompressed files are read from the checkpoint's offset using `WalkReader.ReadAt`.
+func (w *NDJSONWalker) resumeFile(ctx context.Context, cb WalkCallbackFunction, cp *Checkpoint) error {
+
+	uri := cp.URI
+
+	r, sz, err := OpenURI(ctx, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open %s, %v", uri, err)
+	}
+
+	defer r.Close()
+
+	br := bufio.NewReader(io.NewSectionReader(r, 0, sz))
+
+	compression, err := DetectCompression(br, uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to detect compression for %s, %w", uri, err)
+	}
+
+	var rr io.Reader
+
+	switch compression {
+	case CompressionNone:
+
+		if cp.Offset > sz {
+			return fmt.Errorf("Checkpoint offset %d is beyond the end of %s", cp.Offset, uri)
+		}
+
+		rr = bufio.NewReaderSize(io.NewSectionReader(r, cp.Offset, sz-cp.Offset), sectionBufferSize)
+
+	default:
+
+		dr, err := NewDecompressReader(br, compression)
+
+		if err != nil {
+			return fmt.Errorf("Failed to decompress %s, %w", uri, err)
+		}
+
+		defer dr.Close()
+
+		err = skipBytes(dr, cp.Offset)
+
+		if err != nil {
+			return fmt.Errorf("Failed to resume %s, %w", uri, err)
+		}
+
+		rr = dr
+	}
+
+	ctx = ContextWithProvenance(ctx, cp.Provenance.At(cp.LineNumber-1, cp.Offset))
+
+	err = w.WalkReader(ctx, cb, rr)
+
+	if err != nil {
+		return fmt.Errorf("Failed to walk %s, %w", uri, err)
+	}
+
+	return nil
+}
+
+// resumeZipEntry() processes the records in 'f', a zip entry in 'r', which follow the checkpoint 'cp' dispatching each record
+// to 'cb'. Uncompressed (stored) entries are read from the checkpoint's offset using `WalkReader.ReadAt`.
+func (w *NDJSONWalker) resumeZipEntry(ctx context.Context, cb WalkCallbackFunction, r WalkReader, f *zip.File, cp *Checkpoint) error {
+
+	sz := int64(f.UncompressedSize64)
+
+	if cp.Offset > sz {
+		return fmt.Errorf("Checkpoint offset %d is beyond the end of %s", cp.Offset, f.Name)
+	}
+
+	var rr io.Reader
+
+	switch f.Method {
+	case zip.Store:
+
+		offset, err := f.DataOffset()
+
+		if err != nil {
+			return fmt.Errorf("Failed to determine data offset for %s, %w", f.Name, err)
+		}
+
+		rr = bufio.NewReaderSize(io.NewSectionReader(r, offset+cp.Offset, sz-cp.Offset), sectionBufferSize)
+
+	default:
+
+		zip_fh, err := f.Open()
+
+		if err != nil {
+			return fmt.Errorf("Failed to open %s, %v", f.Name, err)
+		}
+
+		defer zip_fh.Close()
+
+		err = skipBytes(zip_fh, cp.Offset)
+
+		if err != nil {
+			return fmt.Errorf("Failed to resume %s, %w", f.Name, err)
+		}
+
+		rr = zip_fh
+	}
+
+	ctx = ContextWithProvenance(ctx, cp.Provenance.At(cp.LineNumber-1, cp.Offset))
+
+	return w.WalkReader(ctx, cb, rr)
+}
+
+// type ndjsonRecord is an internal structure describing a record read from an NDJSON file.
+type ndjsonRecord struct {
+	// seq is the (0-based) position of the record in the file.
+	seq int64
+	// body is the body of the record.
+	body []byte
+	// provenance is the provenance of the record.
+	provenance *Provenance
+	// end is the byte offset of the end of the record (and the start of the next record).
+	end int64
+	// skip is a boolean flag indicating that the record should not be dispatched to callback functions.
+	skip bool
+	// invalid is a boolean flag indicating that the record is not valid JSON and should be rejected.
+	invalid bool
+}
+
 // WalkReader() processes each record in 'r' (which is expected to a line-separate JSON document) and dispatches each record to 'cb'.
+// Records are read sequentially and processed (for example, checked against the 'since' parameter) by up to 'workers' goroutines
+// in parallel. Unless the 'ordered' parameter is true records are dispatched in the order their processing completes. Records are
+// dispatched to 'cb' by up to 'callback_workers' goroutines; if more than one 'cb' must be safe for concurrent use. The first error
+// returned by 'cb' cancels the context passed to any callback functions still running and stops the walk. The provenance of each
+// record (see `ProvenanceFromContext`) is added to the context passed to 'cb' and errors are returned as `RecordError` instances.
+// If the provenance in 'ctx' has a line number and offset they are assumed to be the number of lines and bytes which precede 'r', for
+// example when resuming from a checkpoint. If the 'checkpoint' parameter is set a checkpoint is written every 'checkpoint_interval'
+// records, once the callback functions for all the preceding records have completed, and once all the records in 'r' have been processed.
+// If the 'tolerant' parameter is true records which can not be read or processed are rejected, as described in `NewNDJSONWalker`.
 func (w *NDJSONWalker) WalkReader(ctx context.Context, cb WalkCallbackFunction, r io.Reader) error {
+	ctx = w.tolerance.context(ctx)
+	return w.walkReader(ctx, cb, r, true)
+}
+
+// walkReader() processes each record in 'r' dispatching each record to 'cb' as described in `WalkReader`. If 'count_lines' is
+// false, for example because 'r' is a shard starting at an unknown line, the provenance of each record does not include a line number.
+func (w *NDJSONWalker) walkReader(ctx context.Context, cb WalkCallbackFunction, r io.Reader, count_lines bool) error {
+
+	base, _ := ProvenanceFromContext(ctx)
+
+	at := func(line int, offset int64) *Provenance {
+
+		if !count_lines {
+			line = 0
+		}
+
+		return base.At(line, offset)
+	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	var walk_err error
+	jobs_ch := make(chan *ndjsonRecord)
+	results_ch := make(chan *ndjsonRecord)
+
+	// window is used to limit the number of records which have been read but not dispatched
+
+	window := make(chan bool, w.buffer)
+
+	// read_err is the error, if any, that stopped 'r' being read and read_body is the (partial) record being read at the time
 
-	record_ch := make(chan *jsonl_walk.WalkRecord)
-	error_ch := make(chan *jsonl_walk.WalkError)
-	done_ch := make(chan bool)
+	var read_err *RecordError
+	var read_body []byte
 
 	go func() {
 
+		defer close(jobs_ch)
+
+		br := bufio.NewReader(r)
+
+		seq := int64(0)
+		lineno := base.LineNumber
+		offset := base.Offset
+
 		for {
+
+			body, err := br.ReadBytes('\n')
+
+			if err != nil && err != io.EOF {
+
+				read_err = &RecordError{
+					Provenance: at(lineno+1, offset),
+					Err:        fmt.Errorf("Failed to read line, %w", err),
+				}
+
+				read_body = body
+				return
+			}
+
+			if len(body) == 0 && err == io.EOF {
+				return
+			}
+
+			lineno += 1
+
+			rec := &ndjsonRecord{
+				seq:        seq,
+				body:       body,
+				provenance: at(lineno, offset),
+			}
+
+			seq += 1
+			offset += int64(len(body))
+
+			rec.end = offset
+
 			select {
 			case <-ctx.Done():
-				done_ch <- true
 				return
-			case <-done_ch:
+			case window <- true:
+				// pass
+			}
+
+			select {
+			case <-ctx.Done():
 				return
-			case err := <-error_ch:
-				walk_err = err
-				done_ch <- true
+			case jobs_ch <- rec:
+				// pass
+			}
+
+			if err == io.EOF {
 				return
-			case r := <-record_ch:
+			}
+		}
+	}()
+
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < w.workers; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for rec := range jobs_ch {
+
+				rec.body = bytes.TrimSpace(rec.body)
+				rec.skip = len(rec.body) == 0 || (!w.since.IsZero() && !ChangedSince(rec.body, w.since))
 
-				err := cb(ctx, r.Body)
+				// Records are only validated if they can be rejected; otherwise invalid records are reported by callback functions
 
-				if err != nil {
-					error_ch <- &jsonl_walk.WalkError{
-						Path:       r.Path,
-						LineNumber: r.LineNumber,
-						Err:        fmt.Errorf("Failed to index feature, %w", err),
-					}
+				if w.tolerance.enabled && !rec.skip {
+					rec.invalid = !gjson.ValidBytes(rec.body)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case results_ch <- rec:
+					// pass
 				}
 			}
-		}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results_ch)
 	}()
 
-	walk_opts := &jsonl_walk.WalkOptions{
-		RecordChannel: record_ch,
-		ErrorChannel:  error_ch,
-		DoneChannel:   done_ch,
-		Workers:       w.workers,
+	var cb_err error
+	cb_mu := new(sync.Mutex)
+
+	setError := func(err error) {
+
+		cb_mu.Lock()
+
+		if cb_err == nil {
+			cb_err = err
+		}
+
+		cb_mu.Unlock()
+		cancel()
 	}
 
-	jsonl_walk.WalkReader(ctx, walk_opts, r)
+	// inflight is used to wait for callback functions to complete before a checkpoint is written
+
+	inflight := new(sync.WaitGroup)
+
+	dispatch := func(rec *ndjsonRecord) {
+
+		defer func() {
+			<-window
+			inflight.Done()
+		}()
+
+		if rec.skip || ctx.Err() != nil {
+			return
+		}
+
+		if rec.invalid {
+
+			err := w.tolerance.reject(ctx, &RecordError{Provenance: rec.provenance, Err: fmt.Errorf("Record is not valid JSON")}, rec.body)
+
+			if err != nil {
+				setError(err)
+			}
+
+			return
+		}
+
+		err := cb(ContextWithProvenance(ctx, rec.provenance), rec.body)
+
+		if err != nil {
+
+			rec_err := &RecordError{
+				Provenance: rec.provenance,
+				Err:        fmt.Errorf("Failed to process record, %w", err),
+			}
+
+			// Errors caused by the walk being stopped, for example by another callback function, are not rejections
+
+			if ctx.Err() != nil {
+				setError(rec_err)
+				return
+			}
+
+			err = w.tolerance.reject(ctx, rec_err, rec.body)
+
+			if err != nil {
+				setError(err)
+			}
+		}
+	}
+
+	// Callback functions are invoked by 'callback_workers' goroutines. Once a callback function has returned an error
+	// (or 'ctx' has been cancelled) the remaining records are drained, without being dispatched, so that nothing blocks.
+
+	cb_ch := make(chan *ndjsonRecord)
+	cb_wg := new(sync.WaitGroup)
+
+	for i := 0; i < w.callback_workers; i++ {
+
+		cb_wg.Add(1)
+
+		go func() {
+
+			defer cb_wg.Done()
+
+			for rec := range cb_ch {
+				dispatch(rec)
+			}
+		}()
+	}
+
+	// position is the position of the record after the last record dispatched to a callback worker. Because
+	// checkpoints imply ordered dispatch every record before it has been dispatched once 'inflight' is empty.
+
+	position := base.At(base.LineNumber+1, base.Offset)
+	dispatched := 0
+
+	send := func(rec *ndjsonRecord) {
+
+		inflight.Add(1)
+		cb_ch <- rec
+
+		position = rec.provenance.At(rec.provenance.LineNumber+1, rec.end)
+		dispatched += 1
+
+		if w.checkpoint == "" || dispatched%w.checkpoint_interval != 0 {
+			return
+		}
+
+		inflight.Wait()
 
-	if walk_err != nil && !jsonl_walk.IsEOFError(walk_err) {
-		return fmt.Errorf("Failed to walk document, %v", walk_err)
+		err := w.writeCheckpoint(ctx, position)
+
+		if err != nil {
+			setError(err)
+		}
+	}
+
+	// pending is the reorder buffer used when records are dispatched in order. It never contains more than
+	// 'buffer' records because records are only read once a slot in 'window' is available.
+
+	pending := make(map[int64]*ndjsonRecord)
+	next := int64(0)
+
+	for rec := range results_ch {
+
+		if !w.ordered {
+			send(rec)
+			continue
+		}
+
+		pending[rec.seq] = rec
+
+		for {
+
+			rec, ok := pending[next]
+
+			if !ok {
+				break
+			}
+
+			delete(pending, next)
+			next += 1
+
+			send(rec)
+		}
+	}
+
+	close(cb_ch)
+	cb_wg.Wait()
+
+	// If the record which could not be read is going to be rejected the checkpoint is written first so that, if the walk is
+	// resumed from it, the record is rejected (and counted) again rather than being skipped
+
+	if w.checkpoint != "" && cb_err == nil && (read_err == nil || w.tolerance.enabled) {
+
+		err := w.writeCheckpoint(ctx, position)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if cb_err != nil {
+		return cb_err
+	}
+
+	if read_err != nil {
+		return w.tolerance.reject(ctx, read_err, read_body)
+	}
+
+	return nil
+}
+
+// writeCheckpoint() writes a checkpoint for 'position', the position of the next record to be processed, to the path
+// defined by the 'checkpoint' parameter. It is not written if 'ctx' has been cancelled since not every record before
+// 'position' will have been processed. Any `CheckpointCallbackFunction` in 'ctx' is invoked before the checkpoint is written.
+func (w *NDJSONWalker) writeCheckpoint(ctx context.Context, position *Provenance) error {
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	cp := &Checkpoint{
+		Provenance: *position,
+		Created:    time.Now(),
+		State:      make(map[string]string),
+	}
+
+	rejects, ok := RejectsFromContext(ctx)
+
+	if ok && w.tolerance.enabled {
+		cp.Rejects = rejects.Count()
+		cp.RejectsOffset = rejects.Offset()
+	}
+
+	checkpoint_cb := checkpointCallbackFromContext(ctx)
+
+	if checkpoint_cb != nil {
+
+		err := checkpoint_cb(ctx, cp)
+
+		if err != nil {
+			return fmt.Errorf("Failed to prepare checkpoint at %s, %w", position, err)
+		}
+	}
+
+	err := WriteCheckpoint(w.checkpoint, cp)
+
+	if err != nil {
+		return fmt.Errorf("Failed to write checkpoint at %s, %w", position, err)
 	}
 
 	return nil
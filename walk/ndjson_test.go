@@ -1,10 +1,20 @@
 package walk
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
 )
 
 func TestNDJSONWalker(t *testing.T) {
@@ -14,6 +24,9 @@ func TestNDJSONWalker(t *testing.T) {
 	paths := map[string]int32{
 		"../fixtures/lcsh.sample.ndjson":     int32(3),
 		"../fixtures/lcsh.sample.ndjson.zip": int32(3),
+		"../fixtures/lcsh.sample.ndjson.gz":  int32(3),
+		"../fixtures/lcsh.sample.ndjson.bz2": int32(3),
+		"../fixtures/lcsh.sample.ndjson.zst": int32(3),
 	}
 
 	for rel_path, expected_count := range paths {
@@ -48,3 +61,359 @@ func TestNDJSONWalker(t *testing.T) {
 		}
 	}
 }
+
+func TestNDJSONWalkerSince(t *testing.T) {
+
+	ctx := context.Background()
+
+	rel_path := "../fixtures/lcsh.sample.ndjson"
+
+	abs_path, err := filepath.Abs(rel_path)
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+	}
+
+	tests := map[string]int32{
+		"ndjson://?since=1980-01-01": int32(3),
+		"ndjson://?since=2000-01-01": int32(2),
+		"ndjson://?since=2011-06-04": int32(1),
+		"ndjson://?since=2020-01-01": int32(0),
+	}
+
+	for uri, expected_count := range tests {
+
+		w, err := NewWalker(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker for %s, %v", uri, err)
+		}
+
+		count := int32(0)
+
+		cb := func(ctx context.Context, body []byte) error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, cb, abs_path)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s, %v", abs_path, err)
+		}
+
+		if count != expected_count {
+			t.Fatalf("Unexpected count for %s: %d (expected: %d)", uri, count, expected_count)
+		}
+	}
+
+	_, err = NewWalker(ctx, "ndjson://?since=yesterday")
+
+	if err == nil {
+		t.Fatalf("Expected invalid since parameter to fail")
+	}
+}
+
+func TestNDJSONWalkerOrdered(t *testing.T) {
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	// Include a final record without a trailing newline
+	buf.WriteString(`{"n":1000}`)
+
+	tests := []string{
+		"ndjson://?ordered=true",
+		"ndjson://?ordered=true&workers=16&buffer=1",
+		"ndjson://?ordered=true&workers=1",
+	}
+
+	for _, uri := range tests {
+
+		w, err := NewWalker(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker for %s, %v", uri, err)
+		}
+
+		next := int64(0)
+
+		cb := func(ctx context.Context, body []byte) error {
+
+			n := gjson.GetBytes(body, "n").Int()
+
+			if n != next {
+				return fmt.Errorf("Unexpected record %d (expected %d)", n, next)
+			}
+
+			next += 1
+			return nil
+		}
+
+		err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+		if err != nil {
+			t.Fatalf("Failed to walk records with %s, %v", uri, err)
+		}
+
+		if next != 1001 {
+			t.Fatalf("Unexpected number of records with %s: %d", uri, next)
+		}
+	}
+}
+
+func TestNDJSONWalkerInvalidParameters(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := []string{
+		"ndjson://?ordered=maybe",
+		"ndjson://?buffer=0",
+		"ndjson://?workers=0",
+		"ndjson://?callback_workers=0",
+		"ndjson://?shards=0",
+		"ndjson://?shards=2&checkpoint=test.checkpoint",
+	}
+
+	for _, uri := range tests {
+
+		_, err := NewWalker(ctx, uri)
+
+		if err == nil {
+			t.Fatalf("Expected %s to fail", uri)
+		}
+	}
+}
+
+func TestNDJSONWalkerCallbackWorkers(t *testing.T) {
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	tests := []string{
+		"ndjson://?callback_workers=8",
+		"ndjson://?callback_workers=8&ordered=true&buffer=8",
+		"ndjson://?callback_workers=8&workers=1",
+	}
+
+	for _, uri := range tests {
+
+		w, err := NewWalker(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker for %s, %v", uri, err)
+		}
+
+		var count int64
+		var sum int64
+		var running int64
+		var max_running int64
+
+		cb := func(ctx context.Context, body []byte) error {
+
+			r := atomic.AddInt64(&running, 1)
+			defer atomic.AddInt64(&running, -1)
+
+			for {
+				m := atomic.LoadInt64(&max_running)
+
+				if r <= m || atomic.CompareAndSwapInt64(&max_running, m, r) {
+					break
+				}
+			}
+
+			time.Sleep(100 * time.Microsecond)
+
+			atomic.AddInt64(&count, 1)
+			atomic.AddInt64(&sum, gjson.GetBytes(body, "n").Int())
+			return nil
+		}
+
+		err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+		if err != nil {
+			t.Fatalf("Failed to walk records with %s, %v", uri, err)
+		}
+
+		if count != 1000 || sum != 499500 {
+			t.Fatalf("Unexpected records with %s: count %d, sum %d", uri, count, sum)
+		}
+
+		if max_running < 2 || max_running > 8 {
+			t.Fatalf("Unexpected number of simultaneous callbacks with %s: %d", uri, max_running)
+		}
+	}
+}
+
+func TestNDJSONWalkerCallbackWorkersError(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	w, err := NewWalker(ctx, "ndjson://?callback_workers=4&ordered=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create new walker, %v", err)
+	}
+
+	// Records after the failing record block until their context is cancelled so the walk can
+	// only complete if the first error cancels the callbacks still running.
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		n := gjson.GetBytes(body, "n").Int()
+
+		switch {
+		case n < 10:
+			return nil
+		case n == 10:
+			return fmt.Errorf("Invalid record %d", n)
+		default:
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+
+	err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+	if err == nil {
+		t.Fatalf("Expected walk to fail")
+	}
+
+	var rec_err *RecordError
+
+	if !errors.As(err, &rec_err) {
+		t.Fatalf("Expected a RecordError, got %v", err)
+	}
+
+	if rec_err.Provenance.LineNumber != 11 {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if ctx.Err() != nil {
+		t.Fatalf("Walk did not stop before timeout, %v", err)
+	}
+}
+
+func TestNDJSONWalkerShards(t *testing.T) {
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	body := buf.Bytes()
+
+	path := filepath.Join(t.TempDir(), "test.ndjson")
+
+	err := os.WriteFile(path, body, 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	// http.ServeContent supports range requests
+
+	server := httptest.NewServer(http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		http.ServeContent(rsp, req, "test.ndjson", time.Now(), bytes.NewReader(body))
+	}))
+
+	defer server.Close()
+
+	uris := []string{
+		path,
+		server.URL + "/test.ndjson",
+	}
+
+	for _, uri := range uris {
+
+		for _, shards := range []int{1, 4, 16} {
+
+			w, err := NewWalker(ctx, fmt.Sprintf("ndjson://?shards=%d&callback_workers=2", shards))
+
+			if err != nil {
+				t.Fatalf("Failed to create walker with %d shards, %v", shards, err)
+			}
+
+			mu := new(sync.Mutex)
+			seen := make(map[int64]bool)
+
+			cb := func(ctx context.Context, rec []byte) error {
+
+				p, _ := ProvenanceFromContext(ctx)
+
+				if !bytes.HasPrefix(body[p.Offset:], rec) {
+					return fmt.Errorf("Unexpected offset %d for %s", p.Offset, rec)
+				}
+
+				if shards > 1 && p.LineNumber != 0 {
+					return fmt.Errorf("Unexpected line number %d for %s", p.LineNumber, rec)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				n := gjson.GetBytes(rec, "n").Int()
+
+				if seen[n] {
+					return fmt.Errorf("Record %d dispatched more than once", n)
+				}
+
+				seen[n] = true
+				return nil
+			}
+
+			err = w.WalkFile(ctx, cb, uri)
+
+			if err != nil {
+				t.Fatalf("Failed to walk %s with %d shards, %v", uri, shards, err)
+			}
+
+			if len(seen) != 1000 {
+				t.Fatalf("Unexpected number of records walking %s with %d shards: %d", uri, shards, len(seen))
+			}
+		}
+	}
+
+	w, err := NewWalker(ctx, "ndjson://?shards=4")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	cb := func(ctx context.Context, rec []byte) error {
+
+		if gjson.GetBytes(rec, "n").Int() == 700 {
+			return fmt.Errorf("Invalid record")
+		}
+
+		return nil
+	}
+
+	err = w.WalkFile(ctx, cb, path)
+
+	var rec_err *RecordError
+
+	if !errors.As(err, &rec_err) || rec_err.Provenance.Offset != int64(bytes.Index(body, []byte(`{"n":700}`))) {
+		t.Fatalf("Unexpected error walking shards, %v", err)
+	}
+}
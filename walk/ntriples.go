@@ -0,0 +1,207 @@
+package walk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/sfomuseum/go-libraryofcongress/ntriples"
+)
+
+// type NTriplesWalker implements the `Walker` interface for RDF N-Triples and Turtle files, for example the id.loc.gov
+// `*.nt` and `*.ttl` dumps. Triples are grouped in to records, either per subject or per authority cluster, and each record is converted
+// in to the same JSON-LD representation used by the id.loc.gov `*.both.ndjson` files before being dispatched to
+// callback functions.
+type NTriplesWalker struct {
+	Walker
+	// since is an optional date used to exclude records that have not been created, revised or deprecated after it.
+	since time.Time
+	// group is the mode used to group triples in to records.
+	group string
+	// turtle is a boolean flag indicating that files are Turtle, rather than N-Triples, documents.
+	turtle bool
+	// tolerance describes how records which can not be read or processed are handled.
+	tolerance *tolerance
+}
+
+func init() {
+	ctx := context.Background()
+	RegisterWalker(ctx, "ntriples", NewNTriplesWalker)
+	RegisterWalker(ctx, "turtle", NewNTriplesWalker)
+}
+
+// NewNTriplesWalker creates a new instance that implements the `Walker` interface for RDF N-Triples, or Turtle, files
+// configured by 'uri' which is expected to take the form of:
+//
+//	ntriples://?{PARAMETERS}
+//	turtle://?{PARAMETERS}
+//
+// The `turtle://` scheme reads Turtle documents using `ntriples.TurtleDecoder`. The triples abbreviated by each Turtle
+// statement (prefixed names, predicate and object lists, "[]" blank nodes and collections) are expanded before they are
+// grouped in to records, so the same records are dispatched for equivalent N-Triples and Turtle documents.
+//
+// Where {PARAMETERS} may be:
+// * `?since=` An optional date ("2006-01-02", "2006-01-02T15:04:05" or RFC3339). If present only records with a
+// record info date after this date will be dispatched to callback functions.
+// * `?group=` An optional mode used to group triples in to records. Valid options are "cluster" (each authority and the
+// resources serialized alongside it, which is the layout of the id.loc.gov dumps) and "subject" (each named subject
+// and the blank nodes that follow it, for sorted dumps). Default is "cluster".
+// * `?tolerant=` An optional boolean value. If true records which can not be read, converted or processed are rejected rather
+// than stopping the walk, as described in `NewNDJSONWalker`. Since a file can not be read past an error the remainder of a
+// file (or zip entry) which can not be read is skipped. Default is false.
+// * `?max_errors=` The maximum number of records that can be rejected before the walk is stopped, with an error wrapping
+// `ErrTooManyRejects`. Requires `?tolerant=true`. Default is 0, in which case there is no maximum.
+func NewNTriplesWalker(ctx context.Context, uri string) (Walker, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	q := u.Query()
+
+	w := &NTriplesWalker{
+		group:  ntriples.GroupCluster,
+		turtle: u.Scheme == "turtle",
+	}
+
+	str_group := q.Get("group")
+
+	switch str_group {
+	case "":
+		// pass
+	case ntriples.GroupCluster, ntriples.GroupSubject:
+		w.group = str_group
+	default:
+		return nil, fmt.Errorf("Invalid 'group' parameter '%s'", str_group)
+	}
+
+	str_since := q.Get("since")
+
+	if str_since != "" {
+
+		since, err := ParseSince(str_since)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'since' parameter, %w", err)
+		}
+
+		w.since = since
+	}
+
+	tol, err := newTolerance(q)
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.tolerance = tol
+
+	return w, nil
+}
+
+// WalkURIs() processes 'uris' dispatching each record to 'cb'. 'uris' is expected to be a list of compressed ('.zip',
+// '.gz', '.bz2' or '.zst') or uncompressed files on disk or remote (HTTP) files.
+func (w *NTriplesWalker) WalkURIs(ctx context.Context, cb WalkCallbackFunction, uris ...string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkURIs(ctx, cb, w.WalkReader, uris...)
+}
+
+// WalkFile() processes 'uri' dispatch each record to 'cb'. Files that are gzip, bzip2 or Zstandard compressed are
+// decompressed and zip archives are processed using `WalkZipFile`, regardless of their extension.
+func (w *NTriplesWalker) WalkFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkFile(ctx, cb, w.WalkReader, uri)
+}
+
+// WalkZipFile() decompresses 'uri' and processes each file (contained in the zip archive) dispatching each record to 'cb'.
+func (w *NTriplesWalker) WalkZipFile(ctx context.Context, cb WalkCallbackFunction, uri string) error {
+
+	ctx = w.tolerance.context(ctx)
+	return walkZipFile(ctx, cb, w.WalkReader, uri)
+}
+
+// WalkReader() processes each record in 'r' (which is expected to be an N-Triples or, for `turtle://` walkers, a Turtle
+// document) and dispatches its JSON-LD representation to 'cb'. Records are processed sequentially, in document order.
+// The provenance of each record (the line number and byte offset of its first triple, or Turtle statement) is added
+// to the context passed to 'cb' and errors are returned as `RecordError` instances unless they are rejected because of
+// the 'tolerant' parameter.
+func (w *NTriplesWalker) WalkReader(ctx context.Context, cb WalkCallbackFunction, r io.Reader) error {
+
+	ctx = w.tolerance.context(ctx)
+
+	base, _ := ProvenanceFromContext(ctx)
+
+	var dec ntriples.TripleDecoder
+
+	if w.turtle {
+		dec = ntriples.NewTurtleDecoder(r)
+	} else {
+		dec = ntriples.NewDecoder(r)
+	}
+
+	rr, err := ntriples.NewRecordReaderWithDecoder(dec, w.group)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create record reader, %w", err)
+	}
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			// pass
+		}
+
+		rec, err := rr.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		// The record reader can not continue past a triple which can not be read so the remainder of 'r' is skipped
+
+		if err != nil {
+			return w.tolerance.reject(ctx, &RecordError{Provenance: base.At(rr.Line(), rr.InputOffset()), Err: fmt.Errorf("Failed to read record, %w", err)}, nil)
+		}
+
+		p := base.At(rec.Line, rec.Offset)
+
+		body, err := ntriples.ToJSONLD(rec)
+
+		if err != nil {
+
+			err := w.tolerance.reject(ctx, &RecordError{Provenance: p, Err: fmt.Errorf("Failed to convert record %s, %w", rec.About, err)}, nil)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !w.since.IsZero() && !ChangedSince(body, w.since) {
+			continue
+		}
+
+		err = cb(ContextWithProvenance(ctx, p), body)
+
+		if err != nil {
+
+			err := w.tolerance.reject(ctx, &RecordError{Provenance: p, Err: fmt.Errorf("Failed to process record %s, %w", rec.About, err)}, body)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
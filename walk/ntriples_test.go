@@ -0,0 +1,159 @@
+package walk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sfomuseum/go-libraryofcongress/authority"
+)
+
+func TestNTriplesWalker(t *testing.T) {
+
+	ctx := context.Background()
+
+	ndjson_path, err := filepath.Abs("../fixtures/lcsh.sample.ndjson")
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for NDJSON fixture, %v", err)
+	}
+
+	nt_path, err := filepath.Abs("../fixtures/lcsh.sample.nt")
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for N-Triples fixture, %v", err)
+	}
+
+	ttl_path, err := filepath.Abs("../fixtures/lcsh.sample.ttl")
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for Turtle fixture, %v", err)
+	}
+
+	expected := make([]*authority.Authority, 0)
+
+	fh, err := os.Open(ndjson_path)
+
+	if err != nil {
+		t.Fatalf("Failed to open %s, %v", ndjson_path, err)
+	}
+
+	defer fh.Close()
+
+	scanner := bufio.NewScanner(fh)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+
+	for scanner.Scan() {
+
+		a, err := authority.ParseRecord(scanner.Bytes())
+
+		if err != nil {
+			t.Fatalf("Failed to parse NDJSON record, %v", err)
+		}
+
+		expected = append(expected, a)
+	}
+
+	// The Turtle fixture contains the same triples as the N-Triples fixture using prefixes, predicate and object lists,
+	// "[]" blank nodes and collections
+
+	paths := map[string]string{
+		"ntriples://":               nt_path,
+		"ntriples://?group=cluster": nt_path,
+		"turtle://":                 ttl_path,
+		"turtle://?group=cluster":   ttl_path,
+	}
+
+	for uri, path := range paths {
+
+		w, err := NewWalker(ctx, uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker for %s, %v", uri, err)
+		}
+
+		records := make([]*authority.Authority, 0)
+
+		cb := func(ctx context.Context, body []byte) error {
+
+			a, err := authority.ParseRecord(body)
+
+			if err != nil {
+				return err
+			}
+
+			records = append(records, a)
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, cb, path)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s with %s, %v", path, uri, err)
+		}
+
+		if len(records) != len(expected) {
+			t.Fatalf("Expected %d records with %s but got %d", len(expected), uri, len(records))
+		}
+
+		for idx, a := range records {
+
+			if !reflect.DeepEqual(a, expected[idx]) {
+
+				enc_a, _ := json.Marshal(a)
+				enc_e, _ := json.Marshal(expected[idx])
+
+				t.Fatalf("Unexpected record %d with %s, expected %s but got %s", idx, uri, enc_e, enc_a)
+			}
+		}
+	}
+}
+
+func TestNTriplesWalkerSince(t *testing.T) {
+
+	ctx := context.Background()
+
+	nt_path, err := filepath.Abs("../fixtures/lcsh.sample.nt")
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path, %v", err)
+	}
+
+	w, err := NewWalker(ctx, "ntriples://?since=2100-01-01")
+
+	if err != nil {
+		t.Fatalf("Failed to create new walker, %v", err)
+	}
+
+	count := 0
+
+	cb := func(ctx context.Context, body []byte) error {
+		count += 1
+		return nil
+	}
+
+	err = w.WalkURIs(ctx, cb, nt_path)
+
+	if err != nil {
+		t.Fatalf("Failed to walk %s, %v", nt_path, err)
+	}
+
+	if count != 0 {
+		t.Fatalf("Expected no records but got %d", count)
+	}
+}
+
+func TestNTriplesWalkerInvalidGroup(t *testing.T) {
+
+	ctx := context.Background()
+
+	_, err := NewWalker(ctx, "ntriples://?group=graph")
+
+	if err == nil {
+		t.Fatalf("Expected invalid 'group' parameter to fail")
+	}
+}
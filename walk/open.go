@@ -10,7 +10,9 @@ import (
 )
 
 // OpenURI opens 'uri' a and returns an `io.Reader` and the size of the file. If 'uri' is
-// prefixed with "https://" then the body of the file will be retrieved via an HTTP GET request.
+// prefixed with "https://" then the body of the file will be retrieved via an HTTP GET request. If 'uri' is
+// a `gocloud.dev/blob` URI (for example "s3://bucket/lcsh.both.ndjson.zip", "file:///path/to/lcsh.both.ndjson.zip"
+// or "mem:///lcsh.both.ndjson.zip") then the file will be read from the corresponding bucket. See `IsBlobURI` for details.
 func OpenURI(ctx context.Context, uri string) (WalkReader, int64, error) {
 
 	var r WalkReader
@@ -38,6 +40,10 @@ func OpenURI(ctx context.Context, uri string) (WalkReader, int64, error) {
 
 	default:
 
+		if IsBlobURI(uri) {
+			return openBlobURI(ctx, uri)
+		}
+
 		fh, err := os.Open(uri)
 
 		if err != nil {
@@ -0,0 +1,110 @@
+package walk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// type provenanceKey is the type of the key used to store a `Provenance` instance in a `context.Context`.
+type provenanceKey struct{}
+
+// type Provenance describes where a record was read from.
+type Provenance struct {
+	// URI is the URI of the file the record was read from, if known.
+	URI string `json:"uri,omitempty"`
+	// Entry is the name of the file, in a zip archive, the record was read from, if applicable.
+	Entry string `json:"entry,omitempty"`
+	// LineNumber is the (1-based) line number of the start of the record, if applicable. Binary (ISO 2709) MARC and
+	// MARCXML records do not have line numbers.
+	LineNumber int `json:"line,omitempty"`
+	// Offset is the byte offset of the start of the record in the (decompressed) file or zip entry. For MARCXML records
+	// this is the offset of the end of the previous record.
+	Offset int64 `json:"offset"`
+}
+
+// type WalkProvenanceCallbackFunction defines a user-specified callback function for processing a LoC data file which
+// is passed the provenance of each record.
+type WalkProvenanceCallbackFunction func(context.Context, []byte, *Provenance) error
+
+// WithProvenance() returns a `WalkCallbackFunction` which invokes 'cb' with the provenance of each record.
+func WithProvenance(cb WalkProvenanceCallbackFunction) WalkCallbackFunction {
+
+	return func(ctx context.Context, body []byte) error {
+
+		p, _ := ProvenanceFromContext(ctx)
+		return cb(ctx, body, p)
+	}
+}
+
+// ContextWithProvenance() returns a copy of 'ctx' containing 'p'. `Walker` implementations add the provenance of each
+// record to the context passed to callback functions. Applications calling `Walker.WalkReader` directly can use this method
+// to record the URI (and zip entry) of the reader being walked.
+func ContextWithProvenance(ctx context.Context, p *Provenance) context.Context {
+	return context.WithValue(ctx, provenanceKey{}, p)
+}
+
+// ProvenanceFromContext() returns the `Provenance` instance stored in 'ctx' and a boolean value indicating whether it
+// was present. If it was not present an empty `Provenance` instance is returned.
+func ProvenanceFromContext(ctx context.Context) (*Provenance, bool) {
+
+	v := ctx.Value(provenanceKey{})
+
+	if v == nil {
+		return &Provenance{}, false
+	}
+
+	return v.(*Provenance), true
+}
+
+// At() returns a copy of 'p' with the line number and offset of a record.
+func (p *Provenance) At(line int, offset int64) *Provenance {
+
+	rsp := *p
+	rsp.LineNumber = line
+	rsp.Offset = offset
+
+	return &rsp
+}
+
+// String() returns a human-readable description of 'p', for example "lcsh.both.ndjson.zip#lcsh.both.ndjson line 12 (offset 3456)".
+func (p *Provenance) String() string {
+
+	parts := make([]string, 0)
+
+	uri := p.URI
+
+	if p.Entry != "" {
+		uri = fmt.Sprintf("%s#%s", uri, p.Entry)
+	}
+
+	if uri != "" {
+		parts = append(parts, uri)
+	}
+
+	if p.LineNumber > 0 {
+		parts = append(parts, fmt.Sprintf("line %d", p.LineNumber))
+	}
+
+	parts = append(parts, fmt.Sprintf("(offset %d)", p.Offset))
+
+	return strings.Join(parts, " ")
+}
+
+// type RecordError is an error that occurred while reading or processing a record. It includes the provenance of the record.
+type RecordError struct {
+	// Provenance is the provenance of the record.
+	Provenance *Provenance
+	// Err is the underlying error.
+	Err error
+}
+
+// Error() returns the string value of 'e'.
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%s at %s", e.Err, e.Provenance)
+}
+
+// Unwrap() returns the underlying error of 'e'.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
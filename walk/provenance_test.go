@@ -0,0 +1,132 @@
+package walk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWalkProvenance(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := []struct {
+		walker_uri string
+		rel_path   string
+		entry      string
+		expected   []string
+	}{
+		{"ndjson://?ordered=true", "../fixtures/lcsh.sample.ndjson", "", []string{"1:0", "2:5716", "3:10299"}},
+		{"ndjson://?ordered=true", "../fixtures/lcsh.sample.ndjson.zip", "lcsh.sample.ndjson", []string{"1:0", "2:5716", "3:10299"}},
+		{"ndjson://?ordered=true", "../fixtures/lcsh.sample.ndjson.gz", "", []string{"1:0", "2:5716", "3:10299"}},
+		{"ntriples://", "../fixtures/lcsh.sample.nt", "", []string{"1:0", "78:10203", "139:18067"}},
+		{"marc://", "../fixtures/authorities.sample.mrc", "", []string{"0:0", "0:726", "0:1131", "0:1349", "0:1624"}},
+	}
+
+	for _, test := range tests {
+
+		abs_path, err := filepath.Abs(test.rel_path)
+
+		if err != nil {
+			t.Fatalf("Failed to derive absolute path for %s, %v", test.rel_path, err)
+		}
+
+		w, err := NewWalker(ctx, test.walker_uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create new walker for %s, %v", test.walker_uri, err)
+		}
+
+		positions := make([]string, 0)
+
+		cb := func(ctx context.Context, body []byte, p *Provenance) error {
+
+			if p.URI != abs_path {
+				return fmt.Errorf("Unexpected URI '%s'", p.URI)
+			}
+
+			if p.Entry != test.entry {
+				return fmt.Errorf("Unexpected entry '%s'", p.Entry)
+			}
+
+			positions = append(positions, fmt.Sprintf("%d:%d", p.LineNumber, p.Offset))
+			return nil
+		}
+
+		err = w.WalkURIs(ctx, WithProvenance(cb), abs_path)
+
+		if err != nil {
+			t.Fatalf("Failed to walk %s, %v", abs_path, err)
+		}
+
+		if strings.Join(positions, " ") != strings.Join(test.expected, " ") {
+			t.Fatalf("Unexpected positions for %s: %v", abs_path, positions)
+		}
+	}
+}
+
+func TestWalkProvenanceError(t *testing.T) {
+
+	ctx := context.Background()
+
+	rel_path := "../fixtures/lcsh.sample.ndjson.zip"
+
+	abs_path, err := filepath.Abs(rel_path)
+
+	if err != nil {
+		t.Fatalf("Failed to derive absolute path for %s, %v", rel_path, err)
+	}
+
+	w, err := NewWalker(ctx, "ndjson://?ordered=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create new walker, %v", err)
+	}
+
+	count := 0
+	cb_err := fmt.Errorf("Invalid record")
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		count += 1
+
+		if count == 2 {
+			return cb_err
+		}
+
+		return nil
+	}
+
+	err = w.WalkURIs(ctx, cb, abs_path)
+
+	if err == nil {
+		t.Fatalf("Expected walk to fail")
+	}
+
+	if !errors.Is(err, cb_err) {
+		t.Fatalf("Expected error to wrap callback error, %v", err)
+	}
+
+	var rec_err *RecordError
+
+	if !errors.As(err, &rec_err) {
+		t.Fatalf("Expected error to be a RecordError, %v", err)
+	}
+
+	p := rec_err.Provenance
+
+	if p.URI != abs_path || p.Entry != "lcsh.sample.ndjson" || p.LineNumber != 2 || p.Offset != 5716 {
+		t.Fatalf("Unexpected provenance: %s", p)
+	}
+
+	if !strings.Contains(err.Error(), "lcsh.sample.ndjson.zip#lcsh.sample.ndjson line 2 (offset 5716)") {
+		t.Fatalf("Unexpected error message: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("Expected walk to stop after the second record, got %d", count)
+	}
+}
@@ -1,9 +1,12 @@
 package walk
 
 import (
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/jeffallen/seekinghttp"
+	"gocloud.dev/blob"
 )
 
 // WalkReader is an interface which implements the `io.Reader`, `io.ReaderAt` and `io.Closer` interface
@@ -64,3 +67,114 @@ func (r *RemoteWalkReader) ReadAt(p []byte, off int64) (int, error) {
 func (r *RemoteWalkReader) Close() error {
 	return nil
 }
+
+// type BlobWalkReader implements the `WalkReader` interface for files stored in a `gocloud.dev/blob` bucket, for example
+// an S3 or Google Cloud Storage bucket.
+type BlobWalkReader struct {
+	WalkReader
+	// bucket is the `blob.Bucket` instance that 'reader' was created from, if it was opened for (and is closed with) 'r'.
+	bucket *blob.Bucket
+	// reader is the underlying `blob.Reader` instance used to read data.
+	reader *blob.Reader
+	// last is the most recent block of data retrieved by ReadAt.
+	last []byte
+	// last_offset is the offset of 'last' in the file.
+	last_offset int64
+}
+
+// blobReadAtBlockSize is the minimum number of bytes retrieved by each (range) request made by `BlobWalkReader.ReadAt`.
+// Reading a zip archive involves many small reads so retrieving (and caching) larger blocks reduces the number of requests.
+const blobReadAtBlockSize int = 1024 * 1024
+
+// Read reads up to len(p) bytes into p. It returns the number of bytes read (0 <= n <= len(p)) and any error encountered. Even if Read returns n < len(p), it may use all of p as scratch space during the call. If some data is available but not len(p) bytes, Read conventionally returns what is available instead of waiting for more.
+func (r *BlobWalkReader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
+
+// ReadAt reads len(buf) bytes into buf starting at offset off. Data is retrieved by seeking the underlying `blob.Reader`
+// instance, which issues a range request, in blocks of (at least) 1MB and the most recent block is cached. The offset used
+// by Read is not changed.
+func (r *BlobWalkReader) ReadAt(p []byte, off int64) (int, error) {
+
+	if off < 0 {
+		return 0, fmt.Errorf("Invalid offset %d", off)
+	}
+
+	end := off + int64(len(p))
+
+	if r.last == nil || off < r.last_offset || end > r.last_offset+int64(len(r.last)) {
+
+		length := len(p)
+
+		if length < blobReadAtBlockSize {
+			length = blobReadAtBlockSize
+		}
+
+		block, err := r.readBlock(off, length)
+
+		if err != nil {
+			return 0, err
+		}
+
+		r.last = block
+		r.last_offset = off
+	}
+
+	n := copy(p, r.last[off-r.last_offset:])
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// readBlock() returns up to 'length' bytes starting at 'off' and then restores the offset of the underlying `blob.Reader`
+// instance. Seeking is lazy so the reader is only recreated if Read is subsequently called.
+func (r *BlobWalkReader) readBlock(off int64, length int) ([]byte, error) {
+
+	current, err := r.reader.Seek(0, io.SeekCurrent)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.reader.Seek(off, io.SeekStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block := make([]byte, length)
+	n, err := io.ReadFull(r.reader, block)
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	_, err = r.reader.Seek(current, io.SeekStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return block[:n], nil
+}
+
+// Close closes the underlying `blob.Reader` instance for 'r' and, if it was opened for 'r', the `blob.Bucket` instance
+// it was created from.
+func (r *BlobWalkReader) Close() error {
+
+	err := r.reader.Close()
+
+	if r.bucket != nil {
+
+		bucket_err := r.bucket.Close()
+
+		if err == nil {
+			err = bucket_err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,233 @@
+package walk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// ErrTooManyRejects is the error returned once the number of records rejected by a `Walker` exceeds its 'max_errors' parameter.
+var ErrTooManyRejects = errors.New("Too many rejected records")
+
+// type rejectsKey is the type of the key used to store a `Rejects` instance in a `context.Context`.
+type rejectsKey struct{}
+
+// type Reject describes a record which was rejected because it could not be read or processed. It is written to the rejects
+// file as a single line of JSON.
+type Reject struct {
+	Provenance
+	// Error is the error that caused the record to be rejected.
+	Error string `json:"error"`
+	// Record is the body of the record, if known. It is a string, rather than a JSON object, since it may not be valid JSON.
+	Record string `json:"record"`
+}
+
+// type Rejects records the records rejected by `Walker` implementations created with the `?tolerant=true` parameter. It is
+// safe for concurrent use.
+type Rejects struct {
+	// writer is the optional `io.Writer` instance where each `Reject` is written as a line of JSON.
+	writer io.Writer
+	// count is the number of records that have been rejected.
+	count int64
+	// offset is the number of bytes written to 'writer', including those written before a walk was resumed.
+	offset int64
+	// mu is an internal `sync.Mutex` instance used to serialise writes to 'writer'.
+	mu *sync.Mutex
+}
+
+// NewRejects() returns a new `Rejects` instance which writes each rejected record to 'wr' as newline-delimited JSON. If 'wr'
+// is nil rejected records are only counted.
+func NewRejects(wr io.Writer) *Rejects {
+
+	r := &Rejects{
+		writer: wr,
+		mu:     new(sync.Mutex),
+	}
+
+	return r
+}
+
+// Count() returns the number of records that have been rejected, including those rejected before a walk was resumed.
+func (r *Rejects) Count() int64 {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.count
+}
+
+// Offset() returns the number of bytes written to the `io.Writer` instance of 'r', including those written before a walk was
+// resumed. When a walk is resumed from a checkpoint the output written after this offset should be discarded.
+func (r *Rejects) Offset() int64 {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.offset
+}
+
+// restore() sets the number of rejected records and the number of bytes written for 'r' to those recorded in 'cp'.
+func (r *Rejects) restore(cp *Checkpoint) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count = cp.Rejects
+	r.offset = cp.RejectsOffset
+}
+
+// add() records that 'body' was rejected because of 'rec_err' and returns the number of records that have been rejected.
+func (r *Rejects) add(rec_err *RecordError, body []byte) (int64, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count += 1
+
+	if r.writer == nil {
+		return r.count, nil
+	}
+
+	rec := &Reject{
+		Provenance: *rec_err.Provenance,
+		Error:      rec_err.Err.Error(),
+		Record:     string(body),
+	}
+
+	enc_rec, err := json.Marshal(rec)
+
+	if err != nil {
+		return r.count, fmt.Errorf("Failed to encode rejected record, %w", err)
+	}
+
+	enc_rec = append(enc_rec, '\n')
+
+	n, err := r.writer.Write(enc_rec)
+	r.offset += int64(n)
+
+	if err != nil {
+		return r.count, fmt.Errorf("Failed to write rejected record, %w", err)
+	}
+
+	return r.count, nil
+}
+
+// ContextWithRejects() returns a copy of 'ctx' containing 'r'. `Walker` implementations created with the `?tolerant=true`
+// parameter record rejected records in 'r', rather than in a `Rejects` instance which only counts them, so that applications
+// can write rejected records to a file and report how many records were rejected once the walk has completed.
+func ContextWithRejects(ctx context.Context, r *Rejects) context.Context {
+	return context.WithValue(ctx, rejectsKey{}, r)
+}
+
+// RejectsFromContext() returns the `Rejects` instance stored in 'ctx' and a boolean value indicating whether it was present.
+func RejectsFromContext(ctx context.Context) (*Rejects, bool) {
+
+	v := ctx.Value(rejectsKey{})
+
+	if v == nil {
+		return nil, false
+	}
+
+	return v.(*Rejects), true
+}
+
+// type tolerance is an internal structure, shared by `Walker` implementations, describing how records which can not be read
+// or processed are handled.
+type tolerance struct {
+	// enabled is a boolean flag indicating that records which can not be read or processed are rejected rather than stopping the walk.
+	enabled bool
+	// max_errors is the maximum number of records that can be rejected before the walk is stopped. If zero there is no maximum.
+	max_errors int64
+}
+
+// newTolerance() returns a new `tolerance` instance derived from the `?tolerant=` and `?max_errors=` parameters in 'q'.
+func newTolerance(q url.Values) (*tolerance, error) {
+
+	t := &tolerance{}
+
+	str_tolerant := q.Get("tolerant")
+
+	if str_tolerant != "" {
+
+		tolerant, err := strconv.ParseBool(str_tolerant)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'tolerant' parameter, %w", err)
+		}
+
+		t.enabled = tolerant
+	}
+
+	str_max := q.Get("max_errors")
+
+	if str_max != "" {
+
+		max_errors, err := strconv.ParseInt(str_max, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'max_errors' parameter, %w", err)
+		}
+
+		if max_errors < 0 {
+			return nil, fmt.Errorf("Invalid 'max_errors' parameter, must be zero or greater")
+		}
+
+		if max_errors > 0 && !t.enabled {
+			return nil, fmt.Errorf("The 'max_errors' parameter requires a 'tolerant' parameter")
+		}
+
+		t.max_errors = max_errors
+	}
+
+	return t, nil
+}
+
+// context() returns 'ctx' or, if rejecting records is enabled and 'ctx' does not already contain a `Rejects` instance, a copy
+// of 'ctx' containing a new `Rejects` instance which only counts rejected records.
+func (t *tolerance) context(ctx context.Context) context.Context {
+
+	if !t.enabled {
+		return ctx
+	}
+
+	_, ok := RejectsFromContext(ctx)
+
+	if ok {
+		return ctx
+	}
+
+	return ContextWithRejects(ctx, NewRejects(nil))
+}
+
+// reject() returns 'rec_err' unless rejecting records is enabled, in which case the record 'body' is recorded in the `Rejects`
+// instance stored in 'ctx' and nil is returned. Once more than 'max_errors' records have been rejected a `RecordError` wrapping
+// `ErrTooManyRejects` and the error that caused the record to be rejected is returned.
+func (t *tolerance) reject(ctx context.Context, rec_err *RecordError, body []byte) error {
+
+	if !t.enabled {
+		return rec_err
+	}
+
+	r, ok := RejectsFromContext(ctx)
+
+	if !ok {
+		return rec_err
+	}
+
+	count, err := r.add(rec_err, body)
+
+	if err != nil {
+		return &RecordError{Provenance: rec_err.Provenance, Err: err}
+	}
+
+	if t.max_errors > 0 && count > t.max_errors {
+		return &RecordError{Provenance: rec_err.Provenance, Err: fmt.Errorf("%w (%d), %w", ErrTooManyRejects, count, rec_err.Err)}
+	}
+
+	return nil
+}
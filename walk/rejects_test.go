@@ -0,0 +1,429 @@
+package walk
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+// readRejects() decodes the newline-delimited JSON rejects written to 'body'.
+func readRejects(t *testing.T, body []byte) []*Reject {
+
+	rejects := make([]*Reject, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+
+	for scanner.Scan() {
+
+		var rec *Reject
+
+		err := json.Unmarshal(scanner.Bytes(), &rec)
+
+		if err != nil {
+			t.Fatalf("Failed to decode reject, %v", err)
+		}
+
+		rejects = append(rejects, rec)
+	}
+
+	return rejects
+}
+
+func TestTolerantWalker(t *testing.T) {
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 100; i++ {
+
+		switch {
+		case i%10 == 3:
+			fmt.Fprintf(&buf, "{\"n\":%d\n", i)
+		default:
+			fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+		}
+	}
+
+	w, err := NewWalker(ctx, "ndjson://?ordered=true&callback_workers=4&tolerant=true&max_errors=20")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		n := gjson.GetBytes(body, "n").Int()
+
+		if n%10 == 7 {
+			return fmt.Errorf("Invalid record %d", n)
+		}
+
+		return nil
+	}
+
+	// 10 malformed records and 10 records which fail to be processed
+
+	var rejects_buf bytes.Buffer
+
+	rejects := NewRejects(&rejects_buf)
+
+	err = w.WalkReader(ContextWithRejects(ctx, rejects), cb, bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("Failed to walk records, %v", err)
+	}
+
+	if rejects.Count() != 20 || rejects.Offset() != int64(rejects_buf.Len()) {
+		t.Fatalf("Unexpected number of rejected records (%d) or offset (%d)", rejects.Count(), rejects.Offset())
+	}
+
+	recs := readRejects(t, rejects_buf.Bytes())
+
+	if len(recs) != 20 {
+		t.Fatalf("Unexpected number of rejects written: %d", len(recs))
+	}
+
+	for _, rec := range recs {
+
+		expected := fmt.Sprintf("Failed to process record, Invalid record %d", rec.LineNumber-1)
+
+		if (rec.LineNumber-1)%10 == 3 {
+			expected = "Record is not valid JSON"
+		}
+
+		if rec.Error != expected || !bytes.HasPrefix(buf.Bytes()[rec.Offset:], []byte(rec.Record)) {
+			t.Fatalf("Unexpected reject: %v", rec)
+		}
+	}
+
+	// Exceed the maximum number of rejected records
+
+	w, err = NewWalker(ctx, "ndjson://?ordered=true&callback_workers=4&tolerant=true&max_errors=19")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+	if !errors.Is(err, ErrTooManyRejects) {
+		t.Fatalf("Expected too many rejects error, got %v", err)
+	}
+
+	var rec_err *RecordError
+
+	if !errors.As(err, &rec_err) || rec_err.Provenance.LineNumber != 98 {
+		t.Fatalf("Unexpected error, %v", err)
+	}
+
+	// Without the 'tolerant' parameter the first error stops the walk. Records are not validated so malformed
+	// records are only reported if the callback function fails to process them.
+
+	w, err = NewWalker(ctx, "ndjson://?ordered=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	err = w.WalkReader(ctx, cb, bytes.NewReader(buf.Bytes()))
+
+	if !errors.As(err, &rec_err) || rec_err.Provenance.LineNumber != 8 {
+		t.Fatalf("Unexpected error, %v", err)
+	}
+
+	_, err = NewWalker(ctx, "ndjson://?max_errors=10")
+
+	if err == nil {
+		t.Fatalf("Expected 'max_errors' without 'tolerant' to fail")
+	}
+}
+
+func TestTolerantWalkerReadErrors(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	var gz_buf bytes.Buffer
+	gz_wr := gzip.NewWriter(&gz_buf)
+	gz_wr.Write(buf.Bytes())
+	gz_wr.Close()
+
+	// A truncated gzip file can be decompressed up to the point where it was truncated
+
+	truncated := filepath.Join(dir, "truncated.ndjson.gz")
+	complete := filepath.Join(dir, "complete.ndjson")
+
+	err := os.WriteFile(truncated, gz_buf.Bytes()[:gz_buf.Len()/2], 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", truncated, err)
+	}
+
+	err = os.WriteFile(complete, buf.Bytes(), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", complete, err)
+	}
+
+	counts := make(map[string]*int32)
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		p, _ := ProvenanceFromContext(ctx)
+		atomic.AddInt32(counts[p.URI], 1)
+		return nil
+	}
+
+	for _, walker_uri := range []string{"ndjson://?tolerant=true", "ndjson://?tolerant=true&ordered=true&checkpoint=" + filepath.Join(dir, "checkpoint.json")} {
+
+		counts[truncated] = new(int32)
+		counts[complete] = new(int32)
+
+		w, err := NewWalker(ctx, walker_uri)
+
+		if err != nil {
+			t.Fatalf("Failed to create walker, %v", err)
+		}
+
+		var rejects_buf bytes.Buffer
+
+		rejects := NewRejects(&rejects_buf)
+
+		err = w.WalkURIs(ContextWithRejects(ctx, rejects), cb, truncated, complete)
+
+		if err != nil {
+			t.Fatalf("Failed to walk with %s, %v", walker_uri, err)
+		}
+
+		recs := readRejects(t, rejects_buf.Bytes())
+
+		if len(recs) != 1 || recs[0].URI != truncated || !strings.HasPrefix(recs[0].Error, "Failed to read line") {
+			t.Fatalf("Unexpected rejects with %s: %s", walker_uri, rejects_buf.String())
+		}
+
+		if *counts[truncated] == 0 || *counts[truncated] >= 1000 || *counts[complete] != 1000 {
+			t.Fatalf("Unexpected counts with %s: %d, %d", walker_uri, *counts[truncated], *counts[complete])
+		}
+
+		if recs[0].LineNumber != int(*counts[truncated])+1 {
+			t.Fatalf("Unexpected line number for reject with %s: %d", walker_uri, recs[0].LineNumber)
+		}
+	}
+
+	// Without the 'tolerant' parameter the read error stops the walk
+
+	w, err := NewWalker(ctx, "ndjson://")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	err = w.WalkURIs(ctx, func(ctx context.Context, body []byte) error { return nil }, truncated, complete)
+
+	var rec_err *RecordError
+
+	if !errors.As(err, &rec_err) {
+		t.Fatalf("Expected read error, got %v", err)
+	}
+}
+
+func TestTolerantWalkerResume(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d}\n", i)
+	}
+
+	path := filepath.Join(dir, "test.ndjson")
+
+	err := os.WriteFile(path, buf.Bytes(), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+	walker_uri := fmt.Sprintf("ndjson://?tolerant=true&max_errors=10&checkpoint=%s&checkpoint_interval=10", checkpoint)
+
+	// Interrupt the walk after record 55, and after the checkpoint for the first 50 records has been written
+
+	w, err := NewWalker(ctx, walker_uri)
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	walk_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cb := func(ctx context.Context, body []byte) error {
+
+		n := gjson.GetBytes(body, "n").Int()
+
+		if n == 55 {
+			cancel()
+		}
+
+		if n%10 == 7 {
+			return fmt.Errorf("Invalid record %d", n)
+		}
+
+		return nil
+	}
+
+	var rejects_buf bytes.Buffer
+
+	err = w.WalkURIs(ContextWithRejects(walk_ctx, NewRejects(&rejects_buf)), cb, path)
+
+	if err != nil {
+		t.Fatalf("Failed to walk, %v", err)
+	}
+
+	cp, err := ReadCheckpoint(checkpoint)
+
+	if err != nil {
+		t.Fatalf("Failed to read checkpoint, %v", err)
+	}
+
+	if cp.LineNumber != 51 || cp.Rejects != 5 || cp.RejectsOffset != int64(len(rejects_buf.Bytes())) {
+		t.Fatalf("Unexpected checkpoint: %v", cp)
+	}
+
+	// Resume, discarding any rejects written after the checkpoint. Since the rejects recorded before the checkpoint
+	// are counted another 5 records can be rejected before 'max_errors' is exceeded.
+
+	w, err = NewWalker(ctx, walker_uri+"&resume=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	rejects_buf.Truncate(int(cp.RejectsOffset))
+
+	rejects := NewRejects(&rejects_buf)
+
+	err = w.WalkURIs(ContextWithRejects(ctx, rejects), cb, path)
+
+	if err != nil {
+		t.Fatalf("Failed to resume, %v", err)
+	}
+
+	if rejects.Count() != 10 || rejects.Offset() != int64(rejects_buf.Len()) {
+		t.Fatalf("Unexpected number of rejected records (%d) or offset (%d)", rejects.Count(), rejects.Offset())
+	}
+
+	recs := readRejects(t, rejects_buf.Bytes())
+
+	if len(recs) != 10 {
+		t.Fatalf("Unexpected number of rejects written: %d", len(recs))
+	}
+
+	for i, rec := range recs {
+
+		if rec.LineNumber != i*10+8 {
+			t.Fatalf("Unexpected reject %d: %v", i, rec)
+		}
+	}
+
+	// Resuming again, from the final checkpoint, with one more rejection exceeds 'max_errors'
+
+	cp, err = ReadCheckpoint(checkpoint)
+
+	if err != nil {
+		t.Fatalf("Failed to read checkpoint, %v", err)
+	}
+
+	if cp.Rejects != 10 {
+		t.Fatalf("Unexpected number of rejected records in final checkpoint: %d", cp.Rejects)
+	}
+
+	cp.LineNumber = 98
+	cp.Offset = int64(bytes.Index(buf.Bytes(), []byte(`{"n":97}`)))
+
+	err = WriteCheckpoint(checkpoint, cp)
+
+	if err != nil {
+		t.Fatalf("Failed to write checkpoint, %v", err)
+	}
+
+	err = w.WalkURIs(ContextWithRejects(ctx, NewRejects(nil)), cb, path)
+
+	if !errors.Is(err, ErrTooManyRejects) {
+		t.Fatalf("Expected too many rejects error, got %v", err)
+	}
+}
+
+func TestTolerantNTriplesWalker(t *testing.T) {
+
+	ctx := context.Background()
+
+	body := strings.Join([]string{
+		`<http://id.loc.gov/authorities/subjects/sh1> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "One"@en .`,
+		`<http://id.loc.gov/authorities/subjects/sh2> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Two"@en .`,
+		`<http://id.loc.gov/authorities/subjects/sh3> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Three"@en .`,
+		`<http://id.loc.gov/authorities/subjects/sh4> "Four" .`,
+		`<http://id.loc.gov/authorities/subjects/sh5> <http://www.loc.gov/mads/rdf/v1#authoritativeLabel> "Five"@en .`,
+	}, "\n")
+
+	count := 0
+
+	cb := func(ctx context.Context, body []byte) error {
+		count += 1
+		return nil
+	}
+
+	w, err := NewWalker(ctx, "ntriples://?group=subject")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	err = w.WalkReader(ctx, cb, strings.NewReader(body))
+
+	if err == nil {
+		t.Fatalf("Expected invalid triple to fail")
+	}
+
+	w, err = NewWalker(ctx, "ntriples://?group=subject&tolerant=true")
+
+	if err != nil {
+		t.Fatalf("Failed to create walker, %v", err)
+	}
+
+	count = 0
+	rejects := NewRejects(nil)
+
+	err = w.WalkReader(ContextWithRejects(ctx, rejects), cb, strings.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("Failed to walk, %v", err)
+	}
+
+	if rejects.Count() != 1 || count == 0 {
+		t.Fatalf("Unexpected number of rejected (%d) or dispatched (%d) records", rejects.Count(), count)
+	}
+}
@@ -0,0 +1,83 @@
+package walk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// sectionBufferSize is the size of the buffer used to read a section (a shard, or the remainder of a file after a checkpoint)
+// of a `WalkReader` instance. Reads from remote files are made using range requests so larger buffers mean fewer requests.
+const sectionBufferSize int = 1024 * 1024
+
+// shardScanSize is the number of bytes read at a time while looking for the start of a line.
+const shardScanSize int = 64 * 1024
+
+// ShardBoundaries() splits the first 'sz' bytes of 'r' in to (at most) 'count' byte ranges, of roughly equal size, whose
+// boundaries are aligned to the start of a line. It returns a list of offsets beginning with 0 and ending with 'sz' where
+// each consecutive pair of offsets is the start and end of a range. Fewer than 'count' ranges are returned if lines are
+// too long, or 'sz' too small, to create 'count' non-empty ranges.
+func ShardBoundaries(r io.ReaderAt, sz int64, count int) ([]int64, error) {
+
+	if count < 1 {
+		return nil, fmt.Errorf("Invalid count, must be greater than zero")
+	}
+
+	boundaries := []int64{0}
+
+	buf := make([]byte, shardScanSize)
+
+	for i := 1; i < count; i++ {
+
+		last := boundaries[len(boundaries)-1]
+		offset := max(sz*int64(i)/int64(count), last)
+
+		start, err := nextLineStart(r, sz, offset, buf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if start > last && start < sz {
+			boundaries = append(boundaries, start)
+		}
+	}
+
+	if sz > 0 {
+		boundaries = append(boundaries, sz)
+	}
+
+	return boundaries, nil
+}
+
+// nextLineStart() returns the offset of the first line in 'r' which starts at, or after, 'offset' or 'sz' if there is no
+// such line. 'buf' is used as scratch space.
+func nextLineStart(r io.ReaderAt, sz int64, offset int64, buf []byte) (int64, error) {
+
+	if offset <= 0 {
+		return 0, nil
+	}
+
+	// Start with the byte before 'offset' since, if it is a newline, 'offset' is the start of a line
+
+	pos := offset - 1
+
+	for pos < sz {
+
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), sz-pos)], pos)
+
+		if n == 0 && err != nil {
+			return 0, fmt.Errorf("Failed to read at offset %d, %w", pos, err)
+		}
+
+		idx := bytes.IndexByte(buf[:n], '\n')
+
+		if idx != -1 {
+			return pos + int64(idx) + 1, nil
+		}
+
+		pos += int64(n)
+	}
+
+	return sz, nil
+}
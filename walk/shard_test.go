@@ -0,0 +1,78 @@
+package walk
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestShardBoundaries(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "{\"n\":%d,\"padding\":\"%s\"}\n", i, bytes.Repeat([]byte("x"), i%97))
+	}
+
+	body := buf.Bytes()
+	sz := int64(len(body))
+
+	// Lines have different lengths so when there are (roughly) as many shards as lines some shards are empty
+	// and are skipped. There can never be more shards than lines.
+
+	tests := map[int]int{
+		1:    1,
+		2:    2,
+		7:    7,
+		1000: 0,
+		5000: 0,
+	}
+
+	for count, expected := range tests {
+
+		boundaries, err := ShardBoundaries(bytes.NewReader(body), sz, count)
+
+		if err != nil {
+			t.Fatalf("Failed to derive %d shards, %v", count, err)
+		}
+
+		shards := len(boundaries) - 1
+
+		if shards > min(count, 1000) || (expected != 0 && shards != expected) {
+			t.Fatalf("Unexpected number of shards for %d: %d", count, shards)
+		}
+
+		if boundaries[0] != 0 || boundaries[len(boundaries)-1] != sz {
+			t.Fatalf("Unexpected first or last boundary for %d: %v", count, boundaries)
+		}
+
+		for i := 1; i < len(boundaries); i++ {
+
+			if boundaries[i] <= boundaries[i-1] {
+				t.Fatalf("Boundaries for %d are not increasing: %v", count, boundaries)
+			}
+
+			if body[boundaries[i]-1] != '\n' {
+				t.Fatalf("Boundary %d for %d is not the start of a line", boundaries[i], count)
+			}
+		}
+	}
+
+	// A single line can not be split
+
+	boundaries, err := ShardBoundaries(bytes.NewReader([]byte(`{"n":1}`)), 7, 4)
+
+	if err != nil {
+		t.Fatalf("Failed to derive shards for single line, %v", err)
+	}
+
+	if fmt.Sprintf("%v", boundaries) != "[0 7]" {
+		t.Fatalf("Unexpected boundaries for single line: %v", boundaries)
+	}
+
+	_, err = ShardBoundaries(bytes.NewReader(body), sz, 0)
+
+	if err == nil {
+		t.Fatalf("Expected zero shards to fail")
+	}
+}
@@ -0,0 +1,58 @@
+package walk
+
+import (
+	"time"
+
+	"github.com/sfomuseum/go-libraryofcongress/internal/datetime"
+	"github.com/tidwall/gjson"
+)
+
+// ParseSince() parses 'str' in to a `time.Time` instance. 'str' is expected to be a date ("2006-01-02"),
+// a date and time ("2006-01-02T15:04:05") or an RFC3339 timestamp. It uses the same layouts, from the internal
+// `datetime` package, as the `authority` package uses to parse the `cs:createdDate` and `ri:recordChangeDate` values
+// in LoC data files.
+func ParseSince(str string) (time.Time, error) {
+	return datetime.Parse(str)
+}
+
+// ChangedSince() returns a boolean value indicating whether any of the change sets (`cs:createdDate`) or
+// record info (`ri:recordChangeDate`) nodes in the `@graph` of 'body' have a date after 't'. This is a
+// lightweight check, which does not decode the entire record, used to filter records before they are
+// dispatched to a `WalkCallbackFunction`.
+func ChangedSince(body []byte, t time.Time) bool {
+
+	changed := false
+
+	gjson.GetBytes(body, "@graph").ForEach(func(_, n gjson.Result) bool {
+
+		for _, path := range []string{"cs:createdDate", "ri:recordChangeDate"} {
+
+			rsp := n.Get(path)
+
+			if !rsp.Exists() {
+				continue
+			}
+
+			str := rsp.String()
+
+			if rsp.IsObject() {
+				str = rsp.Get("@value").String()
+			}
+
+			d, err := ParseSince(str)
+
+			if err != nil {
+				continue
+			}
+
+			if d.After(t) {
+				changed = true
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return changed
+}
@@ -0,0 +1,47 @@
+package walk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+
+	tests := map[string]time.Time{
+		"2011-06-04":           time.Date(2011, 6, 4, 0, 0, 0, 0, time.UTC),
+		"2011-06-04T08:27:18":  time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+		"2011-06-04T08:27:18Z": time.Date(2011, 6, 4, 8, 27, 18, 0, time.UTC),
+	}
+
+	for str, expected := range tests {
+
+		d, err := ParseSince(str)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", str, err)
+		}
+
+		if !d.Equal(expected) {
+			t.Fatalf("Unexpected date for '%s': %v", str, d)
+		}
+	}
+
+	_, err := ParseSince("June 4th")
+
+	if err == nil {
+		t.Fatalf("Expected invalid date to fail")
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+
+	body := []byte(`{"@graph": [{"@id": "_:N1", "@type": "cs:ChangeSet", "cs:changeReason": "revised", "cs:createdDate": {"@type": "xsd:dateTime", "@value": "2011-06-04T08:27:18"}}]}`)
+
+	if !ChangedSince(body, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected record to have changed since 2010")
+	}
+
+	if ChangedSince(body, time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Did not expect record to have changed since 2012")
+	}
+}
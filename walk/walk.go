@@ -13,6 +13,15 @@ import (
 )
 
 // type WalkCallbackFunction defines a user-specified callback function for processing a LoC data file.
+//
+// Unless a `Walker` implementation is explicitly configured otherwise callback functions are invoked sequentially, from a
+// single goroutine, and do not need to be safe for concurrent use. The `NDJSONWalker` implementation will invoke callback
+// functions concurrently if its `?callback_workers=` or `?shards=` parameters are greater than 1, in which case any state shared between
+// invocations (writers, maps, counters) must be protected by a lock. Applications which call a `Walker` from multiple goroutines
+// themselves are responsible for the safety of their own callback functions. The context passed to a callback function
+// contains the provenance of the record (see `ProvenanceFromContext`). When callback functions are invoked concurrently the
+// context is cancelled as soon as one of them returns an error, so long-running callback functions should check it. The body
+// passed to a callback function is not reused by the `Walker` and may be retained.
 type WalkCallbackFunction func(context.Context, []byte) error
 
 // type Walker defines an interface for iterating (walking) LoC data files from a variety or sources.